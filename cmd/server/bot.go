@@ -0,0 +1,109 @@
+package main
+
+import (
+	"cashout/internal/client"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/sirupsen/logrus"
+)
+
+// startBot starts receiving the bot updates in the RUN_MODE set in the environment
+func startBot(logger *logrus.Logger, c *client.Client, b *gotgbot.Bot) (*ext.Updater, error) {
+	// Create updater and dispatcher.
+	dispatcher := ext.NewDispatcher(&ext.DispatcherOpts{
+		Error: func(b *gotgbot.Bot, ctx *ext.Context, err error) ext.DispatcherAction {
+			if errors.Is(err, client.ErrNotAllowed) {
+				logger.Infof("unauthorized update: %s\n", err.Error())
+				if err := c.SendNotAllowed(b, ctx); err != nil {
+					logger.Errorf("failed to reply to an unauthorized user: %s\n", err.Error())
+				}
+				return ext.DispatcherActionNoop
+			}
+			logger.Errorf("an error occurred while handling update: %s\n", err.Error())
+			return ext.DispatcherActionNoop
+		},
+		MaxRoutines: ext.DefaultMaxRoutines,
+	})
+
+	updater := ext.NewUpdater(dispatcher, nil)
+
+	client.SetupHandlers(dispatcher, c)
+
+	if err := c.SetupMenuButton(b); err != nil {
+		logger.Warnf("Failed to set the dashboard menu button: %s\n", err.Error())
+	}
+
+	runMode := strings.ToLower(os.Getenv("RUN_MODE"))
+
+	switch runMode {
+	case "polling":
+		// Start receiving updates.
+		err := updater.StartPolling(b, &ext.PollingOpts{
+			DropPendingUpdates: true,
+			GetUpdatesOpts: &gotgbot.GetUpdatesOpts{
+				Timeout: 9,
+				RequestOpts: &gotgbot.RequestOpts{
+					Timeout: time.Second * 10,
+				},
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to start polling: %w", err)
+		}
+	case "webhook":
+		webhookDomain := os.Getenv("WEBHOOK_DOMAIN")
+		if webhookDomain == "" {
+			return nil, fmt.Errorf("WEBHOOK_DOMAIN environment variable is empty")
+		}
+
+		webhookSecret := os.Getenv("WEBHOOK_SECRET")
+		if webhookSecret == "" {
+			return nil, fmt.Errorf("WEBHOOK_SECRET environment variable is empty")
+		}
+
+		webhookHost := os.Getenv("WEBHOOK_HOST")
+		if webhookHost == "" {
+			webhookHost = "0.0.0.0"
+		}
+
+		webhookPort := os.Getenv("WEBHOOK_PORT")
+		if webhookPort == "" {
+			webhookPort = "8080"
+		}
+
+		// Start the webhook server, but before start the server so we're ready when Telegram starts sending updates.
+		webhookOpts := ext.WebhookOpts{
+			ListenAddr:  webhookHost + ":" + webhookPort,
+			SecretToken: webhookSecret,
+		}
+
+		// The bot's urlPath can be anything.
+		// It's a good idea to contain the bot token, as that makes it very difficult for outside
+		// parties to find the update endpoint (which would allow them to inject their own updates).
+		err := updater.StartWebhook(b, "cashout/"+b.Token, webhookOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start webhook: %w", err)
+		}
+
+		err = updater.SetAllBotWebhooks(webhookDomain, &gotgbot.SetWebhookOpts{
+			MaxConnections:     100,
+			DropPendingUpdates: true,
+			SecretToken:        webhookOpts.SecretToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set webhook: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown run mode: %s", runMode)
+	}
+
+	logger.Infof("%s has been started in %s mode...\n", b.Username, runMode)
+
+	return updater, nil
+}
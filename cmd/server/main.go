@@ -6,24 +6,63 @@ import (
 	"cashout/internal/db"
 	"cashout/internal/logging"
 	"cashout/internal/scheduler"
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 	"time"
+	_ "time/tzdata" // Embed the time zones of the users, in case the system has none
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"github.com/joho/godotenv"
-
-	_ "github.com/go-co-op/gocron" // Add to go.mod
 )
 
-// This bot demonstrates some example interactions with commands ontelegram.
-// It has a basic start command with a bot intro.
-// It also has a source command, which sends the bot sourcecode, as a file.
+// shutdownTimeout is how long the running services get to finish their work on SIGTERM
+const shutdownTimeout = 15 * time.Second
+
+const usage = `Usage: cashout [serve] [--bot] [--web] [--scheduler]
+
+Runs the Cashout services in a single process, sharing the database, bot and LLM.
+With no flag, all of them are started.
+
+Flags:
+`
+
+// Cashout runs the Telegram bot, the web server and the reminder scheduler,
+// together or one at a time with the serve flags.
 func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "serve" {
+		args = args[1:]
+	}
+
+	var runBot, runWeb, runScheduler bool
+
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.BoolVar(&runBot, "bot", false, "Run the Telegram bot")
+	flags.BoolVar(&runWeb, "web", false, "Run the web server")
+	flags.BoolVar(&runScheduler, "scheduler", false, "Run the reminder scheduler")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args) // Exits on error
+
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if !runBot && !runWeb && !runScheduler {
+		runBot, runWeb, runScheduler = true, true, true
+	}
+
 	err := godotenv.Load()
 	if err != nil {
 		log.Fatal("Error loading .env file")
@@ -51,108 +90,95 @@ func main() {
 		logger.Fatalln("DATABASE_URL environment variable is empty")
 	}
 
-	db, err := db.NewDB(postgresURL)
+	database, err := db.NewDB(postgresURL)
 	if err != nil {
 		logger.Fatalf("Failed to initialize database: %s\n", err.Error())
 	}
 
 	defer func() {
-		err = errors.Join(err, db.Close())
+		if err := database.Close(); err != nil {
+			logger.Errorf("Failed to close database: %s\n", err.Error())
+		}
 	}()
 
 	// Initialize client
-	c := client.NewClient(logger, db, llm)
+	c := client.NewClient(logger, database, llm)
 
-	// Create bot from environment value.
+	// Create bot from environment value, the web server and the scheduler also use it to send messages
 	b, err := gotgbot.NewBot(token, nil)
 	if err != nil {
 		logger.Fatalf("failed to create new bot: %s\n", err.Error())
 	}
 
-	// Create updater and dispatcher.
-	dispatcher := ext.NewDispatcher(&ext.DispatcherOpts{
-		Error: func(b *gotgbot.Bot, ctx *ext.Context, err error) ext.DispatcherAction {
-			logger.Errorf("an error occurred while handling update: %s\n", err.Error())
-			return ext.DispatcherActionNoop
-		},
-		MaxRoutines: ext.DefaultMaxRoutines,
-	})
-
-	updater := ext.NewUpdater(dispatcher, nil)
-
-	client.SetupHandlers(dispatcher, c)
-
-	runMode := strings.ToLower(os.Getenv("RUN_MODE"))
-
-	switch runMode {
-	case "polling":
-		// Start receiving updates.
-		err = updater.StartPolling(b, &ext.PollingOpts{
-			DropPendingUpdates: true,
-			GetUpdatesOpts: &gotgbot.GetUpdatesOpts{
-				Timeout: 9,
-				RequestOpts: &gotgbot.RequestOpts{
-					Timeout: time.Second * 10,
-				},
-			},
-		})
+	// Stop everything on Ctrl+C or when the container is stopped
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var updater *ext.Updater
+	if runBot {
+		updater, err = startBot(logger, c, b)
 		if err != nil {
-			logger.Fatalf("failed to start polling: %s\n", err.Error())
-		}
-	case "webhook":
-		webhookDomain := os.Getenv("WEBHOOK_DOMAIN")
-		if webhookDomain == "" {
-			logger.Fatalln("WEBHOOK_DOMAIN environment variable is empty")
+			logger.Fatalf("Failed to start bot: %s\n", err.Error())
 		}
+	}
 
-		webhookSecret := os.Getenv("WEBHOOK_SECRET")
-		if webhookSecret == "" {
-			logger.Fatalln("WEBHOOK_SECRET environment variable is empty")
+	// The web server stops the process when it fails, like the signals
+	webErr := make(chan error, 1)
+	var webServer *http.Server
+	if runWeb {
+		webServer, err = newWebServer(logger, database, b, llm)
+		if err != nil {
+			logger.Fatalf("Failed to initialize web server: %s\n", err.Error())
 		}
 
-		webhookHost := os.Getenv("WEBHOOK_HOST")
-		if webhookHost == "" {
-			webhookHost = "0.0.0.0"
-		}
+		go func() {
+			logger.Infof("Starting web server on %s", webServer.Addr)
+			if err := webServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				webErr <- err
+			}
+		}()
+	}
 
-		webhookPort := os.Getenv("WEBHOOK_PORT")
-		if webhookPort == "" {
-			webhookPort = "8080"
-		}
+	// Initialize scheduler for automated reminders
+	var sched *scheduler.Scheduler
+	if runScheduler {
+		sched = scheduler.NewScheduler(b, scheduler.Repositories{
+			Users:        c.Repositories.Users,
+			Transactions: c.Repositories.Transactions,
+			Reminders:    c.Repositories.Reminders,
+			Anomalies:    c.Repositories.Anomalies,
+			Bills:        c.Repositories.Bills,
+			Admin:        c.Repositories.Admin,
+		}, logger)
+		sched.Start()
+	}
 
-		// Start the webhook server, but before start the server so we're ready when Telegram starts sending updates.
-		webhookOpts := ext.WebhookOpts{
-			ListenAddr:  webhookHost + ":" + webhookPort,
-			SecretToken: webhookSecret,
-		}
+	select {
+	case <-ctx.Done():
+		logger.Info("Shutting down...")
+	case err := <-webErr:
+		logger.Errorf("Web server failed: %s", err.Error())
+	}
 
-		// The bot's urlPath can be anything.
-		// It's a good idea to contain the bot token, as that makes it very difficult for outside
-		// parties to find the update endpoint (which would allow them to inject their own updates).
-		err = updater.StartWebhook(b, "cashout/"+token, webhookOpts)
-		if err != nil {
-			panic("failed to start webhook: " + err.Error())
-		}
+	// Stop taking new updates and requests first, then let the running jobs finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
 
-		err = updater.SetAllBotWebhooks(webhookDomain, &gotgbot.SetWebhookOpts{
-			MaxConnections:     100,
-			DropPendingUpdates: true,
-			SecretToken:        webhookOpts.SecretToken,
-		})
-		if err != nil {
-			panic("failed to set webhook: " + err.Error())
+	if webServer != nil {
+		if err := webServer.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("Failed to stop web server: %s", err.Error())
 		}
-	default:
-		logger.Fatalf("unknown run mode: %s\n", runMode)
 	}
 
-	logger.Infof("%s has been started in %s mode...\n", b.Username, runMode)
+	if updater != nil {
+		if err := updater.Stop(); err != nil {
+			logger.Errorf("Failed to stop bot: %s", err.Error())
+		}
+	}
 
-	// Initialize scheduler for automated reminders
-	sched := scheduler.NewScheduler(b, c.Repositories, logger)
-	sched.Start()
-	defer sched.Stop()
+	if sched != nil {
+		sched.Stop()
+	}
 
-	// Idle, to keep updates coming in, and avoid bot stopping.
-	updater.Idle()
+	logger.Info("Stopped")
 }
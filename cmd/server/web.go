@@ -0,0 +1,52 @@
+package main
+
+import (
+	"cashout/internal/ai"
+	"cashout/internal/db"
+	"cashout/internal/repository"
+	"cashout/internal/web"
+	"fmt"
+	"net/http"
+	"os"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// newWebServer builds the HTTP server of the web dashboard and API, listening on WEB_HOST:WEB_PORT
+func newWebServer(logger *logrus.Logger, database *db.DB, bot *gotgbot.Bot, llm ai.LLM) (*http.Server, error) {
+	// For repositories structs embedding common fields
+	repo := repository.Repository{
+		DB:     database,
+		Logger: logger,
+	}
+
+	repositories := web.Repositories{
+		Users:        repository.Users{Repository: repo},
+		Transactions: repository.Transactions{Repository: repo},
+		Auth:         repository.Auth{Repository: repo},
+		APITokens:    repository.APITokens{Repository: repo},
+		AllowedUsers: repository.AllowedUsers{Repository: repo, Access: repository.NewAccessFromEnv(logger)},
+	}
+
+	webServer, err := web.NewServer(logger, repositories, bot, llm)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get web server configuration
+	webHost := os.Getenv("WEB_HOST")
+	if webHost == "" {
+		webHost = "localhost"
+	}
+
+	webPort := os.Getenv("WEB_PORT")
+	if webPort == "" {
+		webPort = "8081"
+	}
+
+	return &http.Server{
+		Addr:    fmt.Sprintf("%s:%s", webHost, webPort),
+		Handler: web.Router(webServer),
+	}, nil
+}
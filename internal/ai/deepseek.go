@@ -29,7 +29,8 @@ type ExtractedTransaction struct {
 	Date        time.Time
 }
 
-func (llm *LLM) ExtractTransaction(userText string, transactionType model.TransactionType) (ExtractedTransaction, error) {
+// ExtractTransaction parses a transaction from the user's text, dated today unless the text says otherwise
+func (llm *LLM) ExtractTransaction(userText string, transactionType model.TransactionType, today time.Time) (ExtractedTransaction, error) {
 	transaction := ExtractedTransaction{
 		Type: transactionType,
 	}
@@ -154,11 +155,11 @@ func (llm *LLM) ExtractTransaction(userText string, transactionType model.Transa
 		transaction.Category = category
 	}
 
-	transaction.Date = time.Now()
+	transaction.Date = today
 	if date, ok := transactionData["date"].(string); ok {
-		transaction.Date, err = utils.ParseDate(date)
+		transaction.Date, err = utils.ParseDateInYear(date, today.Year())
 		if err != nil {
-			transaction.Date = time.Now()
+			transaction.Date = today
 		}
 	}
 
@@ -0,0 +1,434 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/repository"
+	"cashout/internal/utils"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+const (
+	// Users and failed reminders listed at once, to stay within the length of a message
+	adminListLimit = 15
+
+	adminHelp = "<code>/admin users</code> - Users and their activity\n" +
+		"<code>/admin allowed</code> - Users allowed at runtime\n" +
+		"<code>/admin allow USERNAME|ID</code> - Allow a user by username or Telegram ID\n" +
+		"<code>/admin disallow USERNAME|ID</code> - Remove an allowed user\n" +
+		"<code>/admin invite [USES]</code> - Create an invite link, for one user unless given\n" +
+		"<code>/admin invites</code> - Invite codes that can still be used\n" +
+		"<code>/admin reminders</code> - Failed reminders\n" +
+		"<code>/admin broadcast TEXT</code> - Send an announcement to all users"
+)
+
+// Admin handles the /admin command, for the admins of the deployment only: an overview of the users and reminders.
+// "/admin users|allowed|reminders|invites" lists them, "/admin allow|disallow USERNAME|ID" changes the users allowed
+// at runtime, "/admin invite [USES]" creates an invite link and "/admin broadcast TEXT" sends an announcement
+// to all users, after confirming it.
+func (c *Client) Admin(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+	if !c.IsAdmin(user.TgID) {
+		return fmt.Errorf("user %d is not an admin", user.TgID)
+	}
+
+	user.Session.State = model.StateNormal
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	parts := strings.Fields(ctx.Message.Text)
+	if len(parts) < 2 {
+		return c.showAdmin(b, ctx, "")
+	}
+
+	switch strings.ToLower(parts[1]) {
+	case "users":
+		return c.showAdminUsers(b, ctx)
+	case "allowed":
+		return c.showAllowedUsers(b, ctx, "")
+	case "allow", "disallow":
+		if len(parts) != 3 {
+			return SendMessage(ctx, b, "⚠️ Give the Telegram username or ID, e.g. <code>/admin allow johndoe</code>.", nil)
+		}
+		return c.changeAllowedUser(b, ctx, user, strings.ToLower(parts[1]) == "allow", parts[2])
+	case "reminders":
+		return c.showFailedReminders(b, ctx)
+	case "invite":
+		uses := 1
+		if len(parts) > 2 {
+			uses, err = strconv.Atoi(parts[2])
+			if err != nil {
+				return SendMessage(ctx, b, "⚠️ Give how many users can use the invite, e.g. <code>/admin invite 5</code>.", nil)
+			}
+		}
+		return c.createInvite(b, ctx, user, uses)
+	case "invites":
+		return c.showInvites(b, ctx, "")
+	case "broadcast":
+		// The rest of the message as it was written, new lines included
+		message := strings.TrimSpace(ctx.Message.Text[strings.Index(ctx.Message.Text, parts[1])+len(parts[1]):])
+		return c.confirmBroadcast(b, ctx, user, message)
+	default:
+		return c.showAdmin(b, ctx, "")
+	}
+}
+
+// AdminSelected handles the admin buttons (format: admin.users, admin.allowed, admin.reminders, admin.invites,
+// admin.disallow.ALLOWED_USER_ID, admin.revoke.INVITE_ID, admin.broadcast.confirm, admin.broadcast.cancel or admin.home)
+func (c *Client) AdminSelected(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+	if !c.IsAdmin(user.TgID) {
+		return fmt.Errorf("user %d is not an admin", user.TgID)
+	}
+
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	switch {
+	case parts[1] == "users":
+		return c.showAdminUsers(b, ctx)
+	case parts[1] == "allowed":
+		return c.showAllowedUsers(b, ctx, "")
+	case parts[1] == "reminders":
+		return c.showFailedReminders(b, ctx)
+	case parts[1] == "invites":
+		return c.showInvites(b, ctx, "")
+	case parts[1] == "disallow" && len(parts) == 3:
+		id, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid allowed user ID: %v", err)
+		}
+		if _, err := c.Repositories.AllowedUsers.RemoveByID(id); err != nil {
+			return fmt.Errorf("failed to remove allowed user: %w", err)
+		}
+		return c.showAllowedUsers(b, ctx, "🗑 User removed, they can't use the bot or the dashboard anymore.")
+	case parts[1] == "revoke" && len(parts) == 3:
+		id, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid invite ID: %v", err)
+		}
+		if _, err := c.Repositories.Invites.Revoke(id, time.Now()); err != nil {
+			return fmt.Errorf("failed to revoke invite: %w", err)
+		}
+		return c.showInvites(b, ctx, "🗑 Invite revoked.")
+	case parts[1] == "broadcast" && len(parts) == 3:
+		return c.sendBroadcast(b, ctx, user, parts[2] == "confirm")
+	default:
+		return c.showAdmin(b, ctx, "")
+	}
+}
+
+// showAdmin shows the overview of the users and reminders, after an optional notice
+func (c *Client) showAdmin(b *gotgbot.Bot, ctx *ext.Context, notice string) error {
+	summary, err := c.Repositories.Admin.UsersSummary(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get users summary: %w", err)
+	}
+	counts, err := c.Repositories.Admin.ReminderStatusCounts()
+	if err != nil {
+		return fmt.Errorf("failed to get reminder counts: %w", err)
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		text.WriteString(notice + "\n\n")
+	}
+	text.WriteString("🛠 <b>Admin</b>\n\n")
+	text.WriteString(fmt.Sprintf("👥 <b>%d users</b>, %d active in the last 30 days, %d blocked the bot\n\n",
+		summary.Total, summary.Active, summary.Blocked))
+
+	text.WriteString("🔔 <b>Reminders</b>\n")
+	if len(counts) == 0 {
+		text.WriteString("Nothing queued or failed\n")
+	}
+	for _, count := range counts {
+		text.WriteString(fmt.Sprintf("%s %s: %d\n", count.Type, count.Status, count.Count))
+	}
+
+	text.WriteString("\n" + adminHelp)
+
+	return SendMessage(ctx, b, text.String(), adminKeyboard())
+}
+
+// showAdminUsers lists the most recently active users with their transactions
+func (c *Client) showAdminUsers(b *gotgbot.Bot, ctx *ext.Context) error {
+	users, err := c.Repositories.Admin.UsersActivity(time.Now(), adminListLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get users activity: %w", err)
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("👥 <b>Users</b>, the last %d active\n\n", adminListLimit))
+	for _, u := range users {
+		text.WriteString(fmt.Sprintf("• <b>%s</b>", html.EscapeString(u.Name)))
+		if u.TgUsername != "" {
+			text.WriteString(" @" + html.EscapeString(u.TgUsername))
+		}
+		text.WriteString(fmt.Sprintf(" <code>%d</code>\n", u.TgID))
+
+		text.WriteString(fmt.Sprintf("   %d transactions, %d in the last 30 days", u.Transactions, u.RecentTransactions))
+		if u.LastTransactionAt != nil {
+			text.WriteString(", last on " + u.LastTransactionAt.Format("02-01-2006"))
+		}
+		text.WriteString(", joined " + u.CreatedAt.Format("02-01-2006"))
+		if u.BotBlockedAt != nil {
+			text.WriteString(", 🚫 blocked the bot")
+		}
+		text.WriteString("\n")
+	}
+
+	return SendMessage(ctx, b, text.String(), adminKeyboard())
+}
+
+// showAllowedUsers lists the users allowed by the environment and at runtime, after an optional notice
+func (c *Client) showAllowedUsers(b *gotgbot.Bot, ctx *ext.Context, notice string) error {
+	allowed, err := c.Repositories.AllowedUsers.List()
+	if err != nil {
+		return fmt.Errorf("failed to get allowed users: %w", err)
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		text.WriteString(notice + "\n\n")
+	}
+	text.WriteString("✅ <b>Allowed users</b>\n\n")
+
+	access := c.Repositories.AllowedUsers.Access
+	if len(access.Usernames) > 0 || len(access.TgIDs) > 0 {
+		var users []string
+		for username := range access.Usernames {
+			users = append(users, "@"+html.EscapeString(username))
+		}
+		for tgID := range access.TgIDs {
+			users = append(users, fmt.Sprintf("ID %d", tgID))
+		}
+		text.WriteString(fmt.Sprintf("From ALLOWED_USERS: %s\n\n", strings.Join(users, ", ")))
+	}
+
+	if len(allowed) == 0 {
+		text.WriteString("No users allowed at runtime yet.\n")
+	}
+
+	var keyboard [][]gotgbot.InlineKeyboardButton
+	for _, a := range allowed {
+		text.WriteString(fmt.Sprintf("• %s, since %s", html.EscapeString(a.Label()), a.CreatedAt.Format("02-01-2006")))
+		if a.InviteCodeID != nil {
+			text.WriteString(", invited")
+		}
+		text.WriteString("\n")
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: fmt.Sprintf("🗑 Remove %s", a.Label()), CallbackData: fmt.Sprintf("admin.disallow.%d", a.ID)},
+		})
+	}
+	text.WriteString("\nAllow a user with <code>/admin allow USERNAME|ID</code> or invite them with <code>/admin invite</code>.")
+
+	keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{{Text: "⬅️ Back", CallbackData: "admin.home"}})
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
+
+// changeAllowedUser allows a username or Telegram ID at runtime or removes it
+func (c *Client) changeAllowedUser(b *gotgbot.Bot, ctx *ext.Context, user model.User, allow bool, username string) error {
+	label := "@" + model.NormalizeUsername(username)
+	if tgID, err := strconv.ParseInt(username, 10, 64); err == nil {
+		label = fmt.Sprintf("ID %d", tgID)
+	}
+
+	if !allow {
+		removed, err := c.Repositories.AllowedUsers.Remove(username)
+		if err != nil {
+			return fmt.Errorf("failed to remove allowed user: %w", err)
+		}
+		notice := fmt.Sprintf("🗑 %s can't use the bot or the dashboard anymore.", html.EscapeString(label))
+		if !removed {
+			notice = fmt.Sprintf("⚠️ %s wasn't allowed at runtime, users in ALLOWED_USERS can only be removed there.", html.EscapeString(label))
+		}
+		return c.showAllowedUsers(b, ctx, notice)
+	}
+
+	added, err := c.Repositories.AllowedUsers.Add(username, user.TgID)
+	if errors.Is(err, repository.ErrInvalidUsername) {
+		return SendMessage(ctx, b, "⚠️ Give a valid Telegram username or ID, e.g. <code>/admin allow johndoe</code>.", nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add allowed user: %w", err)
+	}
+
+	notice := fmt.Sprintf("✅ %s can now use the bot.", html.EscapeString(label))
+	if !added {
+		notice = fmt.Sprintf("✅ %s was already allowed.", html.EscapeString(label))
+	}
+	return c.showAllowedUsers(b, ctx, notice)
+}
+
+// showFailedReminders lists the latest reminders waiting for a retry or given up on
+func (c *Client) showFailedReminders(b *gotgbot.Bot, ctx *ext.Context) error {
+	reminders, err := c.Repositories.Admin.FailedReminders(adminListLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get failed reminders: %w", err)
+	}
+
+	var text strings.Builder
+	text.WriteString("⚠️ <b>Failed reminders</b>\n\n")
+	if len(reminders) == 0 {
+		text.WriteString("No failed reminders.\n")
+	}
+	for _, r := range reminders {
+		status := "given up"
+		if r.Status == model.ReminderStatusFailed && r.NextAttemptAt != nil {
+			status = "retrying at " + r.NextAttemptAt.UTC().Format("02-01 15:04 MST")
+		}
+		text.WriteString(fmt.Sprintf("• %s to <code>%d</code>, %s after %d attempt(s), scheduled %s\n",
+			r.Type, r.TgID, status, r.Attempts, r.ScheduledFor.UTC().Format("02-01-2006 15:04 MST")))
+		if r.ErrorMessage != nil {
+			text.WriteString(fmt.Sprintf("   <i>%s</i>\n", html.EscapeString(truncate(*r.ErrorMessage, 120))))
+		}
+	}
+
+	return SendMessage(ctx, b, text.String(), adminKeyboard())
+}
+
+// createInvite creates an invite code for a number of users and shows its link
+func (c *Client) createInvite(b *gotgbot.Bot, ctx *ext.Context, user model.User, uses int) error {
+	invite, err := c.Repositories.Invites.Create(user.TgID, uses, time.Now())
+	if errors.Is(err, repository.ErrInvalidInviteUses) {
+		return SendMessage(ctx, b, fmt.Sprintf("⚠️ An invite can be used by 1 to %d users.", repository.MaxInviteUses), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+
+	text := fmt.Sprintf("🎟 <b>Invite created</b>\n\nShare this link, it lets %d user(s) in until %s:\n%s\n\nThey can also send <code>/start %s</code> to the bot.",
+		invite.MaxUses, invite.ExpiresAt.Format("02-01-2006 15:04 MST"), inviteLink(b, invite.Code), invite.Code)
+	return SendMessage(ctx, b, text, adminKeyboard())
+}
+
+// showInvites lists the invite codes that can still be redeemed, after an optional notice
+func (c *Client) showInvites(b *gotgbot.Bot, ctx *ext.Context, notice string) error {
+	invites, err := c.Repositories.Invites.Active(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to get invites: %w", err)
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		text.WriteString(notice + "\n\n")
+	}
+	text.WriteString("🎟 <b>Invites</b>\n\n")
+	if len(invites) == 0 {
+		text.WriteString("No invites can be used, create one with <code>/admin invite</code>.\n")
+	}
+
+	var keyboard [][]gotgbot.InlineKeyboardButton
+	for _, invite := range invites {
+		text.WriteString(fmt.Sprintf("• <code>%s</code>, used %d of %d", invite.Code, invite.Uses, invite.MaxUses))
+		if invite.ExpiresAt != nil {
+			text.WriteString(", until " + invite.ExpiresAt.Format("02-01-2006"))
+		}
+		text.WriteString("\n   " + inviteLink(b, invite.Code) + "\n")
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: fmt.Sprintf("🗑 Revoke %s", invite.Code), CallbackData: fmt.Sprintf("admin.revoke.%d", invite.ID)},
+		})
+	}
+
+	keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{{Text: "⬅️ Back", CallbackData: "admin.home"}})
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
+
+// inviteLink is the deep link starting the bot with an invite code
+func inviteLink(b *gotgbot.Bot, code string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s", b.Username, code)
+}
+
+// confirmBroadcast shows how an announcement looks and asks to confirm it before sending it to all users
+func (c *Client) confirmBroadcast(b *gotgbot.Bot, ctx *ext.Context, user model.User, message string) error {
+	if message == "" {
+		return SendMessage(ctx, b, "⚠️ Write the announcement after the command, e.g. <code>/admin broadcast New feature: /bills!</code>", nil)
+	}
+
+	user.Session.State = model.StateConfirmingBroadcast
+	user.Session.Body = message
+	if err := c.Repositories.Users.Update(&user); err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	text := "📣 <b>This announcement will be sent to all users:</b>\n\n" + utils.FormatBroadcast(message)
+	keyboard := [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: "📣 Send", CallbackData: "admin.broadcast.confirm"},
+			{Text: "❌ Cancel", CallbackData: "admin.broadcast.cancel"},
+		},
+	}
+	return SendMessage(ctx, b, text, keyboard)
+}
+
+// sendBroadcast queues the announcement being confirmed, or drops it
+func (c *Client) sendBroadcast(b *gotgbot.Bot, ctx *ext.Context, user model.User, confirmed bool) error {
+	if user.Session.State != model.StateConfirmingBroadcast {
+		return SendMessage(ctx, b, "⚠️ This announcement was already sent or cancelled.", nil)
+	}
+
+	message := user.Session.Body
+	user.Session.State = model.StateNormal
+	user.Session.Body = ""
+	if err := c.Repositories.Users.Update(&user); err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	if !confirmed {
+		return SendMessage(ctx, b, "❌ Announcement cancelled.", nil)
+	}
+
+	broadcast, err := c.Repositories.Admin.Broadcast(message, user.TgID, time.Now())
+	if errors.Is(err, repository.ErrInvalidBroadcast) {
+		return SendMessage(ctx, b, "⚠️ "+err.Error()+".", nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to queue broadcast: %w", err)
+	}
+
+	return SendMessage(ctx, b, fmt.Sprintf("📣 Announcement queued for %d users, it's sent in the next minutes.", broadcast.Recipients), nil)
+}
+
+// adminKeyboard links the admin views
+func adminKeyboard() [][]gotgbot.InlineKeyboardButton {
+	return [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: "👥 Users", CallbackData: "admin.users"},
+			{Text: "✅ Allowed", CallbackData: "admin.allowed"},
+			{Text: "⚠️ Reminders", CallbackData: "admin.reminders"},
+		},
+		{
+			{Text: "🎟 Invites", CallbackData: "admin.invites"},
+		},
+	}
+}
+
+// truncate shortens a text to a number of characters
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length-1]) + "…"
+}
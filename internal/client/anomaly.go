@@ -0,0 +1,91 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+// checkTransactionAnomalies looks for anything unusual about a just saved transaction and notifies the user
+func (c *Client) checkTransactionAnomalies(b *gotgbot.Bot, user model.User, transaction model.Transaction) error {
+	now := user.Now()
+	history, err := c.Repositories.Transactions.GetUserTransactionsByDateRange(transaction.TgID, now.AddDate(0, 0, -utils.AnomalyHistoryDays), now)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	anomalies := utils.DetectTransactionAnomalies(transaction, history)
+	for _, spike := range utils.DetectCategorySpikes(now, history) {
+		if spike.Category == transaction.Category {
+			spike.TgID = transaction.TgID
+			anomalies = append(anomalies, spike)
+		}
+	}
+
+	for _, anomaly := range anomalies {
+		if err := c.notifyAnomaly(b, anomaly); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// notifyAnomaly records the anomaly and, unless the user already heard about it, sends a notice
+// with buttons to dismiss it or to edit or delete the transaction
+func (c *Client) notifyAnomaly(b *gotgbot.Bot, anomaly model.Anomaly) error {
+	created, err := c.Repositories.Anomalies.Create(&anomaly)
+	if err != nil {
+		return fmt.Errorf("failed to save anomaly: %w", err)
+	}
+	if !created {
+		return nil
+	}
+
+	_, err = b.SendMessage(anomaly.TgID, utils.FormatAnomaly(anomaly), &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{
+			InlineKeyboard: utils.AnomalyKeyboard(anomaly),
+		},
+	})
+	return err
+}
+
+// AnomalyDismiss handles the "that's fine" button of an anomaly notice
+func (c *Client) AnomalyDismiss(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	// Parse callback data (format: anomaly.fine.ANOMALY_ID)
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	anomalyID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid anomaly ID: %v", err)
+	}
+
+	err = c.Repositories.Anomalies.Dismiss(anomalyID, user.TgID)
+	if err != nil {
+		return fmt.Errorf("failed to dismiss anomaly: %w", err)
+	}
+
+	_, _, err = ctx.CallbackQuery.Message.EditText(b, "👍 Got it, all good!", &gotgbot.EditMessageTextOpts{})
+	if err != nil {
+		return err
+	}
+
+	// Answer callback query to remove loading state
+	_, err = ctx.CallbackQuery.Answer(b, &gotgbot.AnswerCallbackQueryOpts{})
+	return err
+}
@@ -2,28 +2,37 @@ package client
 
 import (
 	"cashout/internal/model"
+	"errors"
 	"fmt"
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 )
 
+// ErrNotAllowed is returned for the users that can't use a private deployment of the bot
+var ErrNotAllowed = errors.New("user not allowed")
+
 // authAndGetUser authenticates the user and returns the user data.
 func (c *Client) authAndGetUser(user gotgbot.User) (model.User, error) {
-	if c.Config.AuthEnabled {
-		if _, ok := c.Config.AllowedUsers[user.Username]; !ok {
-			return model.User{}, fmt.Errorf("user %s is not allowed", user.Username)
-		}
+	allowed, err := c.Repositories.AllowedUsers.CanUse(user.Id, user.Username)
+	if err != nil {
+		return model.User{}, fmt.Errorf("failed to check allowed users: %w", err)
+	}
+	if !allowed {
+		return model.User{}, fmt.Errorf("%w: %d (%s)", ErrNotAllowed, user.Id, user.Username)
 	}
 
-	u, exists, err := c.Repositories.Users.GetByUsername(user.Username)
+	u, exists, err := c.Repositories.Users.FindByTgID(user.Id)
 	if err != nil {
 		return u, fmt.Errorf("failed to get user data: %w", err)
 	}
 
 	if exists {
-		err = c.Repositories.Users.Update(&u)
-		return u, err
+		// Only the profile columns are written, the scheduler may be updating the others
+		if err := c.Repositories.Users.RefreshProfile(&u, user); err != nil {
+			return u, fmt.Errorf("failed to refresh user profile: %w", err)
+		}
+		return u, nil
 	}
 
 	// First Message, user to be created.
@@ -35,7 +44,7 @@ func (c *Client) authAndGetUser(user gotgbot.User) (model.User, error) {
 		return u, fmt.Errorf("failed to set user data: %w", err)
 	}
 
-	u, _, err = c.Repositories.Users.GetByUsername(user.Username)
+	u, err = c.Repositories.Users.GetByTgID(user.Id)
 	if err != nil {
 		return u, fmt.Errorf("failed to get user data: %w", err)
 	}
@@ -43,6 +52,26 @@ func (c *Client) authAndGetUser(user gotgbot.User) (model.User, error) {
 	return u, nil
 }
 
+// SendNotAllowed politely tells a user they can't use the bot and how to get access
+func (c *Client) SendNotAllowed(b *gotgbot.Bot, ctx *ext.Context) error {
+	if ctx.EffectiveChat == nil {
+		return nil
+	}
+	if ctx.CallbackQuery != nil {
+		if _, err := ctx.CallbackQuery.Answer(b, nil); err != nil {
+			return err
+		}
+	}
+	text := fmt.Sprintf("🔒 Sorry, this bot is private.\n\nAsk the admin for an invite link, or give them your Telegram ID: <code>%d</code>.", ctx.EffectiveChat.Id)
+	_, err := b.SendMessage(ctx.EffectiveChat.Id, text, &gotgbot.SendMessageOpts{ParseMode: "HTML"})
+	return err
+}
+
+// IsAdmin checks whether the user is one of the admins of the deployment
+func (c *Client) IsAdmin(tgID int64) bool {
+	return c.Repositories.AllowedUsers.Access.IsAdmin(tgID)
+}
+
 func (c *Client) getUserFromContext(ctx *ext.Context) (isInline bool, user gotgbot.User) {
 	if ctx.CallbackQuery != nil {
 		return true, ctx.CallbackQuery.From
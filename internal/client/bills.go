@@ -0,0 +1,226 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/repository"
+	"cashout/internal/utils"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+const billsHelp = "Add one with <code>/bills add internet 29.90 every 5th</code> or " +
+	"<code>/bills add car insurance yearly on 12-03</code>. Leave out the amount if it varies, I'll ask it when you pay.\n" +
+	"I remind you 3 days before they're due, add e.g. <code>7 days before</code> to change it, " +
+	"and <code>in House</code> to file them in another category than Bills."
+
+// Bills handles the /bills command: it lists the user's bills with buttons to pay and delete them.
+// "/bills add SPEC" adds a bill, see utils.ParseBill.
+func (c *Client) Bills(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	user.Session.State = model.StateNormal
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	if ctx.Message != nil {
+		parts := strings.Fields(ctx.Message.Text)
+		if len(parts) > 1 && strings.ToLower(parts[1]) == "add" {
+			return c.addBill(b, ctx, user, strings.Join(parts[2:], " "))
+		}
+	}
+
+	return c.showBills(b, ctx, user, "")
+}
+
+// BillSelected handles the bill buttons (format: bills.paid.BILL_ID.DUE_DATE, bills.snooze.BILL_ID,
+// bills.delete.BILL_ID or bills.list)
+func (c *Client) BillSelected(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 2 || parts[1] == "list" {
+		return c.showBills(b, ctx, user, "")
+	}
+	if len(parts) < 3 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	billID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid bill ID: %v", err)
+	}
+	bill, err := c.Repositories.Bills.Get(billID, user.TgID)
+	if err != nil {
+		// Deleted in the meantime
+		return c.showBills(b, ctx, user, "⚠️ This bill doesn't exist anymore.")
+	}
+
+	switch parts[1] {
+	case "paid":
+		if len(parts) != 4 {
+			return fmt.Errorf("invalid callback data format")
+		}
+		dueDate, err := time.Parse(utils.BillDateLayout, parts[3])
+		if err != nil {
+			return fmt.Errorf("invalid due date: %v", err)
+		}
+		if bill.Amount > 0 {
+			return c.payBill(b, ctx, user, bill, dueDate, 0)
+		}
+
+		// The amount varies, ask it
+		user.Session.State = model.StateEnteringBillAmount
+		user.Session.Body = fmt.Sprintf("%d.%s", bill.ID, parts[3])
+		if err := c.Repositories.Users.Update(&user); err != nil {
+			return fmt.Errorf("failed to set user data: %w", err)
+		}
+		text := fmt.Sprintf("💶 How much did you pay for <b>%s</b>?\n\nSend the amount, or <i>cancel</i>.", html.EscapeString(bill.Name))
+		return SendMessage(ctx, b, text, nil)
+	case "snooze":
+		if _, err := c.Repositories.Bills.Snooze(bill, time.Now()); err != nil {
+			return fmt.Errorf("failed to snooze bill: %w", err)
+		}
+		text := fmt.Sprintf("⏰ I'll remind you about <b>%s</b> again tomorrow.", html.EscapeString(bill.Name))
+		return SendMessage(ctx, b, text, nil)
+	case "delete":
+		if err := c.Repositories.Bills.Delete(bill.ID, user.TgID); err != nil {
+			return fmt.Errorf("failed to delete bill: %w", err)
+		}
+		return c.showBills(b, ctx, user, fmt.Sprintf("🗑 <b>%s</b> deleted.", html.EscapeString(bill.Name)))
+	default:
+		return c.showBills(b, ctx, user, "")
+	}
+}
+
+// billAmountEntered pays the bill the user was asked the amount of
+func (c *Client) billAmountEntered(b *gotgbot.Bot, ctx *ext.Context, user model.User) error {
+	parts := strings.Split(user.Session.Body, ".")
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid bill in the session: %s", user.Session.Body)
+	}
+	billID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid bill ID in the session: %v", err)
+	}
+	dueDate, err := time.Parse(utils.BillDateLayout, parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid due date in the session: %v", err)
+	}
+
+	amountStr := strings.TrimSpace(ctx.Message.Text)
+	amountStr = strings.ReplaceAll(strings.TrimSuffix(amountStr, "€"), ",", ".")
+	amount, err := strconv.ParseFloat(amountStr, 64)
+	if err != nil || amount <= 0 {
+		return SendMessage(ctx, b, "Invalid amount. Please enter a number greater than zero, or <i>cancel</i>.", nil)
+	}
+
+	user.Session.State = model.StateNormal
+	user.Session.Body = ""
+	if err := c.Repositories.Users.Update(&user); err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	bill, err := c.Repositories.Bills.Get(billID, user.TgID)
+	if err != nil {
+		return c.showBills(b, ctx, user, "⚠️ This bill doesn't exist anymore.")
+	}
+
+	return c.payBill(b, ctx, user, bill, dueDate, amount)
+}
+
+// payBill records the payment of a bill due on a date, with its amount unless another one is given
+func (c *Client) payBill(b *gotgbot.Bot, ctx *ext.Context, user model.User, bill model.Bill, dueDate time.Time, amount float64) error {
+	transaction, err := c.Repositories.Bills.MarkPaid(user, bill, dueDate, amount)
+	if errors.Is(err, repository.ErrBillAlreadyPaid) {
+		text := fmt.Sprintf("✅ <b>%s</b> due on %s was already paid.", html.EscapeString(bill.Name), dueDate.Format("Mon 02 Jan"))
+		return SendMessage(ctx, b, text, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to pay bill: %w", err)
+	}
+
+	// Moved to the next due date
+	bill, err = c.Repositories.Bills.Get(bill.ID, user.TgID)
+	if err != nil {
+		return fmt.Errorf("failed to get bill: %w", err)
+	}
+
+	text := fmt.Sprintf("✅ <b>%s</b> paid, %.2f€ added to %s %s.\n\nNext due %s.",
+		html.EscapeString(bill.Name), transaction.Amount, utils.GetCategoryEmoji(transaction.Category), transaction.Category,
+		bill.NextDueDate.Format("Mon 02 Jan 2006"))
+	return SendMessage(ctx, b, text, nil)
+}
+
+// addBill adds the bill described by the user
+func (c *Client) addBill(b *gotgbot.Bot, ctx *ext.Context, user model.User, input string) error {
+	spec, err := utils.ParseBill(input)
+	if err != nil {
+		text := fmt.Sprintf("⚠️ I couldn't understand this bill: %s.\n\n%s",
+			html.EscapeString(strings.TrimPrefix(err.Error(), utils.ErrInvalidBill.Error()+": ")), billsHelp)
+		return SendMessage(ctx, b, text, nil)
+	}
+
+	bill, err := c.Repositories.Bills.Create(user, spec)
+	if errors.Is(err, repository.ErrTooManyBills) {
+		return SendMessage(ctx, b, fmt.Sprintf("⚠️ You can have up to %d bills, delete one first.", repository.MaxBills), nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create bill: %w", err)
+	}
+
+	notice := fmt.Sprintf("✅ <b>%s</b> added, next due %s. I'll remind you on %s.",
+		html.EscapeString(bill.Name), bill.NextDueDate.Format("Mon 02 Jan"),
+		bill.RemindAt.In(user.Location()).Format("Mon 02 Jan at 15:04"))
+	return c.showBills(b, ctx, user, notice)
+}
+
+// showBills lists the bills of the user, after an optional notice
+func (c *Client) showBills(b *gotgbot.Bot, ctx *ext.Context, user model.User, notice string) error {
+	bills, err := c.Repositories.Bills.List(user.TgID)
+	if err != nil {
+		return fmt.Errorf("failed to get bills: %w", err)
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		text.WriteString(notice + "\n\n")
+	}
+	text.WriteString("🧾 <b>Bills</b>\n\n")
+
+	if len(bills) == 0 {
+		text.WriteString("You don't have any bills yet.\n")
+	}
+
+	today := user.Today()
+	var keyboard [][]gotgbot.InlineKeyboardButton
+	for _, bill := range bills {
+		text.WriteString(fmt.Sprintf("• <b>%s</b> %s, %s\n", html.EscapeString(bill.Name), utils.FormatBillAmount(bill), utils.FormatBillSchedule(bill)))
+		text.WriteString(fmt.Sprintf("   %s\n", utils.FormatBillDue(bill, today)))
+
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: fmt.Sprintf("✅ Pay %s", bill.Name), CallbackData: utils.BillPaidCallback(bill)},
+			{Text: "🗑 Delete", CallbackData: fmt.Sprintf("bills.delete.%d", bill.ID)},
+		})
+	}
+
+	text.WriteString("\n" + billsHelp)
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
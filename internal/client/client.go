@@ -5,7 +5,6 @@ import (
 	"cashout/internal/db"
 	"cashout/internal/repository"
 	"os"
-	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -13,9 +12,6 @@ import (
 const MIN_YEAR_ALLOWED = 2015
 
 type Config struct {
-	// Dev Purpose, telegram usernames
-	AuthEnabled     bool
-	AllowedUsers    map[string]struct{}
 	WebDashboardUrl string
 }
 
@@ -30,21 +26,17 @@ type Repositories struct {
 	Users        repository.Users
 	Transactions repository.Transactions
 	Reminders    repository.Reminders
+	Anomalies    repository.Anomalies
+	APITokens    repository.APITokens
+	Auth         repository.Auth
+	Bills        repository.Bills
+	AllowedUsers repository.AllowedUsers
+	Admin        repository.Admin
+	Invites      repository.Invites
 }
 
 func NewClient(logger *logrus.Logger, db *db.DB, llm ai.LLM) *Client {
-	config := Config{
-		AllowedUsers: make(map[string]struct{}),
-	}
-
-	usernames := os.Getenv("ALLOWED_USERS")
-	if usernames != "" {
-		config.AuthEnabled = true
-		for _, u := range strings.Split(usernames, ",") {
-			config.AllowedUsers[u] = struct{}{}
-		}
-	}
-
+	config := Config{}
 	config.WebDashboardUrl = os.Getenv("WEB_DASHBOARD_URL")
 
 	// For repositories structs embedding common fields
@@ -60,6 +52,13 @@ func NewClient(logger *logrus.Logger, db *db.DB, llm ai.LLM) *Client {
 			Users:        repository.Users{Repository: repo},
 			Transactions: repository.Transactions{Repository: repo},
 			Reminders:    repository.Reminders{Repository: repo},
+			Anomalies:    repository.Anomalies{Repository: repo},
+			APITokens:    repository.APITokens{Repository: repo},
+			Auth:         repository.Auth{Repository: repo},
+			Bills:        repository.Bills{Repository: repo},
+			AllowedUsers: repository.AllowedUsers{Repository: repo, Access: repository.NewAccessFromEnv(logger)},
+			Admin:        repository.Admin{Repository: repo},
+			Invites:      repository.Invites{Repository: repo},
 		},
 		LLM: llm,
 	}
@@ -0,0 +1,238 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+const (
+	compareModePreviousMonth = "prev"
+	compareModeLastYear      = "yoy"
+)
+
+// Compare shows how the current month compares to the previous one.
+// "/compare year" compares with the same month of last year, "/compare ytd" compares the year so far with last year.
+func (c *Client) Compare(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	user.Session.State = model.StateNormal
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	var arg string
+	if ctx.Message != nil {
+		parts := strings.Fields(ctx.Message.Text)
+		if len(parts) > 1 {
+			arg = strings.ToLower(parts[1])
+		}
+	}
+
+	now := user.Now()
+	switch arg {
+	case "ytd":
+		return c.showYearComparison(b, ctx, user, now.Year())
+	case "year", "yoy":
+		return c.showMonthComparison(b, ctx, user, now.Year(), int(now.Month()), compareModeLastYear)
+	default:
+		return c.showMonthComparison(b, ctx, user, now.Year(), int(now.Month()), compareModePreviousMonth)
+	}
+}
+
+// CompareSelected handles the comparison buttons
+func (c *Client) CompareSelected(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	// Parse callback data (format: compare.month.YYYY.MM.<mode> or compare.year.YYYY)
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 3 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	year, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return fmt.Errorf("invalid year: %v", err)
+	}
+
+	if parts[1] == "year" {
+		return c.showYearComparison(b, ctx, user, year)
+	}
+
+	if len(parts) != 5 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	month, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return fmt.Errorf("invalid month: %v", err)
+	}
+
+	return c.showMonthComparison(b, ctx, user, year, month, parts[4])
+}
+
+// showMonthComparison compares a month with the previous month or with the same month of last year
+func (c *Client) showMonthComparison(b *gotgbot.Bot, ctx *ext.Context, user model.User, year, month int, mode string) error {
+	current := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	previous := current.AddDate(0, -1, 0)
+	if mode == compareModeLastYear {
+		previous = current.AddDate(-1, 0, 0)
+	}
+
+	// A month in progress is compared with the same days of the other month
+	days := utils.ComparedMonthDays(year, time.Month(month), user.Today())
+
+	currentTotals, err := c.Repositories.Transactions.GetMonthCategorizedTotalsUpTo(user.TgID, current.Year(), int(current.Month()), days)
+	if err != nil {
+		return fmt.Errorf("failed to get category totals: %w", err)
+	}
+
+	previousTotals, err := c.Repositories.Transactions.GetMonthCategorizedTotalsUpTo(user.TgID, previous.Year(), int(previous.Month()), days)
+	if err != nil {
+		return fmt.Errorf("failed to get category totals: %w", err)
+	}
+
+	comparison := utils.ComparePeriods(currentTotals, previousTotals)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("⚖️ <b>%s vs %s</b>\n\n", formatComparedMonth(current, days), formatComparedMonth(previous, days)))
+	writeComparisonDetails(&text, comparison)
+
+	keyboard := [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: "vs Previous Month", CallbackData: fmt.Sprintf("compare.month.%d.%02d.%s", year, month, compareModePreviousMonth)},
+			{Text: "vs Last Year", CallbackData: fmt.Sprintf("compare.month.%d.%02d.%s", year, month, compareModeLastYear)},
+		},
+		{
+			{Text: fmt.Sprintf("%d vs %d", year, year-1), CallbackData: fmt.Sprintf("compare.year.%d", year)},
+		},
+		{
+			{Text: "⬅️ Back to Recap", CallbackData: fmt.Sprintf("monthrecap.month.%d.%02d", year, month)},
+		},
+	}
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
+
+// showYearComparison compares a year with the previous one. For the current year only the months elapsed so far are compared.
+func (c *Client) showYearComparison(b *gotgbot.Bot, ctx *ext.Context, user model.User, year int) error {
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+	label := fmt.Sprintf("%d vs %d", year, year-1)
+
+	now := user.Now()
+	if year == now.Year() {
+		to = time.Date(year, now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, -1)
+		label = fmt.Sprintf("Jan-%s %d vs %d", now.Month().String()[:3], year, year-1)
+	}
+
+	currentTotals, err := c.Repositories.Transactions.GetCategorizedTotalsByDateRange(user.TgID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get category totals: %w", err)
+	}
+
+	previousTotals, err := c.Repositories.Transactions.GetCategorizedTotalsByDateRange(user.TgID, from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0))
+	if err != nil {
+		return fmt.Errorf("failed to get category totals: %w", err)
+	}
+
+	comparison := utils.ComparePeriods(currentTotals, previousTotals)
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("⚖️ <b>%s</b>\n\n", label))
+	writeComparisonDetails(&text, comparison)
+
+	keyboard := [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: fmt.Sprintf("%d vs %d", year-1, year-2), CallbackData: fmt.Sprintf("compare.year.%d", year-1)},
+		},
+		{
+			{Text: "⬅️ Back to Recap", CallbackData: fmt.Sprintf("yearrecap.year.%d", year)},
+		},
+	}
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
+
+// writeComparisonDetails writes the totals, per-category deltas and biggest movers of a comparison
+func writeComparisonDetails(text *strings.Builder, comparison utils.Comparison) {
+	if comparison.Expenses.Current == 0 && comparison.Expenses.Previous == 0 &&
+		comparison.Income.Current == 0 && comparison.Income.Previous == 0 {
+		text.WriteString("No transactions in either period.")
+		return
+	}
+
+	text.WriteString(fmt.Sprintf("💸 <b>Expenses:</b> %.2f€ → %.2f€ %s\n", comparison.Expenses.Previous, comparison.Expenses.Current, utils.FormatDelta(comparison.Expenses)))
+	text.WriteString(fmt.Sprintf("💰 <b>Income:</b> %.2f€ → %.2f€ %s\n", comparison.Income.Previous, comparison.Income.Current, utils.FormatDelta(comparison.Income)))
+	text.WriteString(fmt.Sprintf("⚖️ <b>Balance:</b> %.2f€ → %.2f€ %s\n", comparison.Balance.Previous, comparison.Balance.Current, utils.FormatDelta(comparison.Balance)))
+
+	for _, section := range []struct {
+		title string
+		tt    model.TransactionType
+	}{
+		{"Expenses by Category", model.TypeExpense},
+		{"Income by Category", model.TypeIncome},
+	} {
+		var lines []string
+		for _, d := range comparison.Categories {
+			if d.Type != section.tt {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("  %s <b>%s:</b> %.2f€ %s\n",
+				utils.GetCategoryEmoji(d.Category), d.Category, d.Current, utils.FormatDelta(d.Delta)))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		text.WriteString(fmt.Sprintf("\n<b>%s:</b>\n", section.title))
+		for _, line := range lines {
+			text.WriteString(line)
+		}
+	}
+
+	writeBiggestMovers(text, comparison)
+}
+
+// formatComparedMonth names a month, with the days compared when it's only the first ones, e.g. "May 2025 (1-12)"
+func formatComparedMonth(month time.Time, days int) string {
+	if days == 0 {
+		return month.Format("January 2006")
+	}
+	_, to := utils.MonthPeriod(month.Year(), month.Month(), days)
+	return fmt.Sprintf("%s (1-%d)", month.Format("January 2006"), to.Day())
+}
+
+// writeBiggestMovers writes the top expense categories that increased and decreased the most
+func writeBiggestMovers(text *strings.Builder, comparison utils.Comparison) {
+	increases := comparison.BiggestIncreases(3)
+	if len(increases) > 0 {
+		text.WriteString("\n📈 <b>Biggest Increases:</b>\n")
+		for _, d := range increases {
+			text.WriteString(fmt.Sprintf("  %s %s %s\n", utils.GetCategoryEmoji(d.Category), d.Category, utils.FormatDelta(d.Delta)))
+		}
+	}
+
+	decreases := comparison.BiggestDecreases(3)
+	if len(decreases) > 0 {
+		text.WriteString("\n📉 <b>Biggest Decreases:</b>\n")
+		for _, d := range decreases {
+			text.WriteString(fmt.Sprintf("  %s %s %s\n", utils.GetCategoryEmoji(d.Category), d.Category, utils.FormatDelta(d.Delta)))
+		}
+	}
+}
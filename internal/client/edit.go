@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
@@ -266,7 +265,7 @@ func (c *Client) EditTransactionCategoryConfirm(b *gotgbot.Bot, ctx *ext.Context
 
 	// Check if category is valid for the transaction type
 	isIncome := transaction.Type == model.TypeIncome
-	isIncomeCategory := newCategory == "Salary" || newCategory == "OtherIncomes"
+	isIncomeCategory := model.IsIncomeCategory(model.TransactionCategory(newCategory))
 
 	if isIncome != isIncomeCategory {
 		_, err = b.SendMessage(
@@ -605,14 +604,14 @@ func (c *Client) EditTransactionDateConfirm(b *gotgbot.Bot, ctx *ext.Context) er
 	}
 
 	// Get date from DD-MM-YYYY to date
-	newDate, err := utils.ParseDate(ctx.Message.Text)
+	newDate, err := utils.ParseDateInYear(ctx.Message.Text, user.Now().Year())
 	if err != nil {
 		fmt.Printf("failed to parse date: %v\n", err)
 		_, err = b.SendMessage(ctx.EffectiveSender.ChatId, "Invalid date, please try again.", nil)
 		return err
 	}
 
-	if newDate.After(time.Now()) {
+	if newDate.After(user.Today()) {
 		_, err = b.SendMessage(ctx.EffectiveSender.ChatId, "I don't support future dates, please try again.", nil)
 		if err != nil {
 			return err
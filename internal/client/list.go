@@ -15,20 +15,20 @@ import (
 // ListTransactions displays the year/month selection keyboard
 func (c *Client) ListTransactions(b *gotgbot.Bot, ctx *ext.Context) error {
 	_, u := c.getUserFromContext(ctx)
-	_, err := c.authAndGetUser(u)
+	user, err := c.authAndGetUser(u)
 	if err != nil {
 		return err
 	}
 
 	// Start with current year
-	currentYear := time.Now().Year()
-	return c.sendMonthSelectionKeyboard(b, ctx, currentYear)
+	currentYear := user.Now().Year()
+	return c.sendMonthSelectionKeyboard(b, ctx, user, currentYear)
 }
 
 // ListYearNavigation handles year navigation in month selection
 func (c *Client) ListYearNavigation(b *gotgbot.Bot, ctx *ext.Context) error {
 	_, u := c.getUserFromContext(ctx)
-	_, err := c.authAndGetUser(u)
+	user, err := c.authAndGetUser(u)
 	if err != nil {
 		return err
 	}
@@ -46,7 +46,7 @@ func (c *Client) ListYearNavigation(b *gotgbot.Bot, ctx *ext.Context) error {
 		return fmt.Errorf("invalid year: %v", err)
 	}
 
-	return c.sendMonthSelectionKeyboard(b, ctx, year)
+	return c.sendMonthSelectionKeyboard(b, ctx, user, year)
 }
 
 // ListMonthTransactions displays transactions for selected month
@@ -113,9 +113,10 @@ func (c *Client) ListTransactionPage(b *gotgbot.Bot, ctx *ext.Context) error {
 }
 
 // Helper function to send month selection keyboard
-func (c *Client) sendMonthSelectionKeyboard(b *gotgbot.Bot, ctx *ext.Context, year int) error {
-	currentYear := time.Now().Year()
-	currentMonth := time.Now().Month()
+func (c *Client) sendMonthSelectionKeyboard(b *gotgbot.Bot, ctx *ext.Context, user model.User, year int) error {
+	now := user.Now()
+	currentYear := now.Year()
+	currentMonth := now.Month()
 
 	var keyboard [][]gotgbot.InlineKeyboardButton
 
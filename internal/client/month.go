@@ -4,7 +4,6 @@ import (
 	"cashout/internal/model"
 	"cashout/internal/utils"
 	"fmt"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -28,14 +27,14 @@ func (c *Client) MonthRecap(b *gotgbot.Bot, ctx *ext.Context) error {
 	}
 
 	// Start with current year
-	currentYear := time.Now().Year()
-	return c.sendMonthRecapSelectionKeyboard(b, ctx, currentYear)
+	currentYear := user.Now().Year()
+	return c.sendMonthRecapSelectionKeyboard(b, ctx, user, currentYear)
 }
 
 // MonthRecapYearNavigation handles year navigation in month selection for recap
 func (c *Client) MonthRecapYearNavigation(b *gotgbot.Bot, ctx *ext.Context) error {
 	_, u := c.getUserFromContext(ctx)
-	_, err := c.authAndGetUser(u)
+	user, err := c.authAndGetUser(u)
 	if err != nil {
 		return err
 	}
@@ -53,7 +52,7 @@ func (c *Client) MonthRecapYearNavigation(b *gotgbot.Bot, ctx *ext.Context) erro
 		return fmt.Errorf("invalid year: %v", err)
 	}
 
-	return c.sendMonthRecapSelectionKeyboard(b, ctx, year)
+	return c.sendMonthRecapSelectionKeyboard(b, ctx, user, year)
 }
 
 // MonthRecapSelected displays the recap for selected month
@@ -86,9 +85,10 @@ func (c *Client) MonthRecapSelected(b *gotgbot.Bot, ctx *ext.Context) error {
 }
 
 // Helper function to send month selection keyboard for recap
-func (c *Client) sendMonthRecapSelectionKeyboard(b *gotgbot.Bot, ctx *ext.Context, year int) error {
-	currentYear := time.Now().Year()
-	currentMonth := time.Now().Month()
+func (c *Client) sendMonthRecapSelectionKeyboard(b *gotgbot.Bot, ctx *ext.Context, user model.User, year int) error {
+	now := user.Now()
+	currentYear := now.Year()
+	currentMonth := now.Month()
 
 	var keyboard [][]gotgbot.InlineKeyboardButton
 
@@ -185,99 +185,38 @@ func (c *Client) showMonthRecap(b *gotgbot.Bot, ctx *ext.Context, user model.Use
 
 	if !ok {
 		txt := fmt.Sprintf("No transactions for %s %d", time.Month(month).String(), year)
-		return c.sendRecapWithNavigation(b, ctx, txt, "month", year, month)
+		return c.sendRecapWithNavigation(b, ctx, user, txt, "month", year, month)
 	}
 
 	// Format the message
 	var text strings.Builder
-	var monthTotal float64
 
 	// Header with month name
 	text.WriteString(fmt.Sprintf("📊 <b>%s %d Summary</b>\n\n", time.Month(month).String(), year))
 
-	// --- EXPENSES SECTION ---
-	if expenseAmount, ok := t[model.TypeExpense]; ok && expenseAmount > 0 {
-		monthTotal -= expenseAmount
-		text.WriteString(fmt.Sprintf("💸 <b>Expenses:</b> %.2f€\n", expenseAmount))
-
-		// Add category breakdown for expenses
-		if expenseCats, ok := categoryTotals[model.TypeExpense]; ok && len(expenseCats) > 0 {
-			text.WriteString("\n<b>Expense Breakdown:</b>\n")
-
-			// Sort categories by amount (descending)
-			categories := make([]struct {
-				Category model.TransactionCategory
-				Amount   float64
-			}, 0, len(expenseCats))
-
-			for cat, amount := range expenseCats {
-				categories = append(categories, struct {
-					Category model.TransactionCategory
-					Amount   float64
-				}{cat, amount})
-			}
-
-			sort.Slice(categories, func(i, j int) bool {
-				return categories[i].Amount > categories[j].Amount
-			})
-
-			// Display each category with emoji
-			for _, entry := range categories {
-				emoji := utils.GetCategoryEmoji(entry.Category)
-				percentage := (entry.Amount / expenseAmount) * 100
-				text.WriteString(fmt.Sprintf("  %s <b>%s:</b> %.2f€ (%.1f%%)\n",
-					emoji, entry.Category, entry.Amount, percentage))
-			}
-			text.WriteString("\n")
-		}
-	}
+	writePeriodSummary(&text, t[model.TypeExpense], t[model.TypeIncome], categoryTotals, "Month Balance")
 
-	// --- INCOME SECTION ---
-	if incomeAmount, ok := t[model.TypeIncome]; ok && incomeAmount > 0 {
-		monthTotal += incomeAmount
-		text.WriteString(fmt.Sprintf("💰 <b>Income:</b> %.2f€\n", incomeAmount))
-
-		// Add category breakdown for income
-		if incomeCats, ok := categoryTotals[model.TypeIncome]; ok && len(incomeCats) > 0 {
-			text.WriteString("\n<b>Income Breakdown:</b>\n")
-
-			// Sort categories by amount (descending)
-			categories := make([]struct {
-				Category model.TransactionCategory
-				Amount   float64
-			}, 0, len(incomeCats))
-
-			for cat, amount := range incomeCats {
-				categories = append(categories, struct {
-					Category model.TransactionCategory
-					Amount   float64
-				}{cat, amount})
-			}
-
-			sort.Slice(categories, func(i, j int) bool {
-				return categories[i].Amount > categories[j].Amount
-			})
-
-			// Display each category with emoji
-			for _, entry := range categories {
-				emoji := utils.GetCategoryEmoji(entry.Category)
-				percentage := (entry.Amount / incomeAmount) * 100
-				text.WriteString(fmt.Sprintf("  %s <b>%s:</b> %.2f€ (%.1f%%)\n",
-					emoji, entry.Category, entry.Amount, percentage))
-			}
-			text.WriteString("\n")
+	// --- COMPARISON SECTION ---
+	// A month in progress is compared with the same days of the earlier months
+	current := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	days := utils.ComparedMonthDays(year, time.Month(month), user.Today())
+	for _, previous := range []time.Time{current.AddDate(0, -1, 0), current.AddDate(-1, 0, 0)} {
+		previousTotals, err := c.Repositories.Transactions.GetMonthCategorizedTotalsUpTo(user.TgID, previous.Year(), int(previous.Month()), days)
+		if err != nil {
+			return err
 		}
+		utils.WriteComparisonSummary(&text, "vs "+formatComparedMonth(previous, days), utils.ComparePeriods(categoryTotals, previousTotals))
 	}
 
-	// --- TOTAL BALANCE ---
-	var balanceEmoji string
-	if monthTotal >= 0 {
-		balanceEmoji = "✅"
-	} else {
-		balanceEmoji = "❌"
+	// --- FORECAST SECTION ---
+	now := user.Now()
+	if year == now.Year() && month == int(now.Month()) {
+		forecast, err := c.Repositories.Transactions.GetMonthForecast(user.TgID, now)
+		if err != nil {
+			return err
+		}
+		text.WriteString("\n\n" + utils.FormatForecast(forecast))
 	}
 
-	text.WriteString(fmt.Sprintf("\n%s <b>Month Balance:</b> %.2f€", balanceEmoji, monthTotal))
-
-	return c.sendRecapWithNavigation(b, ctx, text.String(), "month", year, month)
+	return c.sendRecapWithNavigation(b, ctx, user, text.String(), "month", year, month)
 }
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+const recapRangeHelp = "Tell me the period you want a recap for.\n\n<i>Examples:</i>\n<code>last 30 days</code>\n<code>Q2 2025</code>\n<code>from 01-03 to 15-04</code>\n<code>since payday</code>\n<code>march 2024</code>"
+
+// Recap shows the recap for a custom date range, given as argument (e.g. /recap last 30 days) or asked to the user
+func (c *Client) Recap(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	var rangeText string
+	if ctx.Message != nil {
+		parts := strings.SplitN(strings.TrimSpace(ctx.Message.Text), " ", 2)
+		if len(parts) == 2 {
+			rangeText = parts[1]
+		}
+	}
+
+	if rangeText != "" {
+		return c.showRangeRecap(b, ctx, user, rangeText)
+	}
+
+	user.Session.State = model.StateEnteringRecapRange
+	user.Session.Body = ""
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	return SendMessage(ctx, b, "📊 <b>Custom Recap</b>\n\n"+recapRangeHelp, [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: "Last 7 days", CallbackData: "recap.range.last 7 days"},
+			{Text: "Last 30 days", CallbackData: "recap.range.last 30 days"},
+		},
+		{
+			{Text: "This quarter", CallbackData: "recap.range.this quarter"},
+			{Text: "Last quarter", CallbackData: "recap.range.last quarter"},
+		},
+		{
+			{Text: "Since payday", CallbackData: "recap.range.since payday"},
+		},
+		{
+			{Text: "❌ Cancel", CallbackData: "recap.cancel"},
+		},
+	})
+}
+
+// RecapRangeSelected shows the recap for one of the suggested ranges
+func (c *Client) RecapRangeSelected(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	// Parse callback data (format: recap.range.<range text>)
+	rangeText := strings.TrimPrefix(ctx.CallbackQuery.Data, "recap.range.")
+
+	return c.showRangeRecap(b, ctx, user, rangeText)
+}
+
+// RecapRangeEntered handles the date range typed by the user
+func (c *Client) RecapRangeEntered(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	return c.showRangeRecap(b, ctx, user, ctx.Message.Text)
+}
+
+// showRangeRecap parses the date range and shows its recap in the same style as the month recap
+func (c *Client) showRangeRecap(b *gotgbot.Bot, ctx *ext.Context, user model.User, rangeText string) error {
+	var payday *time.Time
+	if utils.MentionsPayday(rangeText) {
+		p, err := c.Repositories.Transactions.GetLastPayday(user.TgID)
+		if err != nil {
+			return fmt.Errorf("failed to get last payday: %w", err)
+		}
+		payday = p
+	}
+
+	r, err := utils.ParseDateRange(rangeText, user.Now(), payday)
+	if err != nil {
+		msg := "I couldn't understand that period, please try again.\n\n" + recapRangeHelp
+		if errors.Is(err, utils.ErrPaydayUnknown) {
+			msg = "I couldn't find any salary yet, so I don't know when your payday was. Try another period.\n\n" + recapRangeHelp
+		}
+		// The user was told, a typo isn't an error of the bot
+		return SendMessage(ctx, b, msg, recapCancelKeyboard())
+	}
+
+	if r.From.After(user.Today()) {
+		return SendMessage(ctx, b, "That period is in the future, please try again.", recapCancelKeyboard())
+	}
+
+	// Only the days so far count for a period in progress, e.g. this quarter
+	r = r.Until(user.Today())
+
+	user.Session.State = model.StateNormal
+	user.Session.Body = ""
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	categoryTotals, err := c.Repositories.Transactions.GetCategorizedTotalsByDateRange(user.TgID, r.From, r.To)
+	if err != nil {
+		return fmt.Errorf("failed to get category totals: %w", err)
+	}
+
+	var expenseAmount, incomeAmount float64
+	for _, amount := range categoryTotals[model.TypeExpense] {
+		expenseAmount += amount
+	}
+	for _, amount := range categoryTotals[model.TypeIncome] {
+		incomeAmount += amount
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📊 <b>%s Summary</b>\n", r.Label))
+	text.WriteString(fmt.Sprintf("<i>%s - %s</i>\n\n", r.From.Format("02 Jan 2006"), r.To.Format("02 Jan 2006")))
+
+	if expenseAmount == 0 && incomeAmount == 0 {
+		text.WriteString("No transactions in this period.")
+		return c.SendHomeKeyboard(b, ctx, text.String())
+	}
+
+	writePeriodSummary(&text, expenseAmount, incomeAmount, categoryTotals, "Period Balance")
+
+	if expenseAmount > 0 {
+		text.WriteString(fmt.Sprintf("\n📈 <b>Avg Daily Spending:</b> %.2f€", expenseAmount/float64(r.Days())))
+	}
+
+	return c.SendHomeKeyboard(b, ctx, text.String())
+}
+
+// recapCancelKeyboard lets the user stop entering a period after a mistake
+func recapCancelKeyboard() [][]gotgbot.InlineKeyboardButton {
+	return [][]gotgbot.InlineKeyboardButton{{{Text: "❌ Cancel", CallbackData: "recap.cancel"}}}
+}
+
+// writePeriodSummary writes the expenses and income of a period with their category breakdown, followed by the balance
+func writePeriodSummary(text *strings.Builder, expenseAmount, incomeAmount float64, categoryTotals map[model.TransactionType]map[model.TransactionCategory]float64, balanceLabel string) {
+	var total float64
+
+	// --- EXPENSES SECTION ---
+	if expenseAmount > 0 {
+		total -= expenseAmount
+		text.WriteString(fmt.Sprintf("💸 <b>Expenses:</b> %.2f€\n", expenseAmount))
+		writeCategoryBreakdown(text, "Expense Breakdown", categoryTotals[model.TypeExpense], expenseAmount)
+	}
+
+	// --- INCOME SECTION ---
+	if incomeAmount > 0 {
+		total += incomeAmount
+		text.WriteString(fmt.Sprintf("💰 <b>Income:</b> %.2f€\n", incomeAmount))
+		writeCategoryBreakdown(text, "Income Breakdown", categoryTotals[model.TypeIncome], incomeAmount)
+	}
+
+	// --- TOTAL BALANCE ---
+	var balanceEmoji string
+	if total >= 0 {
+		balanceEmoji = "✅"
+	} else {
+		balanceEmoji = "❌"
+	}
+
+	text.WriteString(fmt.Sprintf("\n%s <b>%s:</b> %.2f€", balanceEmoji, balanceLabel, total))
+}
+
+// writeCategoryBreakdown writes the categories sorted by amount (descending) with their share of the total
+func writeCategoryBreakdown(text *strings.Builder, title string, cats map[model.TransactionCategory]float64, total float64) {
+	if len(cats) == 0 {
+		return
+	}
+
+	text.WriteString(fmt.Sprintf("\n<b>%s:</b>\n", title))
+
+	// Sort categories by amount (descending)
+	categories := make([]struct {
+		Category model.TransactionCategory
+		Amount   float64
+	}, 0, len(cats))
+
+	for cat, amount := range cats {
+		categories = append(categories, struct {
+			Category model.TransactionCategory
+			Amount   float64
+		}{cat, amount})
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Amount > categories[j].Amount
+	})
+
+	// Display each category with emoji
+	for _, entry := range categories {
+		emoji := utils.GetCategoryEmoji(entry.Category)
+		percentage := (entry.Amount / total) * 100
+		text.WriteString(fmt.Sprintf("  %s <b>%s:</b> %.2f€ (%.1f%%)\n",
+			emoji, entry.Category, entry.Amount, percentage))
+	}
+	text.WriteString("\n")
+}
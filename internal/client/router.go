@@ -71,16 +71,20 @@ func (c *Client) FreeTextRouter(b *gotgbot.Bot, ctx *ext.Context) error {
 		return c.SearchQueryEntered(b, ctx)
 	}
 
-	// End of top-level edit transaction
+	if user.Session.State == model.StateEnteringRecapRange {
+		return c.RecapRangeEntered(b, ctx)
+	}
 
-	// Default behavior: start transaction flow for any unhandled text.
-	// Heuristic 1: there must be at least a digit in text.
-	if strings.ContainsAny(ctx.Message.Text, "0123456789") {
+	if user.Session.State == model.StateEnteringBillAmount {
+		return c.billAmountEntered(b, ctx, user)
+	}
+
+	// End of top-level edit transaction
 
-		// Heuristic 2: it's more common to be an expense than an income, set it to default.
+	// Default behavior: start transaction flow for any unhandled text that looks like a transaction.
+	if transactionType, ok := utils.GuessTransactionType(ctx.Message.Text); ok {
 		user.Session.State = model.StateInsertingExpense
-		// Heuristic 3: try to extract if it could be an income by looking in the text.
-		if utils.IsAnIncomeTransactionPrompt(ctx.Message.Text) {
+		if transactionType == model.TypeIncome {
 			user.Session.State = model.StateInsertingIncome
 		}
 		err = c.Repositories.Users.Update(&user)
@@ -92,7 +96,7 @@ func (c *Client) FreeTextRouter(b *gotgbot.Bot, ctx *ext.Context) error {
 	}
 
 	err = c.CleanupKeyboard(b, ctx)
-	err = errors.Join(err, c.SendHomeKeyboard(b, ctx, "Sorry I don't understand, what can I do for you?\n\n/edit - Edit a transaction\n/delete - Delete a transaction\n/search - Search transactions\n/list - List your transactions\n/week Week Recap\n/month Month Recap\n/year Year Recap\n/export - Export all transactions to CSV"))
+	err = errors.Join(err, c.SendHomeKeyboard(b, ctx, "Sorry I don't understand, what can I do for you?\n\n/edit - Edit a transaction\n/delete - Delete a transaction\n/search - Search transactions\n/list - List your transactions\n/week Week Recap\n/month Month Recap\n/year Year Recap\n/recap Custom Range Recap\n/compare Compare with previous periods\n/export - Export all transactions to CSV\n/token - Manage API tokens\n/sessions - Manage web sessions\n/settings - Recap notifications\n/bills - Bill reminders\n/timezone - Set your time zone"))
 	if err != nil {
 		return err
 	}
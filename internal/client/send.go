@@ -1,8 +1,8 @@
 package client
 
 import (
+	"cashout/internal/model"
 	"fmt"
-	"time"
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
@@ -41,7 +41,7 @@ func (c *Client) SendHomeKeyboard(b *gotgbot.Bot, ctx *ext.Context, text string)
 			{Text: "Month Recap", CallbackData: "home.month"},
 		},
 		{
-			{Text: "🌐 Web Dashboard", Url: c.Config.WebDashboardUrl},
+			c.webDashboardButton(),
 		},
 	}
 
@@ -109,7 +109,8 @@ func SendMessage(ctx *ext.Context, b *gotgbot.Bot, message string, keyboard [][]
 }
 
 // sendRecapWithNavigation sends a recap message with navigation buttons for previous/next period
-func (c *Client) sendRecapWithNavigation(b *gotgbot.Bot, ctx *ext.Context, text string, recapType string, year int, month int) error {
+func (c *Client) sendRecapWithNavigation(b *gotgbot.Bot, ctx *ext.Context, user model.User, text string, recapType string, year int, month int) error {
+	now := user.Now()
 	var keyboard [][]gotgbot.InlineKeyboardButton
 
 	// Create navigation row with Previous/Next buttons
@@ -141,8 +142,7 @@ func (c *Client) sendRecapWithNavigation(b *gotgbot.Bot, ctx *ext.Context, text
 		}
 
 		// Add Next button if not in the future
-		currentTime := time.Now()
-		if nextYear < currentTime.Year() || (nextYear == currentTime.Year() && nextMonth <= int(currentTime.Month())) {
+		if nextYear < now.Year() || (nextYear == now.Year() && nextMonth <= int(now.Month())) {
 			navRow = append(navRow, gotgbot.InlineKeyboardButton{
 				Text:         "Next Month ➡️",
 				CallbackData: fmt.Sprintf("monthrecap.month.%d.%02d", nextYear, nextMonth),
@@ -158,8 +158,7 @@ func (c *Client) sendRecapWithNavigation(b *gotgbot.Bot, ctx *ext.Context, text
 		}
 
 		// Add Next button if not in the future
-		currentYear := time.Now().Year()
-		if year < currentYear {
+		if year < now.Year() {
 			navRow = append(navRow, gotgbot.InlineKeyboardButton{
 				Text:         "Next Year ➡️",
 				CallbackData: fmt.Sprintf("yearrecap.year.%d", year+1),
@@ -174,6 +173,20 @@ func (c *Client) sendRecapWithNavigation(b *gotgbot.Bot, ctx *ext.Context, text
 		keyboard = append(keyboard, navRow)
 	}
 
+	// Add comparison and PDF statement buttons for the displayed period
+	switch recapType {
+	case "month":
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: "⚖️ Compare", CallbackData: fmt.Sprintf("compare.month.%d.%02d.prev", year, month)},
+			{Text: "📄 PDF Statement", CallbackData: fmt.Sprintf("statement.month.%d.%02d", year, month)},
+		})
+	case "year":
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: "⚖️ Compare", CallbackData: fmt.Sprintf("compare.year.%d", year)},
+			{Text: "📄 PDF Statement", CallbackData: fmt.Sprintf("statement.year.%d", year)},
+		})
+	}
+
 	// Add standard home keyboard buttons
 	keyboard = append(keyboard, [][]gotgbot.InlineKeyboardButton{
 		{
@@ -193,7 +206,7 @@ func (c *Client) sendRecapWithNavigation(b *gotgbot.Bot, ctx *ext.Context, text
 			{Text: "Month Recap", CallbackData: "home.month"},
 		},
 		{
-			{Text: "🌐 Web Dashboard", Url: c.Config.WebDashboardUrl},
+			c.webDashboardButton(),
 		},
 	}...)
 
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+// Sessions handles the /sessions command: it lists the user's web sessions with buttons to log them out
+func (c *Client) Sessions(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	user.Session.State = model.StateNormal
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	return c.showSessions(b, ctx, user, "")
+}
+
+// SessionSelected handles the session buttons (format: sessions.revoke.SESSION_ID, sessions.revokeall or sessions.list)
+func (c *Client) SessionSelected(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	switch parts[1] {
+	case "revoke":
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid callback data format")
+		}
+		sessionID, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid session ID: %v", err)
+		}
+		if err := c.Repositories.Auth.RevokeWebSession(sessionID, user.TgID); err != nil {
+			// Already expired or logged out
+			return c.showSessions(b, ctx, user, "⚠️ This session had already ended.")
+		}
+		return c.showSessions(b, ctx, user, "✅ Session logged out.")
+	case "revokeall":
+		revoked, err := c.Repositories.Auth.RevokeOtherWebSessions(user.TgID, "")
+		if err != nil {
+			return fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+		return c.showSessions(b, ctx, user, fmt.Sprintf("✅ %d session(s) logged out.", revoked))
+	default:
+		return c.showSessions(b, ctx, user, "")
+	}
+}
+
+// showSessions lists the active web sessions of the user, after an optional notice
+func (c *Client) showSessions(b *gotgbot.Bot, ctx *ext.Context, user model.User, notice string) error {
+	sessions, err := c.Repositories.Auth.GetUserWebSessions(user.TgID)
+	if err != nil {
+		return fmt.Errorf("failed to get sessions: %w", err)
+	}
+
+	var text strings.Builder
+	if notice != "" {
+		text.WriteString(notice + "\n\n")
+	}
+	text.WriteString("🖥 <b>Web Sessions</b>\n\n")
+
+	if len(sessions) == 0 {
+		text.WriteString("You're not logged in to the web dashboard anywhere.")
+		return SendMessage(ctx, b, text.String(), nil)
+	}
+
+	var keyboard [][]gotgbot.InlineKeyboardButton
+	for i, s := range sessions {
+		device := utils.DescribeUserAgent(s.UserAgent)
+		text.WriteString(fmt.Sprintf("%d. <b>%s</b> (%s)\n", i+1, html.EscapeString(device), html.EscapeString(s.IPAddress)))
+		text.WriteString(fmt.Sprintf("   Last active %s, expires %s", s.LastSeenAt.Format("02-01-2006 15:04"), s.ExpiresAt.Format("02-01-2006")))
+		if s.Remember {
+			text.WriteString(" (remembered)")
+		}
+		text.WriteString("\n")
+
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: fmt.Sprintf("🚪 Log out %d. %s", i+1, device), CallbackData: fmt.Sprintf("sessions.revoke.%d", s.PublicID)},
+		})
+	}
+
+	if len(sessions) > 1 {
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: "🚪 Log out everywhere", CallbackData: "sessions.revokeall"},
+		})
+	}
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
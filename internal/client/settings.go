@@ -0,0 +1,212 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+// Settings handles the /settings command: it shows which recaps the user receives and when, with buttons to change it
+func (c *Client) Settings(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	user.Session.State = model.StateNormal
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	return c.showSettings(b, ctx, user)
+}
+
+// SettingSelected handles the settings buttons (format: settings.toggle.TYPE, settings.day[.WEEKDAY],
+// settings.hour[.HOUR], settings.nudgehour[.HOUR] or settings.show)
+func (c *Client) SettingSelected(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	previous := user
+	switch {
+	case parts[1] == "toggle" && len(parts) == 3:
+		switch model.ReminderType(parts[2]) {
+		case model.ReminderTypeWeeklyRecap:
+			user.WeeklyRecap = !user.WeeklyRecap
+		case model.ReminderTypeMonthlyRecap:
+			user.MonthlyRecap = !user.MonthlyRecap
+		case model.ReminderTypeYearlyRecap:
+			user.YearlyRecap = !user.YearlyRecap
+		case "nudge":
+			user.DailyNudge = !user.DailyNudge
+			// Start again from one nudge a day
+			if err := c.Repositories.Users.ResetNudges(user.TgID); err != nil {
+				return fmt.Errorf("failed to reset nudges: %w", err)
+			}
+			user.NudgesIgnored = 0
+		case "weekends":
+			user.NudgeSkipWeekends = !user.NudgeSkipWeekends
+		default:
+			return fmt.Errorf("invalid recap type: %s", parts[2])
+		}
+		return c.saveSettings(b, ctx, previous, user)
+	case parts[1] == "day" && len(parts) == 2:
+		return c.showWeekdaySelection(b, ctx)
+	case parts[1] == "day" && len(parts) == 3:
+		weekday, err := strconv.Atoi(parts[2])
+		if err != nil || weekday < int(time.Sunday) || weekday > int(time.Saturday) {
+			return fmt.Errorf("invalid weekday: %s", parts[2])
+		}
+		user.RecapWeekday = time.Weekday(weekday)
+		return c.saveSettings(b, ctx, previous, user)
+	case (parts[1] == "hour" || parts[1] == "nudgehour") && len(parts) == 2:
+		return c.showHourSelection(b, ctx, parts[1])
+	case (parts[1] == "hour" || parts[1] == "nudgehour") && len(parts) == 3:
+		hour, err := strconv.Atoi(parts[2])
+		if err != nil || hour < 0 || hour > 23 {
+			return fmt.Errorf("invalid hour: %s", parts[2])
+		}
+		if parts[1] == "nudgehour" {
+			user.NudgeHour = hour
+		} else {
+			user.RecapHour = hour
+		}
+		return c.saveSettings(b, ctx, previous, user)
+	default:
+		return c.showSettings(b, ctx, user)
+	}
+}
+
+// saveSettings saves the changed settings of the user and moves their upcoming recaps accordingly
+func (c *Client) saveSettings(b *gotgbot.Bot, ctx *ext.Context, previous model.User, user model.User) error {
+	err := c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	// Recaps no longer wanted or due at another time are cancelled, the scheduler creates them again accordingly
+	recapsMoved := previous.RecapWeekday != user.RecapWeekday || previous.RecapHour != user.RecapHour
+	recapsTurnedOff := (previous.WeeklyRecap && !user.WeeklyRecap) ||
+		(previous.MonthlyRecap && !user.MonthlyRecap) ||
+		(previous.YearlyRecap && !user.YearlyRecap)
+	if recapsMoved || recapsTurnedOff {
+		if err := c.Repositories.Reminders.CancelUpcomingRecaps(user.TgID, time.Now()); err != nil {
+			return fmt.Errorf("failed to cancel upcoming recaps: %w", err)
+		}
+	}
+
+	return c.showSettings(b, ctx, user)
+}
+
+// showSettings shows the recap settings of the user
+func (c *Client) showSettings(b *gotgbot.Bot, ctx *ext.Context, user model.User) error {
+	var text strings.Builder
+	text.WriteString("⚙️ <b>Settings</b>\n\n")
+	text.WriteString(fmt.Sprintf("🔔 <b>Recaps</b>, sent at %02d:00 (%s)\n",
+		user.RecapHour, html.EscapeString(utils.FormatTimezone(user.Timezone))))
+	text.WriteString(fmt.Sprintf("%s Weekly, the past 7 days every %s\n", enabledEmoji(user.WeeklyRecap), user.RecapWeekday))
+	text.WriteString(fmt.Sprintf("%s Monthly, last month on the 1st\n", enabledEmoji(user.MonthlyRecap)))
+	text.WriteString(fmt.Sprintf("%s Yearly, last year on January 1st\n", enabledEmoji(user.YearlyRecap)))
+	text.WriteString(fmt.Sprintf("\n✍️ <b>Daily reminder</b> to log when you recorded nothing, at %02d:00\n", user.NudgeHour))
+	text.WriteString(fmt.Sprintf("%s %s, less often if you keep ignoring it\n", enabledEmoji(user.DailyNudge), nudgeDays(user)))
+	text.WriteString("\nChange your time zone with /timezone.")
+
+	keyboard := [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: enabledEmoji(user.WeeklyRecap) + " Weekly", CallbackData: "settings.toggle." + string(model.ReminderTypeWeeklyRecap)},
+			{Text: enabledEmoji(user.MonthlyRecap) + " Monthly", CallbackData: "settings.toggle." + string(model.ReminderTypeMonthlyRecap)},
+			{Text: enabledEmoji(user.YearlyRecap) + " Yearly", CallbackData: "settings.toggle." + string(model.ReminderTypeYearlyRecap)},
+		},
+		{
+			{Text: "📅 " + user.RecapWeekday.String(), CallbackData: "settings.day"},
+			{Text: fmt.Sprintf("🕕 %02d:00", user.RecapHour), CallbackData: "settings.hour"},
+		},
+		{
+			{Text: enabledEmoji(user.DailyNudge) + " Daily reminder", CallbackData: "settings.toggle.nudge"},
+			{Text: fmt.Sprintf("🕘 %02d:00", user.NudgeHour), CallbackData: "settings.nudgehour"},
+		},
+		{
+			{Text: enabledEmoji(user.NudgeSkipWeekends) + " Skip weekends", CallbackData: "settings.toggle.weekends"},
+		},
+	}
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
+
+// showWeekdaySelection asks the day of the week of the weekly recap
+func (c *Client) showWeekdaySelection(b *gotgbot.Bot, ctx *ext.Context) error {
+	var keyboard [][]gotgbot.InlineKeyboardButton
+	var row []gotgbot.InlineKeyboardButton
+	// Monday first
+	for i := 1; i <= 7; i++ {
+		weekday := time.Weekday(i % 7)
+		row = append(row, gotgbot.InlineKeyboardButton{
+			Text:         weekday.String()[:3],
+			CallbackData: fmt.Sprintf("settings.day.%d", weekday),
+		})
+		if len(row) == 4 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	keyboard = append(keyboard, row)
+	keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{{Text: "⬅️ Back", CallbackData: "settings.show"}})
+
+	return SendMessage(ctx, b, "📅 On which day do you want the weekly recap?", keyboard)
+}
+
+// showHourSelection asks the hour of the recaps (setting "hour") or of the daily reminder (setting "nudgehour")
+func (c *Client) showHourSelection(b *gotgbot.Bot, ctx *ext.Context, setting string) error {
+	var keyboard [][]gotgbot.InlineKeyboardButton
+	var row []gotgbot.InlineKeyboardButton
+	for hour := 0; hour < 24; hour++ {
+		row = append(row, gotgbot.InlineKeyboardButton{
+			Text:         fmt.Sprintf("%02d:00", hour),
+			CallbackData: fmt.Sprintf("settings.%s.%d", setting, hour),
+		})
+		if len(row) == 6 {
+			keyboard = append(keyboard, row)
+			row = nil
+		}
+	}
+	keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{{Text: "⬅️ Back", CallbackData: "settings.show"}})
+
+	question := "🕕 At what time do you want your recaps?"
+	if setting == "nudgehour" {
+		question = "🕘 At what time do you want the daily reminder?"
+	}
+	return SendMessage(ctx, b, question, keyboard)
+}
+
+// nudgeDays describes the days the daily reminder is sent
+func nudgeDays(user model.User) string {
+	if user.NudgeSkipWeekends {
+		return "Monday to Friday"
+	}
+	return "Every day"
+}
+
+// enabledEmoji marks a setting as on or off
+func enabledEmoji(enabled bool) string {
+	if enabled {
+		return "✅"
+	}
+	return "❌"
+}
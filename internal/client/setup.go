@@ -43,6 +43,8 @@ func SetupHandlers(dispatcher *ext.Dispatcher, c *Client) {
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("delete.page."), c.DeleteTransactionPage))
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("delete.confirm."), c.DeleteTransactionConfirm))
 
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("anomaly.fine."), c.AnomalyDismiss))
+
 	dispatcher.AddHandler(handlers.NewCommand("cancel", c.Cancel))
 	dispatcher.AddHandler(handlers.NewCommand("delete", c.DeleteTransactions))
 	dispatcher.AddHandler(handlers.NewCommand("start", c.Start))
@@ -50,7 +52,16 @@ func SetupHandlers(dispatcher *ext.Dispatcher, c *Client) {
 	dispatcher.AddHandler(handlers.NewCommand("week", c.WeekRecap))
 	dispatcher.AddHandler(handlers.NewCommand("month", c.MonthRecap))
 	dispatcher.AddHandler(handlers.NewCommand("year", c.YearRecap))
+	dispatcher.AddHandler(handlers.NewCommand("recap", c.Recap))
+	dispatcher.AddHandler(handlers.NewCommand("compare", c.Compare))
 	dispatcher.AddHandler(handlers.NewCommand("export", c.ExportTransactions))
+	dispatcher.AddHandler(handlers.NewCommand("token", c.Token))
+	dispatcher.AddHandler(handlers.NewCommand("sessions", c.Sessions))
+	dispatcher.AddHandler(handlers.NewCommand("timezone", c.Timezone))
+	dispatcher.AddHandler(handlers.NewCommand("settings", c.Settings))
+	dispatcher.AddHandler(handlers.NewCommand("bills", c.Bills))
+	dispatcher.AddHandler(handlers.NewCommand("admin", c.Admin))
+	dispatcher.AddHandler(handlers.NewMessage(message.Location, c.TimezoneFromLocation))
 
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Equal("monthrecap.cancel"), c.Cancel))
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("monthrecap.year."), c.MonthRecapYearNavigation))
@@ -59,6 +70,23 @@ func SetupHandlers(dispatcher *ext.Dispatcher, c *Client) {
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Equal("yearrecap.cancel"), c.Cancel))
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("yearrecap.year."), c.YearRecapSelected))
 
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("statement."), c.SendStatement))
+
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("compare."), c.CompareSelected))
+
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("token."), c.TokenSelected))
+
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("sessions."), c.SessionSelected))
+
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("settings."), c.SettingSelected))
+
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("bills."), c.BillSelected))
+
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("admin."), c.AdminSelected))
+
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Equal("recap.cancel"), c.Cancel))
+	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Prefix("recap.range."), c.RecapRangeSelected))
+
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Equal("home.week"), c.WeekRecap))
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Equal("home.month"), c.MonthRecap))
 	dispatcher.AddHandler(handlers.NewCallback(callbackquery.Equal("home.year"), c.YearRecap))
@@ -3,21 +3,53 @@ package client
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 )
 
-// Start introduces the bot.
+// Start introduces the bot. On private deployments "/start CODE", sent by the invite deep links,
+// redeems an invite code and allows the user.
 func (c *Client) Start(b *gotgbot.Bot, ctx *ext.Context) error {
 	_, u := c.getUserFromContext(ctx)
+
+	var code string
+	if ctx.Message != nil {
+		if parts := strings.Fields(ctx.Message.Text); len(parts) > 1 {
+			code = parts[1]
+		}
+	}
+
+	if c.Repositories.AllowedUsers.Access.Private && code != "" {
+		allowed, err := c.Repositories.AllowedUsers.CanUse(u.Id, u.Username)
+		if err != nil {
+			return fmt.Errorf("failed to check allowed users: %w", err)
+		}
+		if !allowed {
+			redeemed, err := c.Repositories.Invites.Redeem(code, u.Id, u.Username, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to redeem invite code: %w", err)
+			}
+			if !redeemed {
+				if _, err := b.SendMessage(ctx.EffectiveChat.Id, "⚠️ This invite code is invalid, expired or already used up.", nil); err != nil {
+					return err
+				}
+				return c.SendNotAllowed(b, ctx)
+			}
+		}
+	}
+
 	user, err := c.authAndGetUser(u)
+	if errors.Is(err, ErrNotAllowed) {
+		return c.SendNotAllowed(b, ctx)
+	}
 	if err != nil {
-		_, errm := b.SendMessage(ctx.EffectiveChat.Id, fmt.Sprintf("You're not allowed to use this bot.\nContact the admin giving them your Telegram ID: \"%d\", and your username (you must create one): \"%s\"", ctx.EffectiveChat.Id, ctx.EffectiveChat.Username), nil)
-		return errors.Join(err, errm)
+		return err
 	}
 
-	msg := fmt.Sprintf("Welcome to Cashout, %s!\nWhat can I do for you?\n\n/edit - Edit a transaction\n/delete - Delete a transaction\n/search - Search transactions\n/list - List your transactions\n/week Week Recap\n/month Month Recap\n/year Year Recap\n/export - Export all transactions to CSV", user.Name)
+	msg := fmt.Sprintf("Welcome to Cashout, %s!\nWhat can I do for you?\n\n/edit - Edit a transaction\n/delete - Delete a transaction\n/search - Search transactions\n/list - List your transactions\n/week Week Recap\n/month Month Recap\n/year Year Recap\n/recap Custom Range Recap\n/compare Compare with previous periods\n/export - Export all transactions to CSV\n/token - Manage API tokens\n/sessions - Manage web sessions\n/settings - Recap notifications\n/bills - Bill reminders\n/timezone - Set your time zone", user.Name)
 
 	err = c.SendHomeKeyboard(b, ctx, msg)
 
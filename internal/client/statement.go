@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bytes"
+	"cashout/internal/pdf"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+// StatementPeriod returns the date range, label and filename suffix for a month (1-12) or a whole year (month 0)
+func StatementPeriod(year, month int) (from, to time.Time, label, suffix string) {
+	if month == 0 {
+		from = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		to = time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+		return from, to, fmt.Sprintf("Year %d", year), fmt.Sprintf("%d", year)
+	}
+
+	from = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	to = from.AddDate(0, 1, -1) // Last day of the month
+	return from, to, from.Format("January 2006"), from.Format("2006-01")
+}
+
+// SendStatement sends the PDF statement for the month or year selected from the recap navigation
+func (c *Client) SendStatement(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	query := ctx.CallbackQuery
+
+	// Parse callback data (format: statement.month.YYYY.MM or statement.year.YYYY)
+	parts := strings.Split(query.Data, ".")
+	if len(parts) < 3 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	year, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return fmt.Errorf("invalid year: %v", err)
+	}
+
+	month := 0
+	if parts[1] == "month" {
+		if len(parts) != 4 {
+			return fmt.Errorf("invalid callback data format")
+		}
+		month, err = strconv.Atoi(parts[3])
+		if err != nil || month < 1 || month > 12 {
+			return fmt.Errorf("invalid month: %s", parts[3])
+		}
+	}
+
+	from, to, label, suffix := StatementPeriod(year, month)
+
+	transactions, err := c.Repositories.Transactions.GetUserTransactionsByDateRange(user.TgID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	statement := pdf.Statement{
+		Name:         user.Name,
+		Period:       label,
+		From:         from,
+		To:           to,
+		Transactions: transactions,
+		GeneratedAt:  user.Now(),
+	}
+
+	data, err := statement.Render()
+	if err != nil {
+		return fmt.Errorf("failed to render statement: %w", err)
+	}
+
+	_, err = query.Answer(b, nil)
+	if err != nil {
+		c.Logger.Errorf("Failed to answer callback query: %v", err)
+	}
+
+	filename := fmt.Sprintf("cashout_statement_%s.pdf", suffix)
+	_, err = b.SendDocument(ctx.EffectiveSender.ChatId, gotgbot.InputFileByReader(filename, bytes.NewReader(data)), &gotgbot.SendDocumentOpts{
+		Caption:   fmt.Sprintf("📄 Statement for %s (%d transactions)", label, len(transactions)),
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send statement: %w", err)
+	}
+
+	return nil
+}
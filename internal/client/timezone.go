@@ -0,0 +1,111 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+const timezoneHelp = "To change it, send <code>/timezone Europe/Rome</code> or an offset like <code>/timezone UTC+2</code>, " +
+	"or share your location with the button below."
+
+// Timezone handles the /timezone command: it shows the user's time zone, "/timezone NAME" sets it.
+// Dates, recaps and reminders follow the user's time zone.
+func (c *Client) Timezone(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	// A location shared after this sets the time zone, until something else is done
+	user.Session.State = model.StateSharingTimezoneLocation
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	parts := strings.Fields(ctx.Message.Text)
+	if len(parts) < 2 {
+		text := fmt.Sprintf("🕐 <b>Time zone</b>\n\nYour time zone is <b>%s</b>, it's %s there.\n\n%s",
+			html.EscapeString(utils.FormatTimezone(user.Timezone)), user.Now().Format("15:04 on Mon 02 Jan"), timezoneHelp)
+		return c.sendTimezoneMessage(b, ctx, text, locationKeyboard())
+	}
+
+	timezone, err := utils.ParseTimezone(strings.Join(parts[1:], " "))
+	if err != nil {
+		text := "⚠️ I don't know this time zone. Use a name like <code>Europe/Rome</code> or <code>America/New_York</code>, " +
+			"an offset like <code>UTC+2</code>, or share your location."
+		return c.sendTimezoneMessage(b, ctx, text, locationKeyboard())
+	}
+
+	return c.setTimezone(b, ctx, user, timezone, "")
+}
+
+// TimezoneFromLocation sets the time zone of the user from the location shared with the /timezone keyboard.
+// Locations shared otherwise are ignored.
+func (c *Client) TimezoneFromLocation(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	if user.Session.State != model.StateSharingTimezoneLocation {
+		return nil
+	}
+
+	// The offset is only a guess from the longitude: whole hours and no daylight saving time, and wrong
+	// wherever the official time differs from the sun's, like Spain, France or India
+	timezone := utils.TimezoneFromLongitude(ctx.Message.Location.Longitude)
+	note := "\n\n⚠️ This is only a guess from your longitude: it's a whole-hour offset without daylight saving time, " +
+		"and it can be off where the local time isn't the sun's, like in Spain, France or India. " +
+		"If it's wrong, set it by name, e.g. <code>/timezone Europe/Madrid</code>."
+
+	return c.setTimezone(b, ctx, user, timezone, note)
+}
+
+// setTimezone saves the time zone of the user and confirms it with its current time
+func (c *Client) setTimezone(b *gotgbot.Bot, ctx *ext.Context, user model.User, timezone string, note string) error {
+	user.Timezone = timezone
+	user.Session.State = model.StateNormal
+	err := c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	// Recaps are sent on the user's day and hour in their time zone, they're created again accordingly
+	if err := c.Repositories.Reminders.CancelUpcomingRecaps(user.TgID, time.Now()); err != nil {
+		return fmt.Errorf("failed to cancel upcoming recaps: %w", err)
+	}
+
+	text := fmt.Sprintf("✅ Time zone set to <b>%s</b>, it's %s there.%s",
+		html.EscapeString(utils.FormatTimezone(timezone)), user.Now().Format("15:04 on Mon 02 Jan"), note)
+	return c.sendTimezoneMessage(b, ctx, text, gotgbot.ReplyKeyboardRemove{RemoveKeyboard: true})
+}
+
+// sendTimezoneMessage sends a time zone message with a reply keyboard, which can't be edited in
+func (c *Client) sendTimezoneMessage(b *gotgbot.Bot, ctx *ext.Context, text string, keyboard gotgbot.ReplyMarkup) error {
+	_, err := b.SendMessage(ctx.EffectiveSender.ChatId, text, &gotgbot.SendMessageOpts{
+		ParseMode:   "HTML",
+		ReplyMarkup: keyboard,
+	})
+	return err
+}
+
+// locationKeyboard asks the user to share their location
+func locationKeyboard() gotgbot.ReplyKeyboardMarkup {
+	return gotgbot.ReplyKeyboardMarkup{
+		Keyboard: [][]gotgbot.KeyboardButton{
+			{{Text: "📍 Share my location", RequestLocation: true}},
+		},
+		ResizeKeyboard:  true,
+		OneTimeKeyboard: true,
+	}
+}
@@ -0,0 +1,158 @@
+package client
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/repository"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+)
+
+// Token handles the /token command: it lists the user's API tokens with buttons to create and revoke them.
+// "/token new [read|rw] [name]" creates a token directly.
+func (c *Client) Token(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	user.Session.State = model.StateNormal
+	err = c.Repositories.Users.Update(&user)
+	if err != nil {
+		return fmt.Errorf("failed to set user data: %w", err)
+	}
+
+	if ctx.Message != nil {
+		parts := strings.Fields(ctx.Message.Text)
+		if len(parts) > 1 && strings.ToLower(parts[1]) == "new" {
+			scope := model.APITokenScopeRead
+			name := ""
+			if len(parts) > 2 {
+				scope, err = model.ParseAPITokenScope(parts[2])
+				if err != nil {
+					// No scope given, it's part of the name
+					scope = model.APITokenScopeRead
+					name = strings.Join(parts[2:], " ")
+				} else {
+					name = strings.Join(parts[3:], " ")
+				}
+			}
+			return c.createToken(b, ctx, user, scope, name)
+		}
+	}
+
+	return c.showTokens(b, ctx, user)
+}
+
+// TokenSelected handles the token buttons (format: token.new.SCOPE, token.revoke.TOKEN_ID or token.list)
+func (c *Client) TokenSelected(b *gotgbot.Bot, ctx *ext.Context) error {
+	_, u := c.getUserFromContext(ctx)
+	user, err := c.authAndGetUser(u)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid callback data format")
+	}
+
+	switch parts[1] {
+	case "new":
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid callback data format")
+		}
+		scope, err := model.ParseAPITokenScope(parts[2])
+		if err != nil {
+			return err
+		}
+		return c.createToken(b, ctx, user, scope, "")
+	case "revoke":
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid callback data format")
+		}
+		tokenID, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid token ID: %v", err)
+		}
+		if err := c.Repositories.APITokens.Revoke(tokenID, user.TgID); err != nil {
+			return fmt.Errorf("failed to revoke token: %w", err)
+		}
+		return c.showTokens(b, ctx, user)
+	default:
+		return c.showTokens(b, ctx, user)
+	}
+}
+
+// showTokens lists the active API tokens of the user
+func (c *Client) showTokens(b *gotgbot.Bot, ctx *ext.Context, user model.User) error {
+	tokens, err := c.Repositories.APITokens.GetUserTokens(user.TgID)
+	if err != nil {
+		return fmt.Errorf("failed to get tokens: %w", err)
+	}
+
+	var text strings.Builder
+	text.WriteString("🔑 <b>API Tokens</b>\n\n")
+	text.WriteString("Personal access tokens let your scripts use the Cashout API (<code>/api/v1</code>). ")
+	text.WriteString("Read-only tokens can fetch data, read-write tokens can also add, edit and delete transactions.\n\n")
+
+	if len(tokens) == 0 {
+		text.WriteString("You don't have any tokens yet.\n")
+	}
+
+	var keyboard [][]gotgbot.InlineKeyboardButton
+	for _, t := range tokens {
+		text.WriteString(fmt.Sprintf("• <b>%s</b> <code>%s</code> (%s)\n", html.EscapeString(t.Name), t.Hint, t.Scope.Label()))
+		text.WriteString(fmt.Sprintf("   Created %s", t.CreatedAt.Format("02-01-2006")))
+		if t.LastUsedAt != nil {
+			text.WriteString(fmt.Sprintf(", last used %s", t.LastUsedAt.Format("02-01-2006")))
+		}
+		text.WriteString("\n")
+
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: fmt.Sprintf("🗑 Revoke %s", t.Name), CallbackData: fmt.Sprintf("token.revoke.%d", t.ID)},
+		})
+	}
+
+	text.WriteString("\nTip: name a token with <code>/token new rw my script</code>")
+
+	if len(tokens) < repository.MaxAPITokens {
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: "➕ Read-only", CallbackData: "token.new." + string(model.APITokenScopeRead)},
+			{Text: "➕ Read-write", CallbackData: "token.new." + string(model.APITokenScopeReadWrite)},
+		})
+	}
+
+	return SendMessage(ctx, b, text.String(), keyboard)
+}
+
+// createToken creates an API token and shows it, the only time it's visible
+func (c *Client) createToken(b *gotgbot.Bot, ctx *ext.Context, user model.User, scope model.APITokenScope, name string) error {
+	if strings.TrimSpace(name) == "" {
+		name = fmt.Sprintf("Telegram %s", user.Now().Format("2006-01-02 15:04"))
+	}
+
+	secret, token, err := c.Repositories.APITokens.Create(user.TgID, name, scope)
+	if err != nil {
+		if errors.Is(err, repository.ErrTooManyAPITokens) {
+			return SendMessage(ctx, b, fmt.Sprintf("⚠️ You can have at most %d tokens, revoke one first.", repository.MaxAPITokens), [][]gotgbot.InlineKeyboardButton{
+				{{Text: "🔑 My Tokens", CallbackData: "token.list"}},
+			})
+		}
+		return fmt.Errorf("failed to create token: %w", err)
+	}
+
+	text := fmt.Sprintf("✅ <b>Token created</b>\n\n<b>%s</b> (%s)\n\n<code>%s</code>\n\n"+
+		"Copy it now, it won't be shown again. Use it as <code>Authorization: Bearer &lt;token&gt;</code>.",
+		html.EscapeString(token.Name), token.Scope.Label(), secret)
+
+	return SendMessage(ctx, b, text, [][]gotgbot.InlineKeyboardButton{
+		{{Text: "🔑 My Tokens", CallbackData: "token.list"}},
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
@@ -75,7 +74,7 @@ func (c *Client) addTransaction(b *gotgbot.Bot, ctx *ext.Context, user model.Use
 		return err
 	}
 
-	transaction, err := c.LLM.ExtractTransaction(ctx.Message.Text, transactionType)
+	transaction, err := c.LLM.ExtractTransaction(ctx.Message.Text, transactionType, user.Today())
 	if err != nil {
 		msg := "I'm sorry, I couldn't understand your transaction!"
 		_, errm := ctx.EffectiveMessage.Reply(b, msg, &gotgbot.SendMessageOpts{
@@ -283,7 +282,7 @@ func (c *Client) editTransactionDate(b *gotgbot.Bot, ctx *ext.Context, user mode
 	}
 
 	// Get date from DD-MM-YYYY to date
-	date, err := utils.ParseDate(ctx.Message.Text)
+	date, err := utils.ParseDateInYear(ctx.Message.Text, user.Now().Year())
 	if err != nil {
 		fmt.Printf("failed to parse date: %v\n", err)
 		_, errm := b.SendMessage(ctx.EffectiveSender.ChatId, "Invalid date, please try again.", nil)
@@ -291,7 +290,7 @@ func (c *Client) editTransactionDate(b *gotgbot.Bot, ctx *ext.Context, user mode
 		return err
 	}
 
-	if date.After(time.Now()) {
+	if date.After(user.Today()) {
 		_, err := b.SendMessage(ctx.EffectiveSender.ChatId, "I don't support future dates, please try again.", nil)
 		return errors.Join(err, fmt.Errorf("invalid date: %s", ctx.Message.Text))
 	}
@@ -601,7 +600,7 @@ func (c *Client) Confirm(b *gotgbot.Bot, ctx *ext.Context) error {
 	transaction.TgID = user.TgID
 	transaction.Currency = model.CurrencyEUR
 
-	err = c.Repositories.Transactions.Add(transaction)
+	err = c.Repositories.Transactions.Add(&transaction)
 	if err != nil {
 		err = errors.Join(err, SendMessage(ctx, b, "There has been an error saving your transaction, please retry", nil))
 		c.Logger.Errorln("failed to add transaction", err)
@@ -643,7 +642,17 @@ func (c *Client) Confirm(b *gotgbot.Bot, ctx *ext.Context) error {
 	if transaction.Type == model.TypeExpense {
 		emoji = "💸"
 	}
-	return c.SendHomeKeyboard(b, ctx, fmt.Sprintf("%s Your transaction has been saved!", emoji))
+	err = c.SendHomeKeyboard(b, ctx, fmt.Sprintf("%s Your transaction has been saved!", emoji))
+	if err != nil {
+		return err
+	}
+
+	// A failed check shouldn't look like a failed save
+	if err := c.checkTransactionAnomalies(b, user, transaction); err != nil {
+		c.Logger.Errorln("failed to check transaction anomalies", err)
+	}
+
+	return nil
 }
 
 // Cancel returns to normal state.
@@ -662,7 +671,7 @@ func (c *Client) Cancel(b *gotgbot.Bot, ctx *ext.Context) error {
 	}
 
 	err = c.CleanupKeyboard(b, ctx)
-	err = errors.Join(err, c.SendHomeKeyboard(b, ctx, "Your operation has been canceled!\nWhat else can I do for you?\n\n/edit - Edit a transaction\n/delete - Delete a transaction\n/search - Search transactions\n/list - List your transactions\n/week Week Recap\n/month Month Recap\n/year Year Recap\n/export - Export all transactions to CSV"))
+	err = errors.Join(err, c.SendHomeKeyboard(b, ctx, "Your operation has been canceled!\nWhat else can I do for you?\n\n/edit - Edit a transaction\n/delete - Delete a transaction\n/search - Search transactions\n/list - List your transactions\n/week Week Recap\n/month Month Recap\n/year Year Recap\n/recap Custom Range Recap\n/compare Compare with previous periods\n/export - Export all transactions to CSV\n/token - Manage API tokens\n/sessions - Manage web sessions\n/settings - Recap notifications\n/bills - Bill reminders\n/timezone - Set your time zone"))
 
 	return err
 }
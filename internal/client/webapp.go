@@ -0,0 +1,39 @@
+package client
+
+import (
+	"strings"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// isMiniAppURL checks if the dashboard can open as a Mini App, which Telegram only allows over HTTPS
+func isMiniAppURL(url string) bool {
+	return strings.HasPrefix(url, "https://")
+}
+
+// webDashboardButton opens the dashboard inside Telegram as a Mini App, or in the browser for a plain HTTP URL
+func (c *Client) webDashboardButton() gotgbot.InlineKeyboardButton {
+	if isMiniAppURL(c.Config.WebDashboardUrl) {
+		return gotgbot.InlineKeyboardButton{
+			Text:   "🌐 Web Dashboard",
+			WebApp: &gotgbot.WebAppInfo{Url: c.Config.WebDashboardUrl},
+		}
+	}
+	return gotgbot.InlineKeyboardButton{Text: "🌐 Web Dashboard", Url: c.Config.WebDashboardUrl}
+}
+
+// SetupMenuButton makes the menu button next to the chat input open the dashboard Mini App
+func (c *Client) SetupMenuButton(b *gotgbot.Bot) error {
+	if !isMiniAppURL(c.Config.WebDashboardUrl) {
+		c.Logger.Infof("Web dashboard URL is not HTTPS, the Mini App menu button is disabled")
+		return nil
+	}
+
+	_, err := b.SetChatMenuButton(&gotgbot.SetChatMenuButtonOpts{
+		MenuButton: gotgbot.MenuButtonWebApp{
+			Text:   "Dashboard",
+			WebApp: gotgbot.WebAppInfo{Url: c.Config.WebDashboardUrl},
+		},
+	})
+	return err
+}
@@ -29,7 +29,7 @@ func (c *Client) WeekRecap(b *gotgbot.Bot, ctx *ext.Context) error {
 	}
 
 	// Get current week boundaries (Monday to Sunday)
-	now := time.Now()
+	now := user.Now()
 	weekday := int(now.Weekday())
 	// If Sunday (0), make it 7 for calculation
 	if weekday == 0 {
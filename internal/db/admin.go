@@ -0,0 +1,204 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserActivity is a user with how much they use the bot
+type UserActivity struct {
+	TgID         int64
+	TgUsername   string
+	Name         string
+	BotBlockedAt *time.Time
+	CreatedAt    time.Time
+	// Transactions in total and recorded since the given time
+	Transactions       int64
+	RecentTransactions int64
+	LastTransactionAt  *time.Time
+}
+
+// UsersSummary counts the users, those who recorded transactions since the given time and those who blocked the bot
+type UsersSummary struct {
+	Total   int64
+	Active  int64
+	Blocked int64
+}
+
+// ReminderStatusCount is the number of reminders of a type in a status
+type ReminderStatusCount struct {
+	Type   model.ReminderType
+	Status model.ReminderStatus
+	Count  int64
+}
+
+// AddAllowedUser allows a username at runtime, false if it already was
+func (db *DB) AddAllowedUser(username string, addedBy int64) (bool, error) {
+	result := db.conn.Exec(`
+		INSERT INTO allowed_users (username, added_by, created_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (username) DO NOTHING
+	`, username, addedBy)
+	return result.RowsAffected > 0, result.Error
+}
+
+// AddAllowedTgID allows a Telegram ID at runtime, false if it already was
+func (db *DB) AddAllowedTgID(tgID int64, addedBy int64) (bool, error) {
+	result := db.conn.Exec(`
+		INSERT INTO allowed_users (tg_id, added_by, created_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (tg_id) DO NOTHING
+	`, tgID, addedBy)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteAllowedTgID removes a Telegram ID allowed at runtime, false if it wasn't
+func (db *DB) DeleteAllowedTgID(tgID int64) (bool, error) {
+	result := db.conn.Where("tg_id = ?", tgID).Delete(&model.AllowedUser{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteAllowedUser removes a username allowed at runtime, false if it wasn't
+func (db *DB) DeleteAllowedUser(username string) (bool, error) {
+	result := db.conn.Where("username = ?", username).Delete(&model.AllowedUser{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetAllowedUser retrieves a user allowed at runtime
+func (db *DB) GetAllowedUser(id int64) (*model.AllowedUser, error) {
+	var allowed model.AllowedUser
+	result := db.conn.First(&allowed, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &allowed, nil
+}
+
+// DeleteAllowedUserByID removes a user allowed at runtime, false if they weren't
+func (db *DB) DeleteAllowedUserByID(id int64) (bool, error) {
+	result := db.conn.Delete(&model.AllowedUser{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetAllowedUsers retrieves the users allowed at runtime
+func (db *DB) GetAllowedUsers() ([]model.AllowedUser, error) {
+	var users []model.AllowedUser
+	result := db.conn.Order("username NULLS LAST, created_at").Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return users, nil
+}
+
+// IsAllowedUser checks whether a user was allowed at runtime, by Telegram ID or username (if they have one)
+func (db *DB) IsAllowedUser(tgID int64, username string) (bool, error) {
+	var count int64
+	query := db.conn.Model(&model.AllowedUser{}).Where("tg_id = ?", tgID)
+	if username != "" {
+		query = query.Or("username = ?", username)
+	}
+	result := query.Count(&count)
+	return count > 0, result.Error
+}
+
+// GetUsersActivity retrieves the users with their transactions, the most recently active first
+func (db *DB) GetUsersActivity(since time.Time, limit int) ([]UserActivity, error) {
+	var results []UserActivity
+	result := db.conn.Raw(`
+		SELECT u.tg_id, u.tg_username, u.name, u.bot_blocked_at, u.created_at,
+			COUNT(t.id) AS transactions,
+			COUNT(t.id) FILTER (WHERE t.created_at >= ?) AS recent_transactions,
+			MAX(t.created_at) AS last_transaction_at
+		FROM users u
+		LEFT JOIN transactions t ON t.tg_id = u.tg_id
+		GROUP BY u.tg_id
+		ORDER BY last_transaction_at DESC NULLS LAST, u.created_at DESC
+		LIMIT ?
+	`, since, limit).Scan(&results)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return results, nil
+}
+
+// GetUsersSummary counts the users, those active since a time and those who blocked the bot
+func (db *DB) GetUsersSummary(since time.Time) (UsersSummary, error) {
+	var summary UsersSummary
+	result := db.conn.Raw(`
+		SELECT COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM transactions t WHERE t.tg_id = u.tg_id AND t.created_at >= ?
+			)) AS active,
+			COUNT(*) FILTER (WHERE u.bot_blocked_at IS NOT NULL) AS blocked
+		FROM users u
+	`, since).Scan(&summary)
+
+	return summary, result.Error
+}
+
+// GetReminderStatusCounts counts the reminders not sent yet or given up on, by type and status
+func (db *DB) GetReminderStatusCounts() ([]ReminderStatusCount, error) {
+	var results []ReminderStatusCount
+	result := db.conn.Model(&model.Reminder{}).
+		Select("type, status, COUNT(*) AS count").
+		Where("status <> ?", model.ReminderStatusSent).
+		Group("type, status").
+		Order("type, status").
+		Scan(&results)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return results, nil
+}
+
+// GetFailedReminders retrieves the reminders waiting for a retry or given up on, the latest first
+func (db *DB) GetFailedReminders(limit int) ([]model.Reminder, error) {
+	var reminders []model.Reminder
+	result := db.conn.Where("status IN ?", []model.ReminderStatus{model.ReminderStatusFailed, model.ReminderStatusDead}).
+		Order("updated_at DESC").
+		Limit(limit).
+		Find(&reminders)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return reminders, nil
+}
+
+// CreateBroadcast stores an announcement and queues a broadcast reminder for every user the bot can write to,
+// all at once. It sets the number of recipients.
+func (db *DB) CreateBroadcast(broadcast *model.Broadcast, scheduledFor time.Time) error {
+	return db.conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(broadcast).Error; err != nil {
+			return err
+		}
+
+		result := tx.Exec(`
+			INSERT INTO reminders (tg_id, type, broadcast_id, status, scheduled_for, created_at, updated_at)
+			SELECT tg_id, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+			FROM users
+			WHERE bot_blocked_at IS NULL
+			ON CONFLICT DO NOTHING
+		`, model.ReminderTypeBroadcast, broadcast.ID, model.ReminderStatusPending, scheduledFor)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		broadcast.Recipients = int(result.RowsAffected)
+		return tx.Model(broadcast).Update("recipients", broadcast.Recipients).Error
+	})
+}
+
+// GetBroadcast retrieves an announcement
+func (db *DB) GetBroadcast(id int64) (*model.Broadcast, error) {
+	var broadcast model.Broadcast
+	result := db.conn.First(&broadcast, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &broadcast, nil
+}
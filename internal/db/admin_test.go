@@ -0,0 +1,117 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"testing"
+	"time"
+)
+
+func TestCreateBroadcast(t *testing.T) {
+	db := testDB(t)
+
+	const tgID, blockedTgID = -4801, -4802
+	testUser(t, db, tgID)
+	testUser(t, db, blockedTgID)
+	if err := db.SetUserBotBlocked(blockedTgID, &time.Time{}); err != nil {
+		t.Fatalf("SetUserBotBlocked() error = %v", err)
+	}
+
+	broadcast := &model.Broadcast{Message: "New feature", CreatedBy: tgID}
+	t.Cleanup(func() {
+		db.conn.Delete(&model.Broadcast{}, broadcast.ID)
+	})
+	if err := db.CreateBroadcast(broadcast, time.Now().UTC()); err != nil {
+		t.Fatalf("CreateBroadcast() error = %v", err)
+	}
+
+	// Other users may be in the database, the recipients are all those the bot can write to
+	var recipients int64
+	db.conn.Model(&model.User{}).Where("bot_blocked_at IS NULL").Count(&recipients)
+	if int64(broadcast.Recipients) != recipients {
+		t.Errorf("Recipients = %d, want %d", broadcast.Recipients, recipients)
+	}
+
+	var own, blocked int64
+	db.conn.Model(&model.Reminder{}).Where("broadcast_id = ? AND tg_id = ?", broadcast.ID, tgID).Count(&own)
+	db.conn.Model(&model.Reminder{}).Where("broadcast_id = ? AND tg_id = ?", broadcast.ID, blockedTgID).Count(&blocked)
+	if own != 1 || blocked != 0 {
+		t.Errorf("queued %d reminders for the user and %d for the one who blocked the bot, want 1 and 0", own, blocked)
+	}
+}
+
+func TestAllowedUsers(t *testing.T) {
+	db := testDB(t)
+
+	const username = "test_allowed_user"
+	t.Cleanup(func() {
+		db.conn.Where("username = ?", username).Delete(&model.AllowedUser{})
+	})
+
+	if added, err := db.AddAllowedUser(username, 1); err != nil || !added {
+		t.Fatalf("AddAllowedUser() = %v, %v, want added", added, err)
+	}
+	if added, err := db.AddAllowedUser(username, 1); err != nil || added {
+		t.Errorf("AddAllowedUser() again = %v, %v, want not added", added, err)
+	}
+	if allowed, err := db.IsAllowedUser(0, username); err != nil || !allowed {
+		t.Errorf("IsAllowedUser() = %v, %v, want true", allowed, err)
+	}
+
+	if removed, err := db.DeleteAllowedUser(username); err != nil || !removed {
+		t.Fatalf("DeleteAllowedUser() = %v, %v, want removed", removed, err)
+	}
+	if allowed, err := db.IsAllowedUser(0, username); err != nil || allowed {
+		t.Errorf("IsAllowedUser() after removing = %v, %v, want false", allowed, err)
+	}
+
+	const tgID = -4701
+	t.Cleanup(func() {
+		db.conn.Where("tg_id = ?", tgID).Delete(&model.AllowedUser{})
+	})
+	if added, err := db.AddAllowedTgID(tgID, 1); err != nil || !added {
+		t.Fatalf("AddAllowedTgID() = %v, %v, want added", added, err)
+	}
+	if allowed, err := db.IsAllowedUser(tgID, "someone_else"); err != nil || !allowed {
+		t.Errorf("IsAllowedUser() by Telegram ID = %v, %v, want true", allowed, err)
+	}
+	if removed, err := db.DeleteAllowedTgID(tgID); err != nil || !removed {
+		t.Errorf("DeleteAllowedTgID() = %v, %v, want removed", removed, err)
+	}
+}
+
+func TestRedeemInviteCode(t *testing.T) {
+	db := testDB(t)
+
+	const firstTgID, secondTgID, thirdTgID = -4901, -4902, -4903
+	now := time.Now().UTC()
+	expiresAt := now.Add(time.Hour)
+	invite := &model.InviteCode{Code: "TESTREDEEMCODE", MaxUses: 2, CreatedBy: 1, ExpiresAt: &expiresAt}
+	if err := db.CreateInviteCode(invite); err != nil {
+		t.Fatalf("CreateInviteCode() error = %v", err)
+	}
+	t.Cleanup(func() {
+		db.conn.Where("invite_code_id = ?", invite.ID).Delete(&model.AllowedUser{})
+		db.conn.Delete(&model.InviteCode{}, invite.ID)
+	})
+
+	if redeemed, err := db.RedeemInviteCode(invite.Code, firstTgID, "", now); err != nil || !redeemed {
+		t.Fatalf("RedeemInviteCode() = %v, %v, want redeemed", redeemed, err)
+	}
+	if allowed, err := db.IsAllowedUser(firstTgID, ""); err != nil || !allowed {
+		t.Errorf("IsAllowedUser() after redeeming = %v, %v, want true", allowed, err)
+	}
+
+	if redeemed, err := db.RedeemInviteCode(invite.Code, secondTgID, "test_invited_user", now); err != nil || !redeemed {
+		t.Fatalf("RedeemInviteCode() second use = %v, %v, want redeemed", redeemed, err)
+	}
+	if redeemed, err := db.RedeemInviteCode(invite.Code, thirdTgID, "", now); err != nil || redeemed {
+		t.Errorf("RedeemInviteCode() beyond max uses = %v, %v, want not redeemed", redeemed, err)
+	}
+	if allowed, err := db.IsAllowedUser(thirdTgID, ""); err != nil || allowed {
+		t.Errorf("IsAllowedUser() without a use left = %v, %v, want false", allowed, err)
+	}
+
+	if redeemed, err := db.RedeemInviteCode("UNKNOWNCODE", thirdTgID, "", now); err != nil || redeemed {
+		t.Errorf("RedeemInviteCode() unknown code = %v, %v, want not redeemed", redeemed, err)
+	}
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"cashout/internal/model"
+)
+
+// CreateAnomaly stores an anomaly unless one with the same key exists for the user.
+// It returns false if the anomaly was already known.
+func (db *DB) CreateAnomaly(anomaly *model.Anomaly) (bool, error) {
+	result := db.conn.Raw(`
+		INSERT INTO anomalies (tg_id, key, kind, status, transaction_id, category, amount, typical, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT (tg_id, key) DO NOTHING
+		RETURNING id
+	`, anomaly.TgID, anomaly.Key, anomaly.Kind, model.AnomalyStatusNotified, anomaly.TransactionID, anomaly.Category, anomaly.Amount, anomaly.Typical).
+		Scan(&anomaly.ID)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateAnomalyStatus updates the status of a user's anomaly
+func (db *DB) UpdateAnomalyStatus(id int64, tgID int64, status model.AnomalyStatus) error {
+	return db.conn.Model(&model.Anomaly{}).
+		Where("id = ? AND tg_id = ?", id, tgID).
+		Update("status", status).Error
+}
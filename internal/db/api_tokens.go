@@ -0,0 +1,65 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"fmt"
+	"time"
+)
+
+// CreateAPIToken creates a new API token
+func (db *DB) CreateAPIToken(token *model.APIToken) error {
+	return db.conn.Create(token).Error
+}
+
+// GetAPITokenByHash retrieves an API token by the hash of the token
+func (db *DB) GetAPITokenByHash(hash string) (*model.APIToken, error) {
+	var token model.APIToken
+	result := db.conn.Where("token_hash = ?", hash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// GetUserAPITokens retrieves the tokens of a user that haven't been revoked
+func (db *DB) GetUserAPITokens(tgID int64) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	result := db.conn.Where("tg_id = ? AND revoked_at IS NULL", tgID).
+		Order("created_at DESC").
+		Find(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return tokens, nil
+}
+
+// RevokeAPIToken marks a user's token as revoked
+func (db *DB) RevokeAPIToken(id int64, tgID int64) error {
+	result := db.conn.Model(&model.APIToken{}).
+		Where("id = ? AND tg_id = ? AND revoked_at IS NULL", id, tgID).
+		Update("revoked_at", time.Now().UTC())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("token not found or doesn't belong to user")
+	}
+
+	return nil
+}
+
+// RevokeUserAPITokens revokes all the tokens of a user
+func (db *DB) RevokeUserAPITokens(tgID int64, revokedAt time.Time) error {
+	return db.conn.Model(&model.APIToken{}).
+		Where("tg_id = ? AND revoked_at IS NULL", tgID).
+		Update("revoked_at", revokedAt).Error
+}
+
+// TouchAPIToken records the last time a token was used
+func (db *DB) TouchAPIToken(id int64, usedAt time.Time) error {
+	return db.conn.Model(&model.APIToken{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", usedAt).Error
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"cashout/internal/model"
+	"fmt"
 	"time"
 )
 
@@ -55,6 +56,72 @@ func (db *DB) DeleteWebSession(sessionID string) error {
 	return db.conn.Delete(&model.WebSession{}, "id = ?", sessionID).Error
 }
 
+// GetUserWebSessions retrieves the active web sessions of a user, most recently used first
+func (db *DB) GetUserWebSessions(tgID int64) ([]model.WebSession, error) {
+	var sessions []model.WebSession
+	result := db.conn.Where("tg_id = ? AND expires_at > ?", tgID, time.Now().UTC()).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return sessions, nil
+}
+
+// DeleteUserWebSession deletes a user's web session by its public ID
+func (db *DB) DeleteUserWebSession(publicID int64, tgID int64) error {
+	result := db.conn.Delete(&model.WebSession{}, "public_id = ? AND tg_id = ?", publicID, tgID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("session not found or doesn't belong to user")
+	}
+
+	return nil
+}
+
+// DeleteUserWebSessionsExcept deletes all the web sessions of a user but one, returning how many were deleted
+func (db *DB) DeleteUserWebSessionsExcept(tgID int64, sessionID string) (int64, error) {
+	result := db.conn.Delete(&model.WebSession{}, "tg_id = ? AND id <> ?", tgID, sessionID)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteUserWebSessions deletes all the web sessions of a user, logging them out everywhere
+func (db *DB) DeleteUserWebSessions(tgID int64) error {
+	return db.conn.Delete(&model.WebSession{}, "tg_id = ?", tgID).Error
+}
+
+// TouchWebSession records the last time and address a web session was used from
+func (db *DB) TouchWebSession(sessionID string, seenAt time.Time, ipAddress string) error {
+	return db.conn.Model(&model.WebSession{}).
+		Where("id = ?", sessionID).
+		UpdateColumns(map[string]interface{}{
+			"last_seen_at": seenAt,
+			"ip_address":   ipAddress,
+		}).Error
+}
+
+// RotateWebSession replaces the ID of a web session
+func (db *DB) RotateWebSession(oldID, newID string, rotatedAt time.Time) error {
+	result := db.conn.Model(&model.WebSession{}).
+		Where("id = ?", oldID).
+		UpdateColumns(map[string]interface{}{
+			"id":         newID,
+			"rotated_at": rotatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("session not found")
+	}
+
+	return nil
+}
+
 // CleanupExpiredAuthData removes expired auth tokens and sessions
 func (db *DB) CleanupExpiredAuthData() error {
 	now := time.Now().UTC()
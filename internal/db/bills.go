@@ -0,0 +1,82 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreateBill creates a new bill
+func (db *DB) CreateBill(bill *model.Bill) error {
+	return db.conn.Create(bill).Error
+}
+
+// GetUserBill retrieves a bill of a user
+func (db *DB) GetUserBill(id int64, tgID int64) (*model.Bill, error) {
+	var bill model.Bill
+	result := db.conn.Where("id = ? AND tg_id = ?", id, tgID).First(&bill)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &bill, nil
+}
+
+// GetUserBills retrieves the bills of a user, the next due first
+func (db *DB) GetUserBills(tgID int64) ([]model.Bill, error) {
+	var bills []model.Bill
+	result := db.conn.Where("tg_id = ?", tgID).
+		Order("next_due_date, name").
+		Find(&bills)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return bills, nil
+}
+
+// UpdateBill saves the changes to a bill
+func (db *DB) UpdateBill(bill *model.Bill) error {
+	return db.conn.Save(bill).Error
+}
+
+// DeleteBill deletes a user's bill, with its reminders
+func (db *DB) DeleteBill(id int64, tgID int64) error {
+	result := db.conn.Where("id = ? AND tg_id = ?", id, tgID).Delete(&model.Bill{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("bill not found or doesn't belong to user")
+	}
+
+	return nil
+}
+
+// PayBill records the payment of a bill due on a date, all at once: it adds the expense transaction,
+// moves the bill to its next due date and reminder time and deletes its pending reminders.
+// It's false, with nothing recorded, when the bill isn't due on that date anymore, e.g. it was already paid.
+func (db *DB) PayBill(bill *model.Bill, paidDueDate time.Time, transaction *model.Transaction) (bool, error) {
+	paid := false
+	err := db.conn.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Bill{}).
+			Where("id = ? AND tg_id = ? AND next_due_date = ?", bill.ID, bill.TgID, paidDueDate).
+			Updates(map[string]interface{}{
+				"next_due_date": bill.NextDueDate,
+				"remind_at":     bill.RemindAt,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+
+		paid = true
+		return tx.Where("bill_id = ? AND status = ?", bill.ID, model.ReminderStatusPending).
+			Delete(&model.Reminder{}).Error
+	})
+	return paid && err == nil, err
+}
@@ -0,0 +1,143 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"testing"
+	"time"
+)
+
+// testBill creates a monthly bill of a user, due on a date
+func testBill(t *testing.T, db *DB, tgID int64, name string, due time.Time) *model.Bill {
+	t.Helper()
+
+	bill := &model.Bill{
+		TgID:             tgID,
+		Name:             name,
+		Amount:           29.90,
+		Category:         model.CategoryBills,
+		Frequency:        model.BillFrequencyMonthly,
+		DueDay:           due.Day(),
+		RemindDaysBefore: model.DefaultBillRemindDaysBefore,
+		NextDueDate:      due,
+		RemindAt:         due.AddDate(0, 0, -model.DefaultBillRemindDaysBefore),
+	}
+	if err := db.CreateBill(bill); err != nil {
+		t.Fatalf("CreateBill() error = %v", err)
+	}
+	return bill
+}
+
+func TestUpsertBillReminders(t *testing.T) {
+	db := testDB(t)
+
+	const tgID = -4701
+	testUser(t, db, tgID)
+
+	due := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	internet := testBill(t, db, tgID, "internet", due)
+	phone := testBill(t, db, tgID, "phone", due)
+
+	// Bills due at the same time have a reminder each, created once however often they're scheduled
+	for i := 0; i < 2; i++ {
+		for _, bill := range []*model.Bill{internet, phone} {
+			reminder := model.Reminder{TgID: tgID, Type: model.ReminderTypeBillDue, BillID: &bill.ID, ScheduledFor: bill.RemindAt}
+			if err := db.UpsertReminder(reminder); err != nil {
+				t.Fatalf("UpsertReminder() error = %v", err)
+			}
+		}
+	}
+
+	var count int64
+	db.conn.Model(&model.Reminder{}).Where("tg_id = ? AND type = ?", tgID, model.ReminderTypeBillDue).Count(&count)
+	if count != 2 {
+		t.Errorf("created %d bill reminders, want 2", count)
+	}
+}
+
+func TestPayBillOnce(t *testing.T) {
+	db := testDB(t)
+
+	const tgID = -4702
+	testUser(t, db, tgID)
+	t.Cleanup(func() {
+		db.conn.Where("tg_id = ?", tgID).Delete(&model.Transaction{})
+	})
+
+	due := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+	bill := testBill(t, db, tgID, "internet", due)
+	if err := db.UpsertReminder(model.Reminder{TgID: tgID, Type: model.ReminderTypeBillDue, BillID: &bill.ID, ScheduledFor: bill.RemindAt}); err != nil {
+		t.Fatalf("UpsertReminder() error = %v", err)
+	}
+
+	pay := func() bool {
+		t.Helper()
+		next := *bill
+		next.NextDueDate = next.FollowingDueDate()
+		next.RemindAt = next.NextDueDate.AddDate(0, 0, -next.RemindDaysBefore)
+		transaction := &model.Transaction{
+			TgID: tgID, Date: due, Type: model.TypeExpense, Category: bill.Category,
+			Amount: bill.Amount, Currency: model.CurrencyEUR, Description: bill.Name,
+		}
+		paid, err := db.PayBill(&next, due, transaction)
+		if err != nil {
+			t.Fatalf("PayBill() error = %v", err)
+		}
+		return paid
+	}
+
+	if !pay() {
+		t.Fatal("first PayBill() = false, want true")
+	}
+	// E.g. the button pressed twice
+	if pay() {
+		t.Error("second PayBill() = true, want false")
+	}
+
+	var transactions, reminders int64
+	db.conn.Model(&model.Transaction{}).Where("tg_id = ?", tgID).Count(&transactions)
+	db.conn.Model(&model.Reminder{}).Where("bill_id = ?", bill.ID).Count(&reminders)
+	if transactions != 1 {
+		t.Errorf("added %d transactions, want 1", transactions)
+	}
+	if reminders != 0 {
+		t.Errorf("%d reminders left for the paid bill, want none", reminders)
+	}
+
+	paid, err := db.GetUserBill(bill.ID, tgID)
+	if err != nil {
+		t.Fatalf("GetUserBill() error = %v", err)
+	}
+	if want := time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC); !paid.NextDueDate.Equal(want) {
+		t.Errorf("next due date = %v, want %v", paid.NextDueDate, want)
+	}
+}
+
+func TestDeleteUpcomingRemindersKeepsOtherTypes(t *testing.T) {
+	db := testDB(t)
+
+	const tgID = -4703
+	testUser(t, db, tgID)
+
+	now := time.Now().UTC()
+	bill := testBill(t, db, tgID, "rent", now.AddDate(0, 0, 10))
+	for _, reminder := range []model.Reminder{
+		{TgID: tgID, Type: model.ReminderTypeWeeklyRecap, ScheduledFor: now.Add(time.Hour)},
+		{TgID: tgID, Type: model.ReminderTypeBillDue, BillID: &bill.ID, ScheduledFor: now.Add(time.Hour)},
+	} {
+		if err := db.UpsertReminder(reminder); err != nil {
+			t.Fatalf("UpsertReminder() error = %v", err)
+		}
+	}
+
+	if err := db.DeleteUpcomingReminders(tgID, now, model.RecapReminderTypes); err != nil {
+		t.Fatalf("DeleteUpcomingReminders() error = %v", err)
+	}
+
+	var reminders []model.Reminder
+	if err := db.conn.Where("tg_id = ?", tgID).Find(&reminders).Error; err != nil {
+		t.Fatalf("failed to get reminders: %v", err)
+	}
+	if len(reminders) != 1 || reminders[0].Type != model.ReminderTypeBillDue {
+		t.Errorf("reminders left = %+v, want only the bill reminder", reminders)
+	}
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CreateInviteCode creates a new invite code
+func (db *DB) CreateInviteCode(invite *model.InviteCode) error {
+	return db.conn.Create(invite).Error
+}
+
+// GetActiveInviteCodes retrieves the invite codes that can still be redeemed, the latest first
+func (db *DB) GetActiveInviteCodes(now time.Time) ([]model.InviteCode, error) {
+	var invites []model.InviteCode
+	result := db.conn.Where("revoked_at IS NULL AND uses < max_uses AND (expires_at IS NULL OR expires_at > ?)", now).
+		Order("created_at DESC").
+		Find(&invites)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return invites, nil
+}
+
+// RevokeInviteCode stops an invite code from being redeemed, false if it already was
+func (db *DB) RevokeInviteCode(id int64, now time.Time) (bool, error) {
+	result := db.conn.Model(&model.InviteCode{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RedeemInviteCode uses an invite code to allow a user by their Telegram ID, counting the use, all at once.
+// It's false, with nothing recorded, when the code doesn't exist or can't be redeemed anymore.
+func (db *DB) RedeemInviteCode(code string, tgID int64, username string, now time.Time) (bool, error) {
+	redeemed := false
+	err := db.conn.Transaction(func(tx *gorm.DB) error {
+		// Counting the use locks the code, so it's never redeemed more times than allowed
+		var invite model.InviteCode
+		result := tx.Raw(`
+			UPDATE invite_codes SET uses = uses + 1
+			WHERE code = ? AND revoked_at IS NULL AND uses < max_uses AND (expires_at IS NULL OR expires_at > ?)
+			RETURNING *
+		`, code, now).Scan(&invite)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		// The username may already be allowed, then the user is too
+		err := tx.Exec(`
+			INSERT INTO allowed_users (tg_id, username, added_by, invite_code_id, created_at)
+			VALUES (?, NULLIF(?, ''), ?, ?, CURRENT_TIMESTAMP)
+			ON CONFLICT DO NOTHING
+		`, tgID, username, invite.CreatedBy, invite.ID).Error
+		if err != nil {
+			return err
+		}
+
+		redeemed = true
+		return nil
+	})
+	return redeemed && err == nil, err
+}
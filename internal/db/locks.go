@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+)
+
+// Keys of the Postgres advisory locks, shared by every instance using the database
+const (
+	// SchedulerLockKey is held by the instance that schedules reminders and runs the periodic checks
+	SchedulerLockKey int64 = 0x63617368_0001
+)
+
+// AdvisoryLock is a Postgres session advisory lock, held on its own connection until released
+// or until the connection is lost, e.g. when the instance holding it crashes
+type AdvisoryLock struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// TryAdvisoryLock takes an advisory lock without waiting, false if another session holds it
+func (db *DB) TryAdvisoryLock(ctx context.Context, key int64) (*AdvisoryLock, bool, error) {
+	sqlDB, err := db.conn.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Session locks belong to a connection, which is kept out of the pool while the lock is held
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get a connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked); err != nil {
+		return nil, false, errors.Join(fmt.Errorf("failed to take advisory lock: %w", err), conn.Close())
+	}
+	if !locked {
+		return nil, false, conn.Close()
+	}
+
+	return &AdvisoryLock{conn: conn, key: key}, true, nil
+}
+
+// Held tells if the lock is still held, that is its connection is alive
+func (l *AdvisoryLock) Held(ctx context.Context) bool {
+	return l.conn.PingContext(ctx) == nil
+}
+
+// Release releases the lock and returns its connection to the pool
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	if err != nil {
+		// Closing the session releases the lock, rather than pooling a connection that may still hold it
+		_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+	return errors.Join(err, l.conn.Close())
+}
@@ -0,0 +1,206 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TransactionSort is a field transactions can be sorted by
+type TransactionSort string
+
+const (
+	SortByDate     TransactionSort = "date"
+	SortByAmount   TransactionSort = "amount"
+	SortByCategory TransactionSort = "category"
+)
+
+var ErrInvalidSort = errors.New("invalid sort, expected date, amount or category")
+
+// ParseTransactionSort parses a sort field, defaulting to the date
+func ParseTransactionSort(s string) (TransactionSort, error) {
+	switch TransactionSort(strings.ToLower(s)) {
+	case "", SortByDate:
+		return SortByDate, nil
+	case SortByAmount:
+		return SortByAmount, nil
+	case SortByCategory:
+		return SortByCategory, nil
+	}
+	return "", ErrInvalidSort
+}
+
+// column returns the SQL expression sorted on. Categories are compared as text to sort them alphabetically
+// instead of in the enum order.
+func (s TransactionSort) column() string {
+	switch s {
+	case SortByAmount:
+		return "amount"
+	case SortByCategory:
+		return "category::text"
+	default:
+		return "date"
+	}
+}
+
+// TransactionCursor points to the last transaction of a page: its value of the sort field and its ID
+type TransactionCursor struct {
+	Value string
+	ID    int64
+}
+
+// TransactionQuery composes the filters and the sorting of a listing of a user's transactions.
+// Zero values are ignored, so a query only made of TgID lists everything, newest first.
+type TransactionQuery struct {
+	TgID       int64
+	From       time.Time
+	To         time.Time
+	Type       model.TransactionType
+	Categories []model.TransactionCategory
+	MinAmount  *float64
+	MaxAmount  *float64
+	Search     string
+
+	Sort      TransactionSort
+	Ascending bool
+}
+
+// filter is a gorm scope applying the query conditions
+func (q TransactionQuery) filter(tx *gorm.DB) *gorm.DB {
+	tx = tx.Where("tg_id = ?", q.TgID)
+
+	if !q.From.IsZero() {
+		tx = tx.Where("date >= ?", q.From.Format("2006-01-02"))
+	}
+	if !q.To.IsZero() {
+		tx = tx.Where("date <= ?", q.To.Format("2006-01-02"))
+	}
+	if q.Type != "" {
+		tx = tx.Where("type = ?", q.Type)
+	}
+	if len(q.Categories) > 0 {
+		tx = tx.Where("category IN ?", q.Categories)
+	}
+	if q.MinAmount != nil {
+		tx = tx.Where("amount >= ?", *q.MinAmount)
+	}
+	if q.MaxAmount != nil {
+		tx = tx.Where("amount <= ?", *q.MaxAmount)
+	}
+	if q.Search != "" {
+		tx = tx.Where(`LOWER(description) LIKE LOWER(?) ESCAPE '\'`, "%"+escapeLike(q.Search)+"%")
+	}
+
+	return tx
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes a LIKE pattern matching the text literally
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// order is a gorm scope sorting by the query field, with the ID breaking ties
+func (q TransactionQuery) order(tx *gorm.DB) *gorm.DB {
+	direction := "DESC"
+	if q.Ascending {
+		direction = "ASC"
+	}
+	return tx.Order(fmt.Sprintf("%s %s, id %s", q.Sort.column(), direction, direction))
+}
+
+// after is a gorm scope skipping the transactions up to the cursor, in the query order
+func (q TransactionQuery) after(cursor TransactionCursor) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		op := "<"
+		if q.Ascending {
+			op = ">"
+		}
+
+		switch q.Sort {
+		case SortByAmount:
+			return tx.Where(fmt.Sprintf("(amount, id) %s (?::numeric, ?)", op), cursor.Value, cursor.ID)
+		case SortByCategory:
+			return tx.Where(fmt.Sprintf("(category::text, id) %s (?, ?)", op), cursor.Value, cursor.ID)
+		default:
+			return tx.Where(fmt.Sprintf("(date, id) %s (?::date, ?)", op), cursor.Value, cursor.ID)
+		}
+	}
+}
+
+// CursorFor returns the cursor pointing after the transaction in this query's order
+func (q TransactionQuery) CursorFor(t model.Transaction) TransactionCursor {
+	var value string
+	switch q.Sort {
+	case SortByAmount:
+		value = strconv.FormatFloat(t.Amount, 'f', -1, 64)
+	case SortByCategory:
+		value = string(t.Category)
+	default:
+		value = t.Date.Format("2006-01-02")
+	}
+	return TransactionCursor{Value: value, ID: t.ID}
+}
+
+// FindTransactions retrieves up to limit transactions matching the query, following the cursor if any.
+// It also reports whether there are more transactions after the page.
+func (db *DB) FindTransactions(q TransactionQuery, cursor *TransactionCursor, limit int) ([]model.Transaction, bool, error) {
+	var transactions []model.Transaction
+
+	query := db.conn.Model(&model.Transaction{}).Scopes(q.filter, q.order)
+	if cursor != nil {
+		query = query.Scopes(q.after(*cursor))
+	}
+
+	// Fetch one more to know if there's a next page
+	result := query.Limit(limit + 1).Find(&transactions)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+
+	if len(transactions) > limit {
+		return transactions[:limit], true, nil
+	}
+
+	return transactions, false, nil
+}
+
+// FindTransactionsPage retrieves a page of transactions matching the query by offset, with the total count
+func (db *DB) FindTransactionsPage(q TransactionQuery, offset, limit int) ([]model.Transaction, int64, error) {
+	var transactions []model.Transaction
+	var total int64
+
+	err := db.conn.Model(&model.Transaction{}).Scopes(q.filter).Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := db.conn.Model(&model.Transaction{}).
+		Scopes(q.filter, q.order).
+		Offset(offset).
+		Limit(limit).
+		Find(&transactions)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return transactions, total, nil
+}
+
+// FindAllTransactions retrieves every transaction matching the query
+func (db *DB) FindAllTransactions(q TransactionQuery) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	result := db.conn.Model(&model.Transaction{}).Scopes(q.filter, q.order).Find(&transactions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return transactions, nil
+}
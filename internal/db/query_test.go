@@ -0,0 +1,23 @@
+package db
+
+import "testing"
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "groceries", want: "groceries"},
+		{input: "100%", want: `100\%`},
+		{input: "my_bill", want: `my\_bill`},
+		{input: `C:\temp`, want: `C:\\temp`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := escapeLike(tt.input); got != tt.want {
+				t.Errorf("escapeLike(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -12,12 +12,46 @@ func (db *DB) CreateReminder(reminder *model.Reminder) error {
 	return db.conn.Create(reminder).Error
 }
 
-// GetPendingReminders retrieves all pending reminders that should be processed
-func (db *DB) GetPendingReminders(reminderType model.ReminderType, scheduledBefore time.Time) ([]model.Reminder, error) {
+// ClaimDueReminders takes up to limit reminders of a type to send now, marking them as being sent until
+// the lease expires and counting the attempt. Due reminders are pending ones whose time came, failed ones
+// to retry and those whose sender didn't finish before its lease expired. Those whose sender never finished
+// in all their attempts, e.g. because the reminder crashes it, are given up on instead.
+// Rows locked by another instance claiming at the same time are skipped, so each reminder is claimed once.
+func (db *DB) ClaimDueReminders(reminderType model.ReminderType, now time.Time, lease time.Duration, limit int) ([]model.Reminder, error) {
+	err := db.conn.Model(&model.Reminder{}).
+		Where("type = ? AND status = ? AND attempts >= ? AND (locked_until IS NULL OR locked_until <= ?)",
+			reminderType, model.ReminderStatusProcessing, model.MaxReminderAttempts, now).
+		Updates(map[string]interface{}{
+			"status":        model.ReminderStatusDead,
+			"processed_at":  now,
+			"locked_until":  nil,
+			"error_message": "the sender never finished sending it",
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
 	var reminders []model.Reminder
-	result := db.conn.Where("type = ? AND status = ? AND scheduled_for <= ?",
-		reminderType, model.ReminderStatusPending, scheduledBefore).
-		Find(&reminders)
+	result := db.conn.Raw(`
+		UPDATE reminders
+		SET status = ?, attempts = attempts + 1, locked_until = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM reminders
+			WHERE type = ? AND (
+				(status IN (?, ?) AND COALESCE(next_attempt_at, scheduled_for) <= ?)
+				OR (status = ? AND (locked_until IS NULL OR locked_until <= ?) AND attempts < ?)
+			)
+			ORDER BY scheduled_for
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, model.ReminderStatusProcessing, now.Add(lease),
+		reminderType,
+		model.ReminderStatusPending, model.ReminderStatusFailed, now,
+		model.ReminderStatusProcessing, now, model.MaxReminderAttempts,
+		limit,
+	).Scan(&reminders)
 
 	if result.Error != nil {
 		return nil, result.Error
@@ -25,94 +59,84 @@ func (db *DB) GetPendingReminders(reminderType model.ReminderType, scheduledBefo
 	return reminders, nil
 }
 
-// UpdateReminderStatusTransaction updates a reminder's status within a transaction
-// This ensures consistency and prevents double processing
-func (db *DB) UpdateReminderStatusTransaction(reminderID int64, status model.ReminderStatus, errorMsg *string) error {
-	return db.conn.Transaction(func(tx *gorm.DB) error {
-		// First, check if the reminder is still pending
-		var reminder model.Reminder
-		result := tx.Set("gorm:query_option", "FOR UPDATE").
-			Where("id = ? AND status = ?", reminderID, model.ReminderStatusPending).
-			First(&reminder)
-
-		if result.Error != nil {
-			if result.Error == gorm.ErrRecordNotFound {
-				// Reminder already processed by another instance
-				return nil
-			}
-			return result.Error
-		}
-
-		// Update the reminder
-		updates := map[string]interface{}{
-			"status": status,
-		}
-
-		if status == model.ReminderStatusSent || status == model.ReminderStatusFailed {
-			now := time.Now()
-			updates["processed_at"] = &now
-		}
-
-		if errorMsg != nil {
-			updates["error_message"] = errorMsg
-		}
-
-		return tx.Model(&model.Reminder{}).
-			Where("id = ?", reminderID).
-			Updates(updates).Error
-	})
+// MarkReminderSent records that a reminder was delivered
+func (db *DB) MarkReminderSent(reminderID int64, now time.Time) error {
+	return db.conn.Model(&model.Reminder{}).
+		Where("id = ?", reminderID).
+		Updates(map[string]interface{}{
+			"status":        model.ReminderStatusSent,
+			"processed_at":  now,
+			"locked_until":  nil,
+			"error_message": nil,
+		}).Error
 }
 
-// CreateOrUpdateWeeklyReminder creates or updates a weekly reminder for a user
-func (db *DB) CreateOrUpdateWeeklyReminder(tgID int64, scheduledFor time.Time) error {
-	reminder := model.Reminder{
-		TgID:         tgID,
-		Type:         model.ReminderTypeWeeklyRecap,
-		Status:       model.ReminderStatusPending,
-		ScheduledFor: scheduledFor,
-	}
-
-	// Use ON CONFLICT to update if exists
-	result := db.conn.Exec(`
-		INSERT INTO reminders (tg_id, type, status, scheduled_for, created_at, updated_at)
-		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		ON CONFLICT (tg_id, type, scheduled_for) 
-		DO UPDATE SET 
-			status = EXCLUDED.status,
-			updated_at = CURRENT_TIMESTAMP
-		WHERE reminders.status != ?
-	`, reminder.TgID, reminder.Type, reminder.Status, reminder.ScheduledFor, model.ReminderStatusSent)
+// MarkReminderFailed records a failed attempt to send a reminder, which is retried at the given time
+func (db *DB) MarkReminderFailed(reminderID int64, nextAttemptAt time.Time, errorMsg string) error {
+	return db.conn.Model(&model.Reminder{}).
+		Where("id = ?", reminderID).
+		Updates(map[string]interface{}{
+			"status":          model.ReminderStatusFailed,
+			"next_attempt_at": nextAttemptAt,
+			"locked_until":    nil,
+			"error_message":   errorMsg,
+		}).Error
+}
 
-	return result.Error
+// DelayReminder puts back a reminder that couldn't be sent yet, without counting the attempt
+func (db *DB) DelayReminder(reminderID int64, nextAttemptAt time.Time, errorMsg string) error {
+	return db.conn.Model(&model.Reminder{}).
+		Where("id = ?", reminderID).
+		Updates(map[string]interface{}{
+			"status":          model.ReminderStatusPending,
+			"attempts":        gorm.Expr("GREATEST(attempts - 1, 0)"),
+			"next_attempt_at": nextAttemptAt,
+			"locked_until":    nil,
+			"error_message":   errorMsg,
+		}).Error
 }
 
-// CreateOrUpdateMonthlyReminder creates or updates a monthly reminder for a user
-func (db *DB) CreateOrUpdateMonthlyReminder(tgID int64, scheduledFor time.Time) error {
-	reminder := model.Reminder{
-		TgID:         tgID,
-		Type:         model.ReminderTypeMonthlyRecap,
-		Status:       model.ReminderStatusPending,
-		ScheduledFor: scheduledFor,
-	}
+// MarkReminderDead gives up on a reminder that can't be sent
+func (db *DB) MarkReminderDead(reminderID int64, now time.Time, errorMsg string) error {
+	return db.conn.Model(&model.Reminder{}).
+		Where("id = ?", reminderID).
+		Updates(map[string]interface{}{
+			"status":        model.ReminderStatusDead,
+			"processed_at":  now,
+			"locked_until":  nil,
+			"error_message": errorMsg,
+		}).Error
+}
 
-	// Use ON CONFLICT to update if exists
+// UpsertReminder creates a pending reminder, unless the user already has it for the same time (and bill or broadcast)
+func (db *DB) UpsertReminder(reminder model.Reminder) error {
+	// An existing reminder keeps its delivery state, it may be being sent
 	result := db.conn.Exec(`
-		INSERT INTO reminders (tg_id, type, status, scheduled_for, created_at, updated_at)
-		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
-		ON CONFLICT (tg_id, type, scheduled_for) 
-		DO UPDATE SET 
-			status = EXCLUDED.status,
-			updated_at = CURRENT_TIMESTAMP
-		WHERE reminders.status != ?
-	`, reminder.TgID, reminder.Type, reminder.Status, reminder.ScheduledFor, model.ReminderStatusSent)
+		INSERT INTO reminders (tg_id, type, bill_id, broadcast_id, status, scheduled_for, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT DO NOTHING
+	`, reminder.TgID, reminder.Type, reminder.BillID, reminder.BroadcastID, model.ReminderStatusPending, reminder.ScheduledFor)
 
 	return result.Error
 }
 
-// GetAllActiveUsers
+// DeleteUpcomingReminders deletes the pending reminders of some types of a user scheduled after a time
+func (db *DB) DeleteUpcomingReminders(tgID int64, after time.Time, types []model.ReminderType) error {
+	return db.conn.Where("tg_id = ? AND type IN ? AND status = ? AND scheduled_for > ?", tgID, types, model.ReminderStatusPending, after).
+		Delete(&model.Reminder{}).Error
+}
+
+// DeleteUpcomingBillReminders deletes the pending reminders of a bill
+func (db *DB) DeleteUpcomingBillReminders(billID int64) error {
+	return db.conn.Where("bill_id = ? AND status = ?", billID, model.ReminderStatusPending).
+		Delete(&model.Reminder{}).Error
+}
+
+// GetAllActiveUsers retrieves the users the bot can write to
 func (db *DB) GetAllActiveUsers() ([]model.User, error) {
 	var users []model.User
 	result := db.conn.Distinct("users.*").
+		Where("bot_blocked_at IS NULL").
 		// TODO: Find something else here, for now it's not a problem
 		// Joins("JOIN transactions ON users.tg_id = transactions.tg_id").
 		Find(&users)
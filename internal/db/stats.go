@@ -0,0 +1,85 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"time"
+)
+
+// MonthlyCategoryTotal is the total of a category of transactions in a month
+type MonthlyCategoryTotal struct {
+	Month    time.Time
+	Type     model.TransactionType
+	Category model.TransactionCategory
+	Total    float64
+}
+
+// CategoryTotal is the total and number of transactions of a category in a period
+type CategoryTotal struct {
+	Type     model.TransactionType
+	Category model.TransactionCategory
+	Total    float64
+	Count    int64
+}
+
+// MerchantTotal is the total spent with a merchant, i.e. on expenses with the same description
+type MerchantTotal struct {
+	Name  string
+	Total float64
+	Count int64
+}
+
+// GetMonthlyCategoryTotals sums a user's transactions by month, type and category within a date range
+func (db *DB) GetMonthlyCategoryTotals(tgID int64, startDate, endDate time.Time) ([]MonthlyCategoryTotal, error) {
+	var results []MonthlyCategoryTotal
+
+	query := db.conn.Table("transactions").
+		Select("date_trunc('month', date)::date as month, type, category, SUM(amount) as total").
+		Where("tg_id = ? AND date BETWEEN ? AND ?",
+			tgID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Group("month, type, category").
+		Order("month, total DESC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetCategoryTotals sums a user's transactions by type and category within a date range, largest first
+func (db *DB) GetCategoryTotals(tgID int64, startDate, endDate time.Time) ([]CategoryTotal, error) {
+	var results []CategoryTotal
+
+	query := db.conn.Table("transactions").
+		Select("type, category, SUM(amount) as total, COUNT(*) as count").
+		Where("tg_id = ? AND date BETWEEN ? AND ?",
+			tgID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Group("type, category").
+		Order("total DESC")
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetTopMerchants returns the descriptions a user spent the most on within a date range.
+// Descriptions are grouped ignoring case and surrounding spaces.
+func (db *DB) GetTopMerchants(tgID int64, startDate, endDate time.Time, limit int) ([]MerchantTotal, error) {
+	var results []MerchantTotal
+
+	query := db.conn.Table("transactions").
+		Select("MIN(TRIM(description)) as name, SUM(amount) as total, COUNT(*) as count").
+		Where("tg_id = ? AND date BETWEEN ? AND ? AND type = ? AND TRIM(description) <> ''",
+			tgID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"), model.TypeExpense).
+		Group("LOWER(TRIM(description))").
+		Order("total DESC").
+		Limit(limit)
+
+	if err := query.Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -264,36 +264,39 @@ func (db *DB) GetUserTransactionsPaginated(tgID int64, offset, limit int) ([]mod
 
 // SearchUserTransactions searches transactions by description with optional category filter
 func (db *DB) SearchUserTransactions(tgID int64, searchQuery string, category string, offset, limit int) ([]model.Transaction, int64, error) {
-	var transactions []model.Transaction
-	var total int64
-
-	// Build base query
-	baseQuery := db.conn.Model(&model.Transaction{}).Where("tg_id = ?", tgID)
-
-	// Add search condition (case-insensitive)
-	baseQuery = baseQuery.Where("LOWER(description) LIKE LOWER(?)", "%"+searchQuery+"%")
+	query := TransactionQuery{
+		TgID:   tgID,
+		Search: searchQuery,
+	}
 
 	// Add category filter if not "all"
 	if category != "" && category != "all" {
-		baseQuery = baseQuery.Where("category = ?", category)
-	}
-
-	// Get total count
-	err := baseQuery.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
+		query.Categories = []model.TransactionCategory{model.TransactionCategory(category)}
 	}
 
-	// Get paginated results
-	result := baseQuery.
-		Order("date DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&transactions)
+	return db.FindTransactionsPage(query, offset, limit)
+}
 
+// GetLatestUserTransactionByCategory retrieves the most recent transaction of a user in a category
+func (db *DB) GetLatestUserTransactionByCategory(tgID int64, category model.TransactionCategory) (*model.Transaction, error) {
+	var transaction model.Transaction
+	result := db.conn.Where("tg_id = ? AND category = ?", tgID, category).
+		Order("date DESC, id DESC").
+		First(&transaction)
 	if result.Error != nil {
-		return nil, 0, result.Error
+		return nil, result.Error
 	}
+	return &transaction, nil
+}
 
-	return transactions, total, nil
+// CountUserTransactionsCreatedSince counts the transactions a user recorded after a time, whatever their date
+func (db *DB) CountUserTransactionsCreatedSince(tgID int64, since time.Time) (int64, error) {
+	var count int64
+	result := db.conn.Model(&model.Transaction{}).
+		Where("tg_id = ? AND created_at > ?", tgID, since).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
 }
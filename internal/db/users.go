@@ -1,6 +1,9 @@
 package db
 
-import "cashout/internal/model"
+import (
+	"cashout/internal/model"
+	"time"
+)
 
 // GetUsermodel. retrieves a user by Telegram ID
 func (db *DB) GetUser(tgID int64) (*model.User, error) {
@@ -12,19 +15,60 @@ func (db *DB) GetUser(tgID int64) (*model.User, error) {
 	return &user, nil
 }
 
-// GetUserByUsername retrieves a user by their Telegram username
+// GetUserByUsername retrieves a user by their Telegram username, ignoring case. Usernames can change hands,
+// the user who last had it wins.
 func (db *DB) GetUserByUsername(username string) (*model.User, error) {
 	var user model.User
-	result := db.conn.Where("tg_username = ?", username).First(&user)
+	result := db.conn.Where("tg_username <> '' AND LOWER(tg_username) = LOWER(?)", username).
+		Order("updated_at DESC").
+		First(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &user, nil
 }
 
-// SetUser creates or updates a user
+// SetUser creates or updates a user. The columns the scheduler writes while handlers run, about nudges and
+// whether the bot is blocked, are left alone: they're changed with their own updates.
 func (db *DB) SetUser(user *model.User) error {
 	// Use upsert functionality (create if not exists, update if exists)
-	result := db.conn.Save(user)
+	result := db.conn.Omit("last_nudge_at", "nudges_ignored", "bot_blocked_at").Save(user)
 	return result.Error
 }
+
+// SetUserProfile updates the username and names of a user as Telegram has them now, and records that the bot
+// can write to them, as they just wrote to it
+func (db *DB) SetUserProfile(tgID int64, username, firstname, lastname string) error {
+	return db.conn.Model(&model.User{}).
+		Where("tg_id = ?", tgID).
+		Updates(map[string]interface{}{
+			"tg_username":    username,
+			"tg_firstname":   firstname,
+			"tg_lastname":    lastname,
+			"bot_blocked_at": nil,
+		}).Error
+}
+
+// ResetUserNudges starts the nudges of a user again from one a day
+func (db *DB) ResetUserNudges(tgID int64) error {
+	return db.conn.Model(&model.User{}).
+		Where("tg_id = ?", tgID).
+		Update("nudges_ignored", 0).Error
+}
+
+// SetUserNudge records when the user was last nudged to log and how many nudges in a row they ignored
+func (db *DB) SetUserNudge(tgID int64, nudgedAt time.Time, ignored int) error {
+	return db.conn.Model(&model.User{}).
+		Where("tg_id = ?", tgID).
+		Updates(map[string]interface{}{
+			"last_nudge_at":  nudgedAt,
+			"nudges_ignored": ignored,
+		}).Error
+}
+
+// SetUserBotBlocked records when the bot found it can't write to the user anymore, nil when it can again
+func (db *DB) SetUserBotBlocked(tgID int64, blockedAt *time.Time) error {
+	return db.conn.Model(&model.User{}).
+		Where("tg_id = ?", tgID).
+		Update("bot_blocked_at", blockedAt).Error
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"cashout/internal/model"
+	"testing"
+	"time"
+)
+
+func TestGetUserByUsername(t *testing.T) {
+	db := testDB(t)
+
+	const oldTgID, newTgID, noUsernameTgID = -5001, -5002, -5003
+	testUser(t, db, oldTgID)
+	testUser(t, db, newTgID)
+	testUser(t, db, noUsernameTgID)
+
+	// The username changed hands, users without one may be many
+	for _, u := range []struct {
+		tgID      int64
+		username  string
+		updatedAt time.Time
+	}{
+		{tgID: oldTgID, username: "Test_Renamed_User", updatedAt: time.Now().Add(-time.Hour)},
+		{tgID: newTgID, username: "test_renamed_user", updatedAt: time.Now()},
+		{tgID: noUsernameTgID, username: "", updatedAt: time.Now()},
+	} {
+		err := db.conn.Model(&model.User{}).Where("tg_id = ?", u.tgID).
+			UpdateColumns(map[string]interface{}{"tg_username": u.username, "updated_at": u.updatedAt}).Error
+		if err != nil {
+			t.Fatalf("failed to set the username: %v", err)
+		}
+	}
+
+	user, err := db.GetUserByUsername("TEST_RENAMED_USER")
+	if err != nil {
+		t.Fatalf("GetUserByUsername() error = %v", err)
+	}
+	if user.TgID != newTgID {
+		t.Errorf("GetUserByUsername() = %d, want the latest user %d", user.TgID, newTgID)
+	}
+
+	if _, err := db.GetUserByUsername(""); err == nil {
+		t.Error("GetUserByUsername(\"\") found a user, want none")
+	}
+}
+
+func TestSetUserKeepsSchedulerColumns(t *testing.T) {
+	db := testDB(t)
+
+	const tgID = -5004
+	testUser(t, db, tgID)
+
+	// A handler loaded the user before the scheduler nudged them
+	user, err := db.GetUser(tgID)
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	nudgedAt := time.Now().UTC().Truncate(time.Second)
+	if err := db.SetUserNudge(tgID, nudgedAt, 2); err != nil {
+		t.Fatalf("SetUserNudge() error = %v", err)
+	}
+
+	user.Session.State = model.StateNormal
+	if err := db.SetUser(user); err != nil {
+		t.Fatalf("SetUser() error = %v", err)
+	}
+	if err := db.SetUserProfile(tgID, "test_new_username", "New", ""); err != nil {
+		t.Fatalf("SetUserProfile() error = %v", err)
+	}
+
+	got, err := db.GetUser(tgID)
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if got.NudgesIgnored != 2 || got.LastNudgeAt == nil || !got.LastNudgeAt.Equal(nudgedAt) {
+		t.Errorf("nudge = %v, %d ignored, want %v, 2 ignored", got.LastNudgeAt, got.NudgesIgnored, nudgedAt)
+	}
+	if got.TgUsername != "test_new_username" || got.TgFirstname != "New" {
+		t.Errorf("profile = %q %q, want the refreshed one", got.TgUsername, got.TgFirstname)
+	}
+}
@@ -0,0 +1,61 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("008", "Create anomalies table", createAnomaliesTable, rollbackAnomaliesTable)
+}
+
+func createAnomaliesTable(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- Create anomaly enums
+		DROP TYPE IF EXISTS anomaly_kind CASCADE;
+		CREATE TYPE anomaly_kind AS ENUM (
+			'large_amount',
+			'duplicate',
+			'category_spike'
+		);
+
+		DROP TYPE IF EXISTS anomaly_status CASCADE;
+		CREATE TYPE anomaly_status AS ENUM (
+			'notified',
+			'dismissed'
+		);
+
+		-- Create anomalies table
+		CREATE TABLE IF NOT EXISTS anomalies (
+			id SERIAL PRIMARY KEY,
+			tg_id BIGINT NOT NULL,
+			key VARCHAR(128) NOT NULL,
+			kind anomaly_kind NOT NULL,
+			status anomaly_status NOT NULL DEFAULT 'notified',
+			transaction_id INTEGER,
+			category transaction_category NOT NULL,
+			amount DECIMAL(15,2) NOT NULL,
+			typical DECIMAL(15,2) NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			CONSTRAINT uq_anomalies_tg_id_key UNIQUE (tg_id, key)
+		);
+
+		-- Create indexes for anomalies
+		CREATE INDEX IF NOT EXISTS idx_anomalies_tg_id ON anomalies (tg_id);
+		CREATE INDEX IF NOT EXISTS idx_anomalies_transaction_id ON anomalies (transaction_id);
+
+		-- Add foreign key constraints
+		ALTER TABLE anomalies ADD CONSTRAINT fk_anomalies_tg_id FOREIGN KEY (tg_id) REFERENCES users (tg_id) ON DELETE CASCADE;
+		ALTER TABLE anomalies ADD CONSTRAINT fk_anomalies_transaction_id FOREIGN KEY (transaction_id) REFERENCES transactions (id) ON DELETE CASCADE;
+	`).Error
+}
+
+func rollbackAnomaliesTable(tx *gorm.DB) error {
+	return tx.Exec(`
+		DROP TABLE IF EXISTS anomalies;
+		DROP TYPE IF EXISTS anomaly_status;
+		DROP TYPE IF EXISTS anomaly_kind;
+	`).Error
+}
@@ -0,0 +1,49 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("009", "Create api tokens table", createAPITokensTable, rollbackAPITokensTable)
+}
+
+func createAPITokensTable(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- Create api token scope enum
+		DROP TYPE IF EXISTS api_token_scope CASCADE;
+		CREATE TYPE api_token_scope AS ENUM (
+			'read',
+			'read_write'
+		);
+
+		-- Create api_tokens table
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			id SERIAL PRIMARY KEY,
+			tg_id BIGINT NOT NULL,
+			name VARCHAR(64) NOT NULL,
+			hint VARCHAR(16) NOT NULL,
+			token_hash CHAR(64) NOT NULL UNIQUE,
+			scope api_token_scope NOT NULL DEFAULT 'read',
+			last_used_at TIMESTAMP WITH TIME ZONE,
+			revoked_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		-- Create indexes for api_tokens
+		CREATE INDEX IF NOT EXISTS idx_api_tokens_tg_id ON api_tokens (tg_id);
+
+		-- Add foreign key constraint
+		ALTER TABLE api_tokens ADD CONSTRAINT fk_api_tokens_tg_id FOREIGN KEY (tg_id) REFERENCES users (tg_id) ON DELETE CASCADE;
+	`).Error
+}
+
+func rollbackAPITokensTable(tx *gorm.DB) error {
+	return tx.Exec(`
+		DROP TABLE IF EXISTS api_tokens;
+		DROP TYPE IF EXISTS api_token_scope;
+	`).Error
+}
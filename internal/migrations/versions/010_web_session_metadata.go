@@ -0,0 +1,37 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("010", "Add web session metadata", addWebSessionMetadata, rollbackWebSessionMetadata)
+}
+
+func addWebSessionMetadata(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- The public ID identifies a session in lists without revealing the secret session ID
+		ALTER TABLE web_sessions ADD COLUMN IF NOT EXISTS public_id BIGSERIAL UNIQUE;
+		ALTER TABLE web_sessions ADD COLUMN IF NOT EXISTS user_agent VARCHAR(512) NOT NULL DEFAULT '';
+		ALTER TABLE web_sessions ADD COLUMN IF NOT EXISTS ip_address VARCHAR(64) NOT NULL DEFAULT '';
+		ALTER TABLE web_sessions ADD COLUMN IF NOT EXISTS remember BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE web_sessions ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+		ALTER TABLE web_sessions ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP;
+
+		-- Existing sessions were last seen and got their ID when created
+		UPDATE web_sessions SET last_seen_at = created_at, rotated_at = created_at;
+	`).Error
+}
+
+func rollbackWebSessionMetadata(tx *gorm.DB) error {
+	return tx.Exec(`
+		ALTER TABLE web_sessions DROP COLUMN IF EXISTS rotated_at;
+		ALTER TABLE web_sessions DROP COLUMN IF EXISTS last_seen_at;
+		ALTER TABLE web_sessions DROP COLUMN IF EXISTS remember;
+		ALTER TABLE web_sessions DROP COLUMN IF EXISTS ip_address;
+		ALTER TABLE web_sessions DROP COLUMN IF EXISTS user_agent;
+		ALTER TABLE web_sessions DROP COLUMN IF EXISTS public_id;
+	`).Error
+}
@@ -0,0 +1,24 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("011", "Add user time zone", addUserTimezone, rollbackUserTimezone)
+}
+
+func addUserTimezone(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- IANA name of the user's time zone, for "today", week and month boundaries and reminders
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+	`).Error
+}
+
+func rollbackUserTimezone(tx *gorm.DB) error {
+	return tx.Exec(`
+		ALTER TABLE users DROP COLUMN IF EXISTS timezone;
+	`).Error
+}
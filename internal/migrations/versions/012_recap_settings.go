@@ -0,0 +1,33 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("012", "Add recap settings", addRecapSettings, rollbackRecapSettings)
+}
+
+func addRecapSettings(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- Which recaps the user receives, and when in their time zone
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS weekly_recap BOOLEAN NOT NULL DEFAULT TRUE;
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS monthly_recap BOOLEAN NOT NULL DEFAULT TRUE;
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS yearly_recap BOOLEAN NOT NULL DEFAULT TRUE;
+		-- Day of the week of the weekly recap, 0 is Sunday
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS recap_weekday SMALLINT NOT NULL DEFAULT 1 CHECK (recap_weekday BETWEEN 0 AND 6);
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS recap_hour SMALLINT NOT NULL DEFAULT 6 CHECK (recap_hour BETWEEN 0 AND 23);
+	`).Error
+}
+
+func rollbackRecapSettings(tx *gorm.DB) error {
+	return tx.Exec(`
+		ALTER TABLE users DROP COLUMN IF EXISTS recap_hour;
+		ALTER TABLE users DROP COLUMN IF EXISTS recap_weekday;
+		ALTER TABLE users DROP COLUMN IF EXISTS yearly_recap;
+		ALTER TABLE users DROP COLUMN IF EXISTS monthly_recap;
+		ALTER TABLE users DROP COLUMN IF EXISTS weekly_recap;
+	`).Error
+}
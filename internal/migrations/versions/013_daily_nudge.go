@@ -0,0 +1,33 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("013", "Add daily logging nudge", addDailyNudge, rollbackDailyNudge)
+}
+
+func addDailyNudge(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- Evening reminder to log when nothing was recorded that day, off by default
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS daily_nudge BOOLEAN NOT NULL DEFAULT FALSE;
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS nudge_hour SMALLINT NOT NULL DEFAULT 21 CHECK (nudge_hour BETWEEN 0 AND 23);
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS nudge_skip_weekends BOOLEAN NOT NULL DEFAULT FALSE;
+		-- Nudges in a row the user didn't log anything after, to send them less often
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS nudges_ignored SMALLINT NOT NULL DEFAULT 0;
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS last_nudge_at TIMESTAMP WITH TIME ZONE;
+	`).Error
+}
+
+func rollbackDailyNudge(tx *gorm.DB) error {
+	return tx.Exec(`
+		ALTER TABLE users DROP COLUMN IF EXISTS last_nudge_at;
+		ALTER TABLE users DROP COLUMN IF EXISTS nudges_ignored;
+		ALTER TABLE users DROP COLUMN IF EXISTS nudge_skip_weekends;
+		ALTER TABLE users DROP COLUMN IF EXISTS nudge_hour;
+		ALTER TABLE users DROP COLUMN IF EXISTS daily_nudge;
+	`).Error
+}
@@ -0,0 +1,54 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("014", "Add reminder retries", addReminderRetries, rollbackReminderRetries)
+}
+
+func addReminderRetries(tx *gorm.DB) error {
+	db, err := tx.DB()
+	if err != nil {
+		return err
+	}
+
+	// Reminders given up on after too many attempts, or because the user blocked the bot
+	_, err = db.Exec(`
+		ALTER TYPE reminder_status ADD VALUE IF NOT EXISTS 'dead';
+	`)
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		-- Delivery attempts, when a failed reminder is retried and until when a sender holds it
+		ALTER TABLE reminders ADD COLUMN IF NOT EXISTS attempts INTEGER NOT NULL DEFAULT 0;
+		ALTER TABLE reminders ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE;
+		ALTER TABLE reminders ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
+
+		-- Failed reminders used to be final, and those left processing can't be told from sent ones: don't retry them
+		UPDATE reminders SET status = 'dead' WHERE status IN ('failed', 'processing');
+
+		CREATE INDEX IF NOT EXISTS idx_reminders_next_attempt_at ON reminders (next_attempt_at);
+
+		-- Users who blocked the bot don't get reminders until they write to it again
+		ALTER TABLE users ADD COLUMN IF NOT EXISTS bot_blocked_at TIMESTAMP WITH TIME ZONE;
+	`).Error
+}
+
+func rollbackReminderRetries(tx *gorm.DB) error {
+	// Enum values can't be dropped, 'dead' stays unused
+	return tx.Exec(`
+		UPDATE reminders SET status = 'failed' WHERE status = 'dead';
+
+		ALTER TABLE users DROP COLUMN IF EXISTS bot_blocked_at;
+		DROP INDEX IF EXISTS idx_reminders_next_attempt_at;
+		ALTER TABLE reminders DROP COLUMN IF EXISTS locked_until;
+		ALTER TABLE reminders DROP COLUMN IF EXISTS next_attempt_at;
+		ALTER TABLE reminders DROP COLUMN IF EXISTS attempts;
+	`).Error
+}
@@ -0,0 +1,74 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("015", "Add bill reminders", addBills, rollbackBills)
+}
+
+func addBills(tx *gorm.DB) error {
+	db, err := tx.DB()
+	if err != nil {
+		return err
+	}
+
+	// Reminders of a bill about to be due
+	_, err = db.Exec(`
+		ALTER TYPE reminder_type ADD VALUE IF NOT EXISTS 'bill_due';
+	`)
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		DROP TYPE IF EXISTS bill_frequency;
+		CREATE TYPE bill_frequency AS ENUM (
+			'monthly',
+			'yearly'
+		);
+
+		CREATE TABLE IF NOT EXISTS bills (
+			id SERIAL PRIMARY KEY,
+			tg_id BIGINT NOT NULL REFERENCES users (tg_id) ON DELETE CASCADE,
+			name VARCHAR(64) NOT NULL,
+			-- Zero when it varies, the user is asked for it when paying
+			amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+			category transaction_category NOT NULL DEFAULT 'Bills',
+			frequency bill_frequency NOT NULL,
+			due_day SMALLINT NOT NULL CHECK (due_day BETWEEN 1 AND 31),
+			-- Only for yearly bills
+			due_month SMALLINT NOT NULL DEFAULT 0 CHECK (due_month BETWEEN 0 AND 12),
+			remind_days_before SMALLINT NOT NULL DEFAULT 3 CHECK (remind_days_before >= 0),
+			next_due_date DATE NOT NULL,
+			remind_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_bills_tg_id ON bills (tg_id);
+
+		-- Bill reminders are per bill, a user can have several due at the same time
+		ALTER TABLE reminders ADD COLUMN IF NOT EXISTS bill_id INTEGER REFERENCES bills (id) ON DELETE CASCADE;
+		ALTER TABLE reminders DROP CONSTRAINT IF EXISTS unique_user_type_schedule;
+		CREATE UNIQUE INDEX IF NOT EXISTS unique_user_type_schedule_bill
+			ON reminders (tg_id, type, scheduled_for, COALESCE(bill_id, 0));
+	`).Error
+}
+
+func rollbackBills(tx *gorm.DB) error {
+	// Enum values can't be dropped, 'bill_due' stays unused
+	return tx.Exec(`
+		DELETE FROM reminders WHERE type = 'bill_due';
+
+		DROP INDEX IF EXISTS unique_user_type_schedule_bill;
+		ALTER TABLE reminders DROP COLUMN IF EXISTS bill_id;
+		ALTER TABLE reminders ADD CONSTRAINT unique_user_type_schedule UNIQUE (tg_id, type, scheduled_for);
+
+		DROP TABLE IF EXISTS bills;
+		DROP TYPE IF EXISTS bill_frequency;
+	`).Error
+}
@@ -0,0 +1,64 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("016", "Add allowed users and broadcasts", addAdmin, rollbackAdmin)
+}
+
+func addAdmin(tx *gorm.DB) error {
+	db, err := tx.DB()
+	if err != nil {
+		return err
+	}
+
+	// Announcements sent by the admins to all users, queued like the other reminders
+	_, err = db.Exec(`
+		ALTER TYPE reminder_type ADD VALUE IF NOT EXISTS 'broadcast';
+	`)
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		-- Users allowed by the admins at runtime, on top of ALLOWED_USERS
+		CREATE TABLE IF NOT EXISTS allowed_users (
+			id SERIAL PRIMARY KEY,
+			username VARCHAR(64) NOT NULL UNIQUE,
+			added_by BIGINT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		CREATE TABLE IF NOT EXISTS broadcasts (
+			id SERIAL PRIMARY KEY,
+			message TEXT NOT NULL,
+			created_by BIGINT NOT NULL,
+			recipients INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		ALTER TABLE reminders ADD COLUMN IF NOT EXISTS broadcast_id INTEGER REFERENCES broadcasts (id) ON DELETE CASCADE;
+		DROP INDEX IF EXISTS unique_user_type_schedule_bill;
+		CREATE UNIQUE INDEX IF NOT EXISTS unique_reminder_schedule
+			ON reminders (tg_id, type, scheduled_for, COALESCE(bill_id, 0), COALESCE(broadcast_id, 0));
+	`).Error
+}
+
+func rollbackAdmin(tx *gorm.DB) error {
+	// Enum values can't be dropped, 'broadcast' stays unused
+	return tx.Exec(`
+		DELETE FROM reminders WHERE type = 'broadcast';
+
+		DROP INDEX IF EXISTS unique_reminder_schedule;
+		CREATE UNIQUE INDEX IF NOT EXISTS unique_user_type_schedule_bill
+			ON reminders (tg_id, type, scheduled_for, COALESCE(bill_id, 0));
+		ALTER TABLE reminders DROP COLUMN IF EXISTS broadcast_id;
+
+		DROP TABLE IF EXISTS broadcasts;
+		DROP TABLE IF EXISTS allowed_users;
+	`).Error
+}
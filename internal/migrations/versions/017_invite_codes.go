@@ -0,0 +1,46 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("017", "Add invite codes", addInviteCodes, rollbackInviteCodes)
+}
+
+func addInviteCodes(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- Codes the admins share to let users in, usable a limited number of times until they expire
+		CREATE TABLE IF NOT EXISTS invite_codes (
+			id SERIAL PRIMARY KEY,
+			code VARCHAR(32) NOT NULL UNIQUE,
+			max_uses INTEGER NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+			uses INTEGER NOT NULL DEFAULT 0,
+			created_by BIGINT NOT NULL,
+			expires_at TIMESTAMP WITH TIME ZONE,
+			revoked_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+
+		-- Users who redeemed a code are allowed by their Telegram ID, they may have no username
+		ALTER TABLE allowed_users ADD COLUMN IF NOT EXISTS tg_id BIGINT UNIQUE;
+		ALTER TABLE allowed_users ALTER COLUMN username DROP NOT NULL;
+		ALTER TABLE allowed_users ADD COLUMN IF NOT EXISTS invite_code_id INTEGER REFERENCES invite_codes (id) ON DELETE SET NULL;
+		ALTER TABLE allowed_users ADD CONSTRAINT allowed_users_tg_id_or_username CHECK (tg_id IS NOT NULL OR username IS NOT NULL);
+	`).Error
+}
+
+func rollbackInviteCodes(tx *gorm.DB) error {
+	return tx.Exec(`
+		DELETE FROM allowed_users WHERE username IS NULL;
+
+		ALTER TABLE allowed_users DROP CONSTRAINT IF EXISTS allowed_users_tg_id_or_username;
+		ALTER TABLE allowed_users DROP COLUMN IF EXISTS invite_code_id;
+		ALTER TABLE allowed_users ALTER COLUMN username SET NOT NULL;
+		ALTER TABLE allowed_users DROP COLUMN IF EXISTS tg_id;
+
+		DROP TABLE IF EXISTS invite_codes;
+	`).Error
+}
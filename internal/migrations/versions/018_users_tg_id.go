@@ -0,0 +1,52 @@
+package versions
+
+import (
+	"cashout/internal/migrations"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	migrations.RegisterMigrationWithRollback("018", "Identify users by Telegram ID", identifyUsersByTgID, rollbackIdentifyUsersByTgID)
+}
+
+func identifyUsersByTgID(tx *gorm.DB) error {
+	return tx.Exec(`
+		-- Users without a username have an empty one
+		UPDATE users SET tg_username = '' WHERE tg_username IS NULL;
+		UPDATE users SET tg_username = TRIM(LEADING '@' FROM TRIM(tg_username));
+
+		-- Usernames can change and be taken by someone else, only the latest user to have one keeps it
+		UPDATE users u SET tg_username = ''
+		FROM users o
+		WHERE u.tg_username <> ''
+			AND LOWER(u.tg_username) = LOWER(o.tg_username)
+			AND u.tg_id <> o.tg_id
+			AND (u.updated_at < o.updated_at OR (u.updated_at = o.updated_at AND u.tg_id < o.tg_id));
+
+		ALTER TABLE users DROP CONSTRAINT IF EXISTS users_tg_username_key;
+		CREATE INDEX IF NOT EXISTS idx_users_tg_username ON users (LOWER(tg_username)) WHERE tg_username <> '';
+
+		-- Usernames allowed at runtime of users who already wrote to the bot stay allowed if they change it
+		UPDATE allowed_users a SET tg_id = u.tg_id
+		FROM users u
+		WHERE a.tg_id IS NULL
+			AND a.username IS NOT NULL
+			AND u.tg_username <> ''
+			AND LOWER(u.tg_username) = a.username
+			AND NOT EXISTS (SELECT 1 FROM allowed_users o WHERE o.tg_id = u.tg_id);
+	`).Error
+}
+
+func rollbackIdentifyUsersByTgID(tx *gorm.DB) error {
+	return tx.Exec(`
+		DROP INDEX IF EXISTS idx_users_tg_username;
+
+		UPDATE users SET tg_username = NULL WHERE tg_username = '';
+		UPDATE users u SET tg_username = NULL
+		FROM users o
+		WHERE u.tg_username = o.tg_username AND u.tg_id < o.tg_id;
+
+		ALTER TABLE users ADD CONSTRAINT users_tg_username_key UNIQUE (tg_username);
+	`).Error
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AllowedUser represents the allowed_users table structure: a user the admins allowed at runtime,
+// by username or, when they redeemed an invite code, by Telegram ID
+type AllowedUser struct {
+	ID   int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	TgID *int64 `gorm:"column:tg_id;unique"`
+	// Telegram username, lowercase and without the @
+	Username *string `gorm:"column:username;unique"`
+	AddedBy  int64   `gorm:"column:added_by;not null"`
+	// Invite code the user redeemed, if any
+	InviteCodeID *int64    `gorm:"column:invite_code_id"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// Label describes the allowed user, by username when known
+func (a AllowedUser) Label() string {
+	if a.Username != nil && *a.Username != "" {
+		return "@" + *a.Username
+	}
+	if a.TgID != nil {
+		return fmt.Sprintf("ID %d", *a.TgID)
+	}
+	return fmt.Sprintf("#%d", a.ID)
+}
+
+// TableName overrides the table name
+func (AllowedUser) TableName() string {
+	return "allowed_users"
+}
+
+// NormalizeUsername returns a Telegram username the way it's stored in the allowlist, as they're case insensitive
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
+
+// Broadcast represents the broadcasts table structure: an announcement sent by an admin to all users
+type Broadcast struct {
+	ID        int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	Message   string `gorm:"column:message;not null;type:text"`
+	CreatedBy int64  `gorm:"column:created_by;not null"`
+	// Users it was queued for
+	Recipients int       `gorm:"column:recipients;not null;default:0"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (Broadcast) TableName() string {
+	return "broadcasts"
+}
+
+// DefaultInviteValidity is how long an invite code can be redeemed
+const DefaultInviteValidity = 7 * 24 * time.Hour
+
+// InviteCode represents the invite_codes table structure: a code the admins share to let users in
+type InviteCode struct {
+	ID        int64      `gorm:"column:id;primaryKey;autoIncrement"`
+	Code      string     `gorm:"column:code;not null;unique"`
+	MaxUses   int        `gorm:"column:max_uses;not null;default:1"`
+	Uses      int        `gorm:"column:uses;not null;default:0"`
+	CreatedBy int64      `gorm:"column:created_by;not null"`
+	ExpiresAt *time.Time `gorm:"column:expires_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime"`
+}
+
+// TableName overrides the table name
+func (InviteCode) TableName() string {
+	return "invite_codes"
+}
+
+// Usable reports whether the code can still be redeemed
+func (i InviteCode) Usable(now time.Time) bool {
+	if i.RevokedAt != nil || i.Uses >= i.MaxUses {
+		return false
+	}
+	return i.ExpiresAt == nil || now.Before(*i.ExpiresAt)
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "johndoe", want: "johndoe"},
+		{input: "@JohnDoe", want: "johndoe"},
+		{input: "  @john_doe ", want: "john_doe"},
+		{input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := NormalizeUsername(tt.input); got != tt.want {
+				t.Errorf("NormalizeUsername(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInviteCodeUsable(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name   string
+		invite InviteCode
+		want   bool
+	}{
+		{name: "unused", invite: InviteCode{MaxUses: 1, ExpiresAt: &later}, want: true},
+		{name: "without expiry", invite: InviteCode{MaxUses: 3, Uses: 2}, want: true},
+		{name: "used up", invite: InviteCode{MaxUses: 3, Uses: 3, ExpiresAt: &later}, want: false},
+		{name: "expired", invite: InviteCode{MaxUses: 1, ExpiresAt: &earlier}, want: false},
+		{name: "expiring now", invite: InviteCode{MaxUses: 1, ExpiresAt: &now}, want: false},
+		{name: "revoked", invite: InviteCode{MaxUses: 1, RevokedAt: &earlier}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.invite.Usable(now); got != tt.want {
+				t.Errorf("Usable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
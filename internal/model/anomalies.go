@@ -0,0 +1,92 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// AnomalyKind represents the kind of unusual spending detected
+type AnomalyKind string
+
+// Anomaly kinds
+const (
+	AnomalyLargeAmount   AnomalyKind = "large_amount"
+	AnomalyDuplicate     AnomalyKind = "duplicate"
+	AnomalyCategorySpike AnomalyKind = "category_spike"
+)
+
+// Value implements the driver.Valuer interface for AnomalyKind
+func (k AnomalyKind) Value() (driver.Value, error) {
+	return string(k), nil
+}
+
+// Scan implements the sql.Scanner interface for AnomalyKind
+func (k *AnomalyKind) Scan(value interface{}) error {
+	if value == nil {
+		return errors.New("anomaly kind cannot be null")
+	}
+
+	strVal, ok := value.(string)
+	if !ok {
+		return errors.New("invalid anomaly kind")
+	}
+
+	*k = AnomalyKind(strVal)
+	return nil
+}
+
+// AnomalyStatus represents the status of an anomaly notice
+type AnomalyStatus string
+
+// Anomaly statuses
+const (
+	AnomalyStatusNotified  AnomalyStatus = "notified"
+	AnomalyStatusDismissed AnomalyStatus = "dismissed"
+)
+
+// Value implements the driver.Valuer interface for AnomalyStatus
+func (s AnomalyStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for AnomalyStatus
+func (s *AnomalyStatus) Scan(value interface{}) error {
+	if value == nil {
+		return errors.New("anomaly status cannot be null")
+	}
+
+	strVal, ok := value.(string)
+	if !ok {
+		return errors.New("invalid anomaly status")
+	}
+
+	*s = AnomalyStatus(strVal)
+	return nil
+}
+
+// Anomaly represents the anomalies table structure.
+// Key identifies the anomaly so that the same one is notified only once.
+type Anomaly struct {
+	ID            int64               `gorm:"column:id;primaryKey;autoIncrement"`
+	TgID          int64               `gorm:"column:tg_id;not null;index"`
+	Key           string              `gorm:"column:key;not null"`
+	Kind          AnomalyKind         `gorm:"column:kind;not null;type:anomaly_kind"`
+	Status        AnomalyStatus       `gorm:"column:status;not null;type:anomaly_status;default:'notified'"`
+	TransactionID *int64              `gorm:"column:transaction_id"`
+	Category      TransactionCategory `gorm:"column:category;not null;type:transaction_category"`
+	// Amount is the amount of the transaction, or the category total for a spike
+	Amount float64 `gorm:"column:amount;not null;type:decimal(15,2)"`
+	// Typical is the usual amount it's compared against
+	Typical   float64   `gorm:"column:typical;not null;type:decimal(15,2)"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+
+	// Transaction is the flagged transaction, not stored
+	Transaction *Transaction `gorm:"-"`
+}
+
+// TableName overrides the table name
+func (Anomaly) TableName() string {
+	return "anomalies"
+}
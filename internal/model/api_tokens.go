@@ -0,0 +1,112 @@
+package model
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// APITokenPrefix marks personal access tokens so they're easy to recognize (and to scan for in leaked code)
+const APITokenPrefix = "cash_"
+
+// APITokenScope represents what an API token is allowed to do
+type APITokenScope string
+
+// API token scopes
+const (
+	APITokenScopeRead      APITokenScope = "read"
+	APITokenScopeReadWrite APITokenScope = "read_write"
+)
+
+var ErrInvalidAPITokenScope = errors.New("invalid token scope, expected read or read_write")
+
+// ParseAPITokenScope parses a scope, accepting a few short forms used in the bot
+func ParseAPITokenScope(s string) (APITokenScope, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "read", "r", "ro", "readonly", "read-only":
+		return APITokenScopeRead, nil
+	case "read_write", "write", "w", "rw", "readwrite", "read-write":
+		return APITokenScopeReadWrite, nil
+	}
+	return "", ErrInvalidAPITokenScope
+}
+
+// Allows reports whether the scope permits a request with the given HTTP method
+func (s APITokenScope) Allows(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return s == APITokenScopeRead || s == APITokenScopeReadWrite
+	}
+	return s == APITokenScopeReadWrite
+}
+
+// Label returns a human readable name of the scope
+func (s APITokenScope) Label() string {
+	if s == APITokenScopeReadWrite {
+		return "read-write"
+	}
+	return "read-only"
+}
+
+// Value implements the driver.Valuer interface for APITokenScope
+func (s APITokenScope) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface for APITokenScope
+func (s *APITokenScope) Scan(value interface{}) error {
+	if value == nil {
+		return errors.New("api token scope cannot be null")
+	}
+
+	strVal, ok := value.(string)
+	if !ok {
+		return errors.New("invalid api token scope")
+	}
+
+	*s = APITokenScope(strVal)
+	return nil
+}
+
+// APIToken represents the api_tokens table structure.
+// Only the SHA-256 hash of the token is stored, the token itself is shown once on creation.
+type APIToken struct {
+	ID         int64         `gorm:"column:id;primaryKey;autoIncrement"`
+	TgID       int64         `gorm:"column:tg_id;not null;index"`
+	Name       string        `gorm:"column:name;not null"`
+	Hint       string        `gorm:"column:hint;not null"`
+	TokenHash  string        `gorm:"column:token_hash;not null;unique"`
+	Scope      APITokenScope `gorm:"column:scope;not null;type:api_token_scope;default:'read'"`
+	LastUsedAt *time.Time    `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time    `gorm:"column:revoked_at"`
+	CreatedAt  time.Time     `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time     `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (APIToken) TableName() string {
+	return "api_tokens"
+}
+
+// IsValid checks if the token hasn't been revoked
+func (t *APIToken) IsValid() bool {
+	return t.RevokedAt == nil
+}
+
+// HashAPIToken returns the hex encoded SHA-256 hash under which a token is stored
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// APITokenHint returns the part of a token that is safe to display to recognize it
+func APITokenHint(token string) string {
+	if len(token) <= len(APITokenPrefix)+4 {
+		return token
+	}
+	return token[:len(APITokenPrefix)+4] + "…"
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseAPITokenScope(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    APITokenScope
+		wantErr bool
+	}{
+		{input: "", want: APITokenScopeRead},
+		{input: "read", want: APITokenScopeRead},
+		{input: "RO", want: APITokenScopeRead},
+		{input: "rw", want: APITokenScopeReadWrite},
+		{input: "read_write", want: APITokenScopeReadWrite},
+		{input: " Write ", want: APITokenScopeReadWrite},
+		{input: "admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAPITokenScope(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAPITokenScope(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAPITokenScope(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPITokenScopeAllows(t *testing.T) {
+	tests := []struct {
+		scope  APITokenScope
+		method string
+		want   bool
+	}{
+		{scope: APITokenScopeRead, method: http.MethodGet, want: true},
+		{scope: APITokenScopeRead, method: http.MethodPost, want: false},
+		{scope: APITokenScopeRead, method: http.MethodDelete, want: false},
+		{scope: APITokenScopeReadWrite, method: http.MethodGet, want: true},
+		{scope: APITokenScopeReadWrite, method: http.MethodPut, want: true},
+		{scope: APITokenScope("unknown"), method: http.MethodGet, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scope)+" "+tt.method, func(t *testing.T) {
+			if got := tt.scope.Allows(tt.method); got != tt.want {
+				t.Errorf("%s.Allows(%s) = %v, want %v", tt.scope, tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashAPIToken(t *testing.T) {
+	hash := HashAPIToken("cash_0123456789abcdef")
+	if len(hash) != 64 {
+		t.Errorf("HashAPIToken() length = %d, want 64", len(hash))
+	}
+	if hash != HashAPIToken("cash_0123456789abcdef") {
+		t.Error("HashAPIToken() is not deterministic")
+	}
+	if hash == HashAPIToken("cash_0123456789abcdeg") {
+		t.Error("HashAPIToken() collides on different tokens")
+	}
+
+	if got := APITokenHint("cash_0123456789abcdef"); got != "cash_0123…" {
+		t.Errorf("APITokenHint() = %q, want %q", got, "cash_0123…")
+	}
+}
@@ -59,13 +59,28 @@ func (a *AuthToken) IsValid() bool {
 	return a.Status == AuthStatusPending && time.Now().UTC().Before(a.ExpiresAt)
 }
 
+const (
+	// SessionDuration is how long a web session lasts
+	SessionDuration = 24 * time.Hour
+	// RememberSessionDuration is how long a "remember me" web session lasts
+	RememberSessionDuration = 30 * 24 * time.Hour
+	// SessionRotationInterval is how often the ID of a web session is renewed
+	SessionRotationInterval = 12 * time.Hour
+)
+
 // WebSession represents a web session
 type WebSession struct {
-	ID        string    `gorm:"column:id;primaryKey"`
-	TgID      int64     `gorm:"column:tg_id;not null;index"`
-	ExpiresAt time.Time `gorm:"column:expires_at;not null;index"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID         string    `gorm:"column:id;primaryKey"`
+	PublicID   int64     `gorm:"column:public_id;->"`
+	TgID       int64     `gorm:"column:tg_id;not null;index"`
+	UserAgent  string    `gorm:"column:user_agent;not null;default:''"`
+	IPAddress  string    `gorm:"column:ip_address;not null;default:''"`
+	Remember   bool      `gorm:"column:remember;not null;default:false"`
+	LastSeenAt time.Time `gorm:"column:last_seen_at"`
+	RotatedAt  time.Time `gorm:"column:rotated_at"`
+	ExpiresAt  time.Time `gorm:"column:expires_at;not null;index"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Association to User (optional)
 	User *User `gorm:"foreignKey:TgID;references:TgID"`
@@ -80,3 +95,8 @@ func (WebSession) TableName() string {
 func (s *WebSession) IsValid() bool {
 	return time.Now().UTC().Before(s.ExpiresAt)
 }
+
+// NeedsRotation checks if the session ID is old enough to be renewed
+func (s *WebSession) NeedsRotation(now time.Time) bool {
+	return now.Sub(s.RotatedAt) >= SessionRotationInterval
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebSessionNeedsRotation(t *testing.T) {
+	rotatedAt := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "just rotated", now: rotatedAt, want: false},
+		{name: "before interval", now: rotatedAt.Add(SessionRotationInterval - time.Second), want: false},
+		{name: "at interval", now: rotatedAt.Add(SessionRotationInterval), want: true},
+		{name: "days later", now: rotatedAt.Add(72 * time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := WebSession{RotatedAt: rotatedAt}
+			if got := session.NeedsRotation(tt.now); got != tt.want {
+				t.Errorf("NeedsRotation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// BillFrequency represents how often a bill is due
+type BillFrequency string
+
+// Bill frequencies
+const (
+	BillFrequencyMonthly BillFrequency = "monthly"
+	BillFrequencyYearly  BillFrequency = "yearly"
+)
+
+const (
+	// DefaultBillRemindDaysBefore is how many days before the due date bills are reminded
+	DefaultBillRemindDaysBefore = 3
+	// BillReminderHour is the hour bills are reminded at, in the user's time zone
+	BillReminderHour = 9
+	// BillOverdueDays is how long an unpaid bill stays overdue before it moves on to its next due date,
+	// e.g. when it's paid automatically and never marked paid
+	BillOverdueDays = 7
+)
+
+// Value implements the driver.Valuer interface for BillFrequency
+func (f BillFrequency) Value() (driver.Value, error) {
+	return string(f), nil
+}
+
+// Scan implements the sql.Scanner interface for BillFrequency
+func (f *BillFrequency) Scan(value interface{}) error {
+	if value == nil {
+		return errors.New("bill frequency cannot be null")
+	}
+
+	strVal, ok := value.(string)
+	if !ok {
+		return errors.New("invalid bill frequency")
+	}
+
+	*f = BillFrequency(strVal)
+	return nil
+}
+
+// Bill represents the bills table structure: a recurring expense the user is reminded of before it's due
+type Bill struct {
+	ID   int64  `gorm:"column:id;primaryKey;autoIncrement"`
+	TgID int64  `gorm:"column:tg_id;not null;index"`
+	Name string `gorm:"column:name;not null"`
+	// Zero when it varies, the user is asked for it when paying
+	Amount    float64             `gorm:"column:amount;not null;type:decimal(15,2);default:0"`
+	Category  TransactionCategory `gorm:"column:category;not null;type:transaction_category;default:'Bills'"`
+	Frequency BillFrequency       `gorm:"column:frequency;not null;type:bill_frequency"`
+	// Day of the month it's due, the last day in shorter months
+	DueDay int `gorm:"column:due_day;not null"`
+	// Month it's due, for yearly bills only
+	DueMonth         time.Month `gorm:"column:due_month;not null;default:0"`
+	RemindDaysBefore int        `gorm:"column:remind_days_before;not null;default:3"`
+	// Due date of the next payment, as a transaction date
+	NextDueDate time.Time `gorm:"column:next_due_date;not null;type:date"`
+	// When the user is reminded of the next payment
+	RemindAt time.Time `gorm:"column:remind_at;not null"`
+
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// TableName overrides the table name
+func (Bill) TableName() string {
+	return "bills"
+}
+
+// FirstDueDate returns the first due date of the bill on or after a day, as a transaction date
+func (b Bill) FirstDueDate(from time.Time) time.Time {
+	from = DateOf(from)
+
+	if b.Frequency == BillFrequencyYearly {
+		due := b.dueDateIn(from.Year(), b.DueMonth)
+		if due.Before(from) {
+			due = b.dueDateIn(from.Year()+1, b.DueMonth)
+		}
+		return due
+	}
+
+	due := b.dueDateIn(from.Year(), from.Month())
+	if due.Before(from) {
+		due = b.dueDateIn(from.Year(), from.Month()+1)
+	}
+	return due
+}
+
+// FollowingDueDate returns the due date after the next one, once that is paid
+func (b Bill) FollowingDueDate() time.Time {
+	return b.FirstDueDate(b.NextDueDate.AddDate(0, 0, 1))
+}
+
+// CurrentDueDate returns the due date the bill waits to be paid for: the next one, unless it was missed by more than
+// BillOverdueDays, then the first due date after that
+func (b Bill) CurrentDueDate(today time.Time) time.Time {
+	today = DateOf(today)
+	for b.NextDueDate.AddDate(0, 0, BillOverdueDays).Before(today) {
+		b.NextDueDate = b.FollowingDueDate()
+	}
+	return b.NextDueDate
+}
+
+// ReminderTime returns when the user is reminded of the next payment, days before it's due at their hour
+func (b Bill) ReminderTime(loc *time.Location) time.Time {
+	day := b.NextDueDate.AddDate(0, 0, -b.RemindDaysBefore)
+	return time.Date(day.Year(), day.Month(), day.Day(), BillReminderHour, 0, 0, 0, loc)
+}
+
+// dueDateIn returns the due date in a month, on the last day of the month when it's shorter
+func (b Bill) dueDateIn(year int, month time.Month) time.Time {
+	// Normalize the month, e.g. month 13 is January of the next year
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(b.DueDay, lastDay)-1)
+}
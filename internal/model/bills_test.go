@@ -0,0 +1,138 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBillFirstDueDate(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		bill Bill
+		from time.Time
+		want time.Time
+	}{
+		{
+			name: "monthly later this month",
+			bill: Bill{Frequency: BillFrequencyMonthly, DueDay: 5},
+			from: day(2025, 3, 2),
+			want: day(2025, 3, 5),
+		},
+		{
+			name: "monthly due today",
+			bill: Bill{Frequency: BillFrequencyMonthly, DueDay: 5},
+			from: day(2025, 3, 5),
+			want: day(2025, 3, 5),
+		},
+		{
+			name: "monthly already past",
+			bill: Bill{Frequency: BillFrequencyMonthly, DueDay: 5},
+			from: day(2025, 3, 6),
+			want: day(2025, 4, 5),
+		},
+		{
+			name: "monthly on a day February doesn't have",
+			bill: Bill{Frequency: BillFrequencyMonthly, DueDay: 31},
+			from: day(2025, 2, 1),
+			want: day(2025, 2, 28),
+		},
+		{
+			name: "monthly past in December",
+			bill: Bill{Frequency: BillFrequencyMonthly, DueDay: 10},
+			from: day(2025, 12, 20),
+			want: day(2026, 1, 10),
+		},
+		{
+			name: "yearly later this year",
+			bill: Bill{Frequency: BillFrequencyYearly, DueDay: 12, DueMonth: time.March},
+			from: day(2025, 1, 15),
+			want: day(2025, 3, 12),
+		},
+		{
+			name: "yearly already past",
+			bill: Bill{Frequency: BillFrequencyYearly, DueDay: 12, DueMonth: time.March},
+			from: day(2025, 3, 13),
+			want: day(2026, 3, 12),
+		},
+		{
+			name: "yearly on a leap day",
+			bill: Bill{Frequency: BillFrequencyYearly, DueDay: 29, DueMonth: time.February},
+			from: day(2025, 1, 1),
+			want: day(2025, 2, 28),
+		},
+		{
+			name: "from a time of the day",
+			bill: Bill{Frequency: BillFrequencyMonthly, DueDay: 5},
+			from: time.Date(2025, 3, 5, 23, 30, 0, 0, time.UTC),
+			want: day(2025, 3, 5),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bill.FirstDueDate(tt.from); !got.Equal(tt.want) {
+				t.Errorf("FirstDueDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBillFollowingDueDate(t *testing.T) {
+	bill := Bill{
+		Frequency:   BillFrequencyMonthly,
+		DueDay:      31,
+		NextDueDate: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	want := time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)
+	if got := bill.FollowingDueDate(); !got.Equal(want) {
+		t.Errorf("FollowingDueDate() = %v, want %v", got, want)
+	}
+}
+
+func TestBillReminderTime(t *testing.T) {
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	bill := Bill{
+		RemindDaysBefore: 3,
+		NextDueDate:      time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+	}
+
+	want := time.Date(2025, 3, 2, 8, 0, 0, 0, time.UTC)
+	if got := bill.ReminderTime(rome); !got.Equal(want) {
+		t.Errorf("ReminderTime() = %v, want %v", got, want)
+	}
+}
+
+func TestBillCurrentDueDate(t *testing.T) {
+	monthly := Bill{Frequency: BillFrequencyMonthly, DueDay: 5, NextDueDate: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)}
+	yearly := Bill{Frequency: BillFrequencyYearly, DueDay: 1, DueMonth: time.July, NextDueDate: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		name  string
+		bill  Bill
+		today time.Time
+		want  time.Time
+	}{
+		{name: "not due yet", bill: monthly, today: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "overdue", bill: monthly, today: time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "missed", bill: monthly, today: time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 4, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "missed for months", bill: monthly, today: time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "missed yearly", bill: yearly, today: time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bill.CurrentDueDate(tt.today); !got.Equal(tt.want) {
+				t.Errorf("CurrentDueDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
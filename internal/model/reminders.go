@@ -14,8 +14,13 @@ const (
 	ReminderTypeWeeklyRecap  ReminderType = "weekly_recap"
 	ReminderTypeMonthlyRecap ReminderType = "monthly_recap"
 	ReminderTypeYearlyRecap  ReminderType = "yearly_recap"
+	ReminderTypeBillDue      ReminderType = "bill_due"
+	ReminderTypeBroadcast    ReminderType = "broadcast"
 )
 
+// RecapReminderTypes are the reminders scheduled from the recap settings and time zone of the user
+var RecapReminderTypes = []ReminderType{ReminderTypeWeeklyRecap, ReminderTypeMonthlyRecap, ReminderTypeYearlyRecap}
+
 // Value implements the driver.Valuer interface for ReminderType
 func (r ReminderType) Value() (driver.Value, error) {
 	return string(r), nil
@@ -45,6 +50,20 @@ const (
 	ReminderStatusProcessing ReminderStatus = "processing"
 	ReminderStatusSent       ReminderStatus = "sent"
 	ReminderStatusFailed     ReminderStatus = "failed"
+	ReminderStatusDead       ReminderStatus = "dead"
+)
+
+// Delivery of reminders
+const (
+	// MaxReminderAttempts is how many times a reminder is sent before giving up on it
+	MaxReminderAttempts = 5
+	// ReminderLease is how long a sender holds a reminder, after which it's considered crashed
+	// and the reminder is sent again
+	ReminderLease = 10 * time.Minute
+	// reminderRetryDelay is the wait before the first retry, doubled after each failed attempt
+	reminderRetryDelay = 5 * time.Minute
+	// reminderMaxRetryDelay caps the wait between retries
+	reminderMaxRetryDelay = 6 * time.Hour
 )
 
 // Value implements the driver.Valuer interface for ReminderStatus
@@ -76,13 +95,36 @@ type Reminder struct {
 	ScheduledFor time.Time      `gorm:"column:scheduled_for;not null;index"`
 	ProcessedAt  *time.Time     `gorm:"column:processed_at"`
 	ErrorMessage *string        `gorm:"column:error_message;type:text"`
-	CreatedAt    time.Time      `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt    time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+	// Delivery attempts so far, when a failed reminder is retried and until when a sender holds it
+	Attempts      int        `gorm:"column:attempts;not null;default:0"`
+	NextAttemptAt *time.Time `gorm:"column:next_attempt_at"`
+	LockedUntil   *time.Time `gorm:"column:locked_until"`
+	// Bill the reminder is about, for bill reminders
+	BillID *int64 `gorm:"column:bill_id"`
+	// Announcement the reminder delivers, for broadcasts
+	BroadcastID *int64 `gorm:"column:broadcast_id"`
+
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
 	// Association to User (optional)
 	User *User `gorm:"foreignKey:TgID;references:TgID"`
 }
 
+// NextRetry returns when a reminder whose last attempt failed is sent again, waiting longer after each attempt.
+// It's false when the reminder ran out of attempts.
+func (r Reminder) NextRetry(now time.Time) (time.Time, bool) {
+	if r.Attempts >= MaxReminderAttempts {
+		return time.Time{}, false
+	}
+
+	delay := reminderRetryDelay
+	for i := 1; i < r.Attempts && delay < reminderMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return now.Add(min(delay, reminderMaxRetryDelay)), true
+}
+
 // TableName overrides the table name
 func (Reminder) TableName() string {
 	return "reminders"
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReminderNextRetry(t *testing.T) {
+	now := time.Date(2025, 3, 12, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+		wantOK   bool
+	}{
+		{
+			name:     "after the first attempt",
+			attempts: 1,
+			want:     5 * time.Minute,
+			wantOK:   true,
+		},
+		{
+			name:     "doubled after each attempt",
+			attempts: 3,
+			want:     20 * time.Minute,
+			wantOK:   true,
+		},
+		{
+			name:     "last retry",
+			attempts: MaxReminderAttempts - 1,
+			want:     40 * time.Minute,
+			wantOK:   true,
+		},
+		{
+			name:     "out of attempts",
+			attempts: MaxReminderAttempts,
+			wantOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Reminder{Attempts: tt.attempts}.NextRetry(now)
+			if ok != tt.wantOK {
+				t.Fatalf("NextRetry() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.Equal(now.Add(tt.want)) {
+				t.Errorf("NextRetry() = %v, want %v", got, now.Add(tt.want))
+			}
+		})
+	}
+}
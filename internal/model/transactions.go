@@ -3,6 +3,7 @@ package model
 import (
 	"database/sql/driver"
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -41,6 +42,11 @@ func IsValidTransactionCategory(category string) bool {
 	return false
 }
 
+// IsIncomeCategory reports whether the category is an income category
+func IsIncomeCategory(category TransactionCategory) bool {
+	return category == CategorySalary || category == CategoryOtherIncomes
+}
+
 // Value implements the driver.Valuer interface for TransactionCategory
 func (t TransactionCategory) Value() (driver.Value, error) {
 	return string(t), nil
@@ -144,6 +150,39 @@ func (Transaction) TableName() string {
 	return "transactions"
 }
 
+// Transaction validation errors
+var (
+	ErrInvalidTransactionType = errors.New("invalid transaction type")
+	ErrInvalidCategory        = errors.New("invalid category")
+	ErrCategoryTypeMismatch   = errors.New("cannot change between expense and income categories")
+	ErrInvalidAmount          = errors.New("amount must be greater than zero")
+	ErrEmptyDescription       = errors.New("description cannot be empty")
+	ErrFutureDate             = errors.New("future dates are not supported")
+)
+
+// Validate checks the rules a transaction must respect when it's created or edited
+func (t Transaction) Validate(now time.Time) error {
+	if t.Type != TypeIncome && t.Type != TypeExpense {
+		return ErrInvalidTransactionType
+	}
+	if !IsValidTransactionCategory(string(t.Category)) {
+		return ErrInvalidCategory
+	}
+	if IsIncomeCategory(t.Category) != (t.Type == TypeIncome) {
+		return ErrCategoryTypeMismatch
+	}
+	if t.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if strings.TrimSpace(t.Description) == "" {
+		return ErrEmptyDescription
+	}
+	if t.Date.After(now) {
+		return ErrFutureDate
+	}
+	return nil
+}
+
 // Get category enum values as a slice of strings
 func GetTransactionCategories() []string {
 	return []string{
@@ -1,6 +1,10 @@
 package model
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+)
 
 func TestIsValidTransactionCategory(t *testing.T) {
 	tests := []struct {
@@ -85,3 +89,41 @@ func TestTransactionTypeValue(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionValidate(t *testing.T) {
+	now := time.Date(2025, 5, 14, 12, 0, 0, 0, time.UTC)
+	valid := Transaction{
+		Type:        TypeExpense,
+		Category:    CategoryGrocery,
+		Amount:      12.5,
+		Description: "Supermarket",
+		Date:        time.Date(2025, 5, 14, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Transaction)
+		want   error
+	}{
+		{name: "valid", modify: func(*Transaction) {}},
+		{name: "valid income", modify: func(t *Transaction) { t.Type = TypeIncome; t.Category = CategorySalary }},
+		{name: "invalid type", modify: func(t *Transaction) { t.Type = "Transfer" }, want: ErrInvalidTransactionType},
+		{name: "invalid category", modify: func(t *Transaction) { t.Category = "Groceries" }, want: ErrInvalidCategory},
+		{name: "income category on expense", modify: func(t *Transaction) { t.Category = CategorySalary }, want: ErrCategoryTypeMismatch},
+		{name: "expense category on income", modify: func(t *Transaction) { t.Type = TypeIncome }, want: ErrCategoryTypeMismatch},
+		{name: "zero amount", modify: func(t *Transaction) { t.Amount = 0 }, want: ErrInvalidAmount},
+		{name: "negative amount", modify: func(t *Transaction) { t.Amount = -3 }, want: ErrInvalidAmount},
+		{name: "blank description", modify: func(t *Transaction) { t.Description = "  " }, want: ErrEmptyDescription},
+		{name: "future date", modify: func(t *Transaction) { t.Date = now.AddDate(0, 0, 1) }, want: ErrFutureDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := valid
+			tt.modify(&tx)
+			if got := tx.Validate(now); !errors.Is(got, tt.want) {
+				t.Errorf("Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -33,23 +33,159 @@ const (
 	// Search-related states
 	StateSelectingSearchCategory StateType = "selecting_search_category"
 	StateEnteringSearchQuery     StateType = "entering_search_query"
+	// The user has to enter a date range for a custom recap
+	StateEnteringRecapRange StateType = "entering_recap_range"
+	// The user has to enter the amount paid for a bill
+	StateEnteringBillAmount StateType = "entering_bill_amount"
+	// The user has been asked to share their location to set their time zone
+	StateSharingTimezoneLocation StateType = "sharing_timezone_location"
+	// An admin has to confirm the announcement to broadcast
+	StateConfirmingBroadcast StateType = "confirming_broadcast"
 	// The user has to confirm an action.
 	StateWaitingConfirm StateType = "waiting_confirm"
 )
 
+// Defaults of the user settings
+const (
+	// DefaultTimezone is the time zone of the users who haven't set theirs
+	DefaultTimezone = "UTC"
+	// DefaultRecapWeekday is the day the weekly recap is sent
+	DefaultRecapWeekday = time.Monday
+	// DefaultRecapHour is the hour recaps are sent at, in the user's time zone
+	DefaultRecapHour = 6
+	// DefaultNudgeHour is the hour of the evening reminder to log, in the user's time zone
+	DefaultNudgeHour = 21
+)
+
+// nudgeBackoffDays are the days between nudges by how many the user ignored in a row
+var nudgeBackoffDays = []int{1, 1, 2, 4, 7}
+
 // CommandType represents the type of command sent by the user
 type CommandType string
 
 // User represents the users table structure
 type User struct {
-	TgID        int64       `gorm:"column:tg_id;primaryKey"`
-	TgUsername  string      `gorm:"column:tg_username;unique"`
+	TgID int64 `gorm:"column:tg_id;primaryKey"`
+	// Profile as last seen on Telegram, it can change or be empty: users are identified by TgID
+	TgUsername  string      `gorm:"column:tg_username"`
 	TgFirstname string      `gorm:"column:tg_firstname"`
 	TgLastname  string      `gorm:"column:tg_lastname"`
 	Name        string      `gorm:"column:name;name"`
 	Session     UserSession `gorm:"column:session;type:jsonb"`
-	CreatedAt   time.Time   `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt   time.Time   `gorm:"column:updated_at;autoUpdateTime"`
+	Timezone    string      `gorm:"column:timezone;not null;default:UTC"`
+	// Recaps the user receives, on their day and hour
+	WeeklyRecap  bool         `gorm:"column:weekly_recap;not null;default:true"`
+	MonthlyRecap bool         `gorm:"column:monthly_recap;not null;default:true"`
+	YearlyRecap  bool         `gorm:"column:yearly_recap;not null;default:true"`
+	RecapWeekday time.Weekday `gorm:"column:recap_weekday;not null;default:1"`
+	RecapHour    int          `gorm:"column:recap_hour;not null;default:6"`
+	// Evening reminder to log when nothing was recorded that day
+	DailyNudge        bool       `gorm:"column:daily_nudge;not null;default:false"`
+	NudgeHour         int        `gorm:"column:nudge_hour;not null;default:21"`
+	NudgeSkipWeekends bool       `gorm:"column:nudge_skip_weekends;not null;default:false"`
+	NudgesIgnored     int        `gorm:"column:nudges_ignored;not null;default:0"`
+	LastNudgeAt       *time.Time `gorm:"column:last_nudge_at"`
+	// Set when the bot can't write to the user anymore, who gets no reminders until they write to it again
+	BotBlockedAt *time.Time `gorm:"column:bot_blocked_at"`
+
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// Location returns the user's time zone, UTC when it isn't set or known
+func (u User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Now returns the current time in the user's time zone
+func (u User) Now() time.Time {
+	return time.Now().In(u.Location())
+}
+
+// Today returns the user's current day, as a transaction date
+func (u User) Today() time.Time {
+	return DateOf(u.Now())
+}
+
+// DateOf returns the calendar day of t at midnight UTC, the way transaction dates are stored
+func DateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// RecapEnabled tells if the user receives the recaps of a type
+func (u User) RecapEnabled(reminderType ReminderType) bool {
+	switch reminderType {
+	case ReminderTypeWeeklyRecap:
+		return u.WeeklyRecap
+	case ReminderTypeMonthlyRecap:
+		return u.MonthlyRecap
+	case ReminderTypeYearlyRecap:
+		return u.YearlyRecap
+	default:
+		return false
+	}
+}
+
+// NextRecap returns when the next recap of a type is due after now, at the user's hour in their time zone:
+// weekly ones on their day of the week, monthly ones on the 1st and yearly ones on January 1st
+func (u User) NextRecap(reminderType ReminderType, now time.Time) time.Time {
+	local := now.In(u.Location())
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, u.RecapHour, 0, 0, 0, local.Location())
+	}
+
+	switch reminderType {
+	case ReminderTypeWeeklyRecap:
+		days := (int(u.RecapWeekday) - int(local.Weekday()) + 7) % 7
+		next := at(local.Year(), local.Month(), local.Day()+days)
+		if !next.After(local) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	case ReminderTypeMonthlyRecap:
+		next := at(local.Year(), local.Month(), 1)
+		if !next.After(local) {
+			next = at(local.Year(), local.Month()+1, 1)
+		}
+		return next
+	default:
+		next := at(local.Year(), time.January, 1)
+		if !next.After(local) {
+			next = at(local.Year()+1, time.January, 1)
+		}
+		return next
+	}
+}
+
+// NudgeDue tells if the user should get the daily nudge now: it's on, it's their hour or later,
+// it isn't a skipped weekend and they weren't nudged for long enough, which grows as they ignore nudges
+func (u User) NudgeDue(now time.Time) bool {
+	if !u.DailyNudge {
+		return false
+	}
+
+	local := now.In(u.Location())
+	if local.Hour() < u.NudgeHour {
+		return false
+	}
+	if u.NudgeSkipWeekends && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return false
+	}
+	if u.LastNudgeAt == nil {
+		return true
+	}
+
+	ignored := min(max(u.NudgesIgnored, 0), len(nudgeBackoffDays)-1)
+	days := int(DateOf(local).Sub(DateOf(u.LastNudgeAt.In(u.Location()))).Hours() / 24)
+	return days >= nudgeBackoffDays[ignored]
 }
 
 type UserSession struct {
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 )
 
 func TestUserSessionValueAndScan(t *testing.T) {
@@ -78,3 +79,229 @@ func TestUserSessionValueAndScan(t *testing.T) {
 		}
 	})
 }
+
+func TestUserLocation(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		want     string
+	}{
+		{"unset", "", "UTC"},
+		{"named zone", "Europe/Rome", "Europe/Rome"},
+		{"fixed offset", "Etc/GMT-2", "Etc/GMT-2"},
+		{"unknown zone", "Mars/Olympus", "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := User{Timezone: tt.timezone}
+			if got := user.Location().String(); got != tt.want {
+				t.Errorf("Location() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateOf(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	// 23:30 UTC on the 31st is already the 1st in Tokyo
+	utc := time.Date(2025, 1, 31, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{"utc", utc, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"ahead of utc", utc.In(tokyo), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DateOf(tt.t); !got.Equal(tt.want) {
+				t.Errorf("DateOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserNextRecap(t *testing.T) {
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	user := User{Timezone: "Europe/Rome", RecapWeekday: time.Monday, RecapHour: 6}
+
+	tests := []struct {
+		name         string
+		reminderType ReminderType
+		user         User
+		now          time.Time
+		want         time.Time
+	}{
+		{
+			name:         "weekly later this week",
+			reminderType: ReminderTypeWeeklyRecap,
+			user:         user,
+			now:          time.Date(2025, 3, 12, 10, 0, 0, 0, rome), // Wednesday
+			want:         time.Date(2025, 3, 17, 6, 0, 0, 0, rome),
+		},
+		{
+			name:         "weekly today before the hour",
+			reminderType: ReminderTypeWeeklyRecap,
+			user:         user,
+			now:          time.Date(2025, 3, 17, 5, 0, 0, 0, rome),
+			want:         time.Date(2025, 3, 17, 6, 0, 0, 0, rome),
+		},
+		{
+			name:         "weekly today at the hour",
+			reminderType: ReminderTypeWeeklyRecap,
+			user:         user,
+			now:          time.Date(2025, 3, 17, 6, 0, 0, 0, rome),
+			want:         time.Date(2025, 3, 24, 6, 0, 0, 0, rome),
+		},
+		{
+			name:         "weekly on another day and hour",
+			reminderType: ReminderTypeWeeklyRecap,
+			user:         User{Timezone: "Europe/Rome", RecapWeekday: time.Sunday, RecapHour: 20},
+			now:          time.Date(2025, 3, 12, 10, 0, 0, 0, rome),
+			want:         time.Date(2025, 3, 16, 20, 0, 0, 0, rome),
+		},
+		{
+			name:         "weekly in the user's time zone",
+			reminderType: ReminderTypeWeeklyRecap,
+			user:         user,
+			now:          time.Date(2025, 3, 16, 23, 30, 0, 0, time.UTC), // Already Monday in Rome
+			want:         time.Date(2025, 3, 17, 6, 0, 0, 0, rome),
+		},
+		{
+			name:         "monthly",
+			reminderType: ReminderTypeMonthlyRecap,
+			user:         user,
+			now:          time.Date(2025, 12, 15, 10, 0, 0, 0, rome),
+			want:         time.Date(2026, 1, 1, 6, 0, 0, 0, rome),
+		},
+		{
+			name:         "monthly on the first before the hour",
+			reminderType: ReminderTypeMonthlyRecap,
+			user:         user,
+			now:          time.Date(2025, 4, 1, 2, 0, 0, 0, rome),
+			want:         time.Date(2025, 4, 1, 6, 0, 0, 0, rome),
+		},
+		{
+			name:         "yearly",
+			reminderType: ReminderTypeYearlyRecap,
+			user:         user,
+			now:          time.Date(2025, 1, 1, 6, 0, 0, 0, rome),
+			want:         time.Date(2026, 1, 1, 6, 0, 0, 0, rome),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.NextRecap(tt.reminderType, tt.now); !got.Equal(tt.want) {
+				t.Errorf("NextRecap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserNudgeDue(t *testing.T) {
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2025, 3, day, hour, 0, 0, 0, rome)
+		return &t
+	}
+
+	// 12 March 2025 is a Wednesday, 15 March a Saturday
+	tests := []struct {
+		name string
+		user User
+		now  time.Time
+		want bool
+	}{
+		{
+			name: "off",
+			user: User{Timezone: "Europe/Rome", NudgeHour: 21},
+			now:  *at(12, 21),
+			want: false,
+		},
+		{
+			name: "first nudge at the hour",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21},
+			now:  *at(12, 21),
+			want: true,
+		},
+		{
+			name: "before the hour",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21},
+			now:  *at(12, 20),
+			want: false,
+		},
+		{
+			name: "hour in the user's time zone",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21},
+			now:  time.Date(2025, 3, 12, 20, 30, 0, 0, time.UTC), // 21:30 in Rome
+			want: true,
+		},
+		{
+			name: "already nudged today",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21, LastNudgeAt: at(12, 21)},
+			now:  *at(12, 22),
+			want: false,
+		},
+		{
+			name: "nudged yesterday",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21, LastNudgeAt: at(11, 21)},
+			now:  *at(12, 21),
+			want: true,
+		},
+		{
+			name: "weekend skipped",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21, NudgeSkipWeekends: true},
+			now:  *at(15, 21),
+			want: false,
+		},
+		{
+			name: "weekend not skipped",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21},
+			now:  *at(15, 21),
+			want: true,
+		},
+		{
+			name: "backing off after ignored nudges",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21, NudgesIgnored: 3, LastNudgeAt: at(10, 21)},
+			now:  *at(12, 21),
+			want: false,
+		},
+		{
+			name: "backed off long enough",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21, NudgesIgnored: 3, LastNudgeAt: at(8, 21)},
+			now:  *at(12, 21),
+			want: true,
+		},
+		{
+			name: "back-off capped at a week",
+			user: User{Timezone: "Europe/Rome", DailyNudge: true, NudgeHour: 21, NudgesIgnored: 30, LastNudgeAt: at(5, 21)},
+			now:  *at(12, 21),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.NudgeDue(tt.now); got != tt.want {
+				t.Errorf("NudgeDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package pdf
+
+import (
+	"bytes"
+	"cashout/internal/model"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "ascii",
+			input: "Coffee 3.50",
+			want:  "Coffee 3.50",
+		},
+		{
+			name:  "euro sign",
+			input: "3.50 €",
+			want:  "3.50 \x80",
+		},
+		{
+			name:  "latin-1 accents",
+			input: "Caffè",
+			want:  "Caff\xe8",
+		},
+		{
+			name:  "unsupported characters",
+			input: "🛒 Grocery",
+			want:  "? Grocery",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := encode(tt.input); got != tt.want {
+				t.Errorf("encode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got := escape(`a(b)c\d`); got != `a\(b\)c\\d` {
+		t.Errorf("escape() = %q", got)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	// Digits are 556/1000 em wide in Helvetica
+	if got := TextWidth("1234", 10); got != 22.24 {
+		t.Errorf("TextWidth() = %v, want 22.24", got)
+	}
+}
+
+func TestDocumentBytes(t *testing.T) {
+	doc := New()
+	doc.AddPage()
+	doc.Text(50, 50, 12, false, "Hello")
+	doc.AddPage()
+	doc.Text(50, 50, 12, true, "World")
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+
+	for _, want := range []string{"%PDF-1.4", "/Count 2", "(Hello) Tj", "(World) Tj", "startxref", "%%EOF"} {
+		if !bytes.Contains(data, []byte(want)) {
+			t.Errorf("Bytes() output doesn't contain %q", want)
+		}
+	}
+}
+
+func TestStatementCategoryTotals(t *testing.T) {
+	s := Statement{
+		Transactions: []model.Transaction{
+			{Type: model.TypeExpense, Category: model.CategoryGrocery, Amount: 30},
+			{Type: model.TypeExpense, Category: model.CategoryGrocery, Amount: 45},
+			{Type: model.TypeExpense, Category: model.CategoryCar, Amount: 25},
+			{Type: model.TypeIncome, Category: model.CategorySalary, Amount: 2000},
+		},
+	}
+
+	income, expenses := s.Totals()
+	if income != 2000 || expenses != 100 {
+		t.Fatalf("Totals() = %v, %v, want 2000, 100", income, expenses)
+	}
+
+	got := s.CategoryTotals()
+	want := []CategoryTotal{
+		{Type: model.TypeExpense, Category: model.CategoryGrocery, Amount: 75, Percentage: 75},
+		{Type: model.TypeExpense, Category: model.CategoryCar, Amount: 25, Percentage: 25},
+		{Type: model.TypeIncome, Category: model.CategorySalary, Amount: 2000, Percentage: 100},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("CategoryTotals() returned %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CategoryTotals()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestStatementRenderPaginates(t *testing.T) {
+	var transactions []model.Transaction
+	for i := 0; i < 120; i++ {
+		transactions = append(transactions, model.Transaction{
+			Date:        time.Date(2025, 3, 1+i%28, 0, 0, 0, 0, time.UTC),
+			Type:        model.TypeExpense,
+			Category:    model.CategoryGrocery,
+			Amount:      float64(i),
+			Description: "Groceries",
+		})
+	}
+
+	s := Statement{
+		Name:         "Alice",
+		Period:       "March 2025",
+		From:         time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:           time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+		Transactions: transactions,
+		GeneratedAt:  time.Date(2025, 4, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	data, err := s.Render()
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	if !bytes.Contains(data, []byte("(Page 2 of ")) {
+		t.Errorf("Render() expected the transaction list to span multiple pages")
+	}
+}
@@ -0,0 +1,225 @@
+package pdf
+
+import (
+	"cashout/internal/model"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	marginLeft   = 50.0
+	marginRight  = PageWidth - 50.0
+	marginTop    = 60.0
+	marginBottom = PageHeight - 60.0
+	rowHeight    = 16.0
+)
+
+// Statement holds the data needed to render a PDF statement for a period
+type Statement struct {
+	Name         string
+	Period       string
+	From         time.Time
+	To           time.Time
+	Transactions []model.Transaction
+	GeneratedAt  time.Time
+}
+
+// CategoryTotal is the total amount for a category, with its share of the type total
+type CategoryTotal struct {
+	Type       model.TransactionType
+	Category   model.TransactionCategory
+	Amount     float64
+	Percentage float64
+}
+
+// Totals returns the total income and expenses of the statement
+func (s Statement) Totals() (income, expenses float64) {
+	for _, t := range s.Transactions {
+		if t.Type == model.TypeIncome {
+			income += t.Amount
+		} else {
+			expenses += t.Amount
+		}
+	}
+	return income, expenses
+}
+
+// CategoryTotals returns the per-category totals, expenses first, each group sorted by amount (descending)
+func (s Statement) CategoryTotals() []CategoryTotal {
+	income, expenses := s.Totals()
+
+	sums := make(map[model.TransactionType]map[model.TransactionCategory]float64)
+	for _, t := range s.Transactions {
+		if sums[t.Type] == nil {
+			sums[t.Type] = make(map[model.TransactionCategory]float64)
+		}
+		sums[t.Type][t.Category] += t.Amount
+	}
+
+	var result []CategoryTotal
+	for _, tt := range []model.TransactionType{model.TypeExpense, model.TypeIncome} {
+		total := expenses
+		if tt == model.TypeIncome {
+			total = income
+		}
+
+		var group []CategoryTotal
+		for cat, amount := range sums[tt] {
+			var percentage float64
+			if total > 0 {
+				percentage = amount / total * 100
+			}
+			group = append(group, CategoryTotal{Type: tt, Category: cat, Amount: amount, Percentage: percentage})
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Amount > group[j].Amount
+		})
+
+		result = append(result, group...)
+	}
+
+	return result
+}
+
+// Render generates the PDF statement
+func (s Statement) Render() ([]byte, error) {
+	doc := New()
+	doc.AddPage()
+
+	// --- HEADER ---
+	y := marginTop
+	doc.Text(marginLeft, y, 20, true, "Cashout Statement")
+	y += 24
+	doc.Text(marginLeft, y, 11, false, s.Name)
+	doc.TextRight(marginRight, y, 11, true, s.Period)
+	y += 16
+	doc.Text(marginLeft, y, 9, false, fmt.Sprintf("%s - %s", s.From.Format("02 Jan 2006"), s.To.Format("02 Jan 2006")))
+	doc.TextRight(marginRight, y, 9, false, fmt.Sprintf("Generated on %s", s.GeneratedAt.Format("02 Jan 2006 15:04")))
+	y += 10
+	doc.Line(marginLeft, y, marginRight, y)
+
+	// --- SUMMARY ---
+	income, expenses := s.Totals()
+	y += 28
+	doc.Text(marginLeft, y, 13, true, "Summary")
+	y += 20
+	for _, row := range []struct {
+		label  string
+		amount float64
+	}{
+		{"Total Income", income},
+		{"Total Expenses", expenses},
+		{"Balance", income - expenses},
+	} {
+		doc.Text(marginLeft, y, 10, row.label == "Balance", row.label)
+		doc.TextRight(marginLeft+250, y, 10, row.label == "Balance", formatAmount(row.amount))
+		y += rowHeight
+	}
+	doc.Text(marginLeft, y, 10, false, "Transactions")
+	doc.TextRight(marginLeft+250, y, 10, false, fmt.Sprintf("%d", len(s.Transactions)))
+
+	// --- CATEGORIES ---
+	y += 32
+	doc.Text(marginLeft, y, 13, true, "Categories")
+	y += 8
+	y = categoryHeader(doc, y)
+
+	for _, ct := range s.CategoryTotals() {
+		if y > marginBottom {
+			doc.AddPage()
+			y = categoryHeader(doc, marginTop)
+		}
+		doc.Text(marginLeft+4, y, 9, false, string(ct.Type))
+		doc.Text(marginLeft+90, y, 9, false, string(ct.Category))
+		doc.TextRight(marginRight-90, y, 9, false, formatAmount(ct.Amount))
+		doc.TextRight(marginRight-4, y, 9, false, fmt.Sprintf("%.1f%%", ct.Percentage))
+		y += rowHeight
+	}
+
+	// --- TRANSACTIONS ---
+	y += 24
+	if y > marginBottom-40 {
+		doc.AddPage()
+		y = marginTop
+	}
+	doc.Text(marginLeft, y, 13, true, "Transactions")
+	y += 8
+	y = transactionHeader(doc, y)
+
+	transactions := make([]model.Transaction, len(s.Transactions))
+	copy(transactions, s.Transactions)
+	sort.SliceStable(transactions, func(i, j int) bool {
+		return transactions[i].Date.Before(transactions[j].Date)
+	})
+
+	if len(transactions) == 0 {
+		doc.Text(marginLeft+4, y, 9, false, "No transactions in this period.")
+	}
+
+	for _, t := range transactions {
+		if y > marginBottom {
+			doc.AddPage()
+			y = transactionHeader(doc, marginTop)
+		}
+
+		amount := formatAmount(t.Amount)
+		if t.Type == model.TypeExpense {
+			amount = "-" + amount
+		}
+
+		doc.Text(marginLeft+4, y, 9, false, t.Date.Format("02-01-2006"))
+		doc.Text(marginLeft+75, y, 9, false, string(t.Category))
+		doc.Text(marginLeft+170, y, 9, false, truncate(t.Description, 230, 9))
+		doc.TextRight(marginRight-4, y, 9, false, amount)
+		y += rowHeight
+	}
+
+	// --- FOOTER ---
+	pages := doc.PageCount()
+	for i := 0; i < pages; i++ {
+		doc.current = doc.pages[i]
+		doc.TextRight(marginRight, PageHeight-30, 8, false, fmt.Sprintf("Page %d of %d", i+1, pages))
+	}
+
+	return doc.Bytes()
+}
+
+// categoryHeader draws the category table header and returns the y of the first row
+func categoryHeader(doc *Document, y float64) float64 {
+	doc.FillRect(marginLeft, y, marginRight-marginLeft, rowHeight+2, 0.9)
+	y += 12
+	doc.Text(marginLeft+4, y, 9, true, "Type")
+	doc.Text(marginLeft+90, y, 9, true, "Category")
+	doc.TextRight(marginRight-90, y, 9, true, "Amount")
+	doc.TextRight(marginRight-4, y, 9, true, "Share")
+	return y + rowHeight + 2
+}
+
+// transactionHeader draws the transaction table header and returns the y of the first row
+func transactionHeader(doc *Document, y float64) float64 {
+	doc.FillRect(marginLeft, y, marginRight-marginLeft, rowHeight+2, 0.9)
+	y += 12
+	doc.Text(marginLeft+4, y, 9, true, "Date")
+	doc.Text(marginLeft+75, y, 9, true, "Category")
+	doc.Text(marginLeft+170, y, 9, true, "Description")
+	doc.TextRight(marginRight-4, y, 9, true, "Amount")
+	return y + rowHeight + 2
+}
+
+func formatAmount(amount float64) string {
+	return fmt.Sprintf("%.2f €", amount)
+}
+
+// truncate shortens s so that it fits in maxWidth points
+func truncate(s string, maxWidth, size float64) string {
+	if TextWidth(s, size) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", size) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
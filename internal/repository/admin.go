@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"cashout/internal/db"
+	"cashout/internal/model"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// AdminActivityPeriod is the period users must have recorded transactions in to count as active
+	AdminActivityPeriod = 30 * 24 * time.Hour
+
+	// maxBroadcastLength leaves room for the announcement header within Telegram's 4096 characters
+	maxBroadcastLength = 4000
+)
+
+var ErrInvalidBroadcast = errors.New("the announcement must be between 1 and 4000 characters")
+
+type Admin struct {
+	Repository
+}
+
+// UsersActivity retrieves the users with their transactions in total and in the activity period, the most recently active first
+func (r *Admin) UsersActivity(now time.Time, limit int) ([]db.UserActivity, error) {
+	return r.DB.GetUsersActivity(now.Add(-AdminActivityPeriod), limit)
+}
+
+// UsersSummary counts the users, those active in the activity period and those who blocked the bot
+func (r *Admin) UsersSummary(now time.Time) (db.UsersSummary, error) {
+	return r.DB.GetUsersSummary(now.Add(-AdminActivityPeriod))
+}
+
+// ReminderStatusCounts counts the reminders not sent yet or given up on, by type and status
+func (r *Admin) ReminderStatusCounts() ([]db.ReminderStatusCount, error) {
+	return r.DB.GetReminderStatusCounts()
+}
+
+// FailedReminders retrieves the latest reminders waiting for a retry or given up on
+func (r *Admin) FailedReminders(limit int) ([]model.Reminder, error) {
+	return r.DB.GetFailedReminders(limit)
+}
+
+// Broadcast queues an announcement for all the users the bot can write to. The scheduler sends it in batches,
+// at the pace Telegram allows.
+func (r *Admin) Broadcast(message string, createdBy int64, now time.Time) (*model.Broadcast, error) {
+	message = strings.TrimSpace(message)
+	if message == "" || len([]rune(message)) > maxBroadcastLength {
+		return nil, ErrInvalidBroadcast
+	}
+
+	broadcast := &model.Broadcast{
+		Message:   message,
+		CreatedBy: createdBy,
+	}
+	if err := r.DB.CreateBroadcast(broadcast, now.UTC()); err != nil {
+		return nil, err
+	}
+	return broadcast, nil
+}
+
+// GetBroadcast retrieves an announcement
+func (r *Admin) GetBroadcast(id int64) (model.Broadcast, error) {
+	broadcast, err := r.DB.GetBroadcast(id)
+	if err != nil {
+		return model.Broadcast{}, err
+	}
+	return *broadcast, nil
+}
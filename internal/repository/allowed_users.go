@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"cashout/internal/model"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidUsername = errors.New("invalid username")
+
+// Access is who can use a private deployment as set in the environment: the admins (ADMIN_USERS) and the users
+// in ALLOWED_USERS, by Telegram ID or username. The admins allow more users at runtime.
+type Access struct {
+	Private   bool
+	AdminIDs  map[int64]struct{}
+	TgIDs     map[int64]struct{}
+	Usernames map[string]struct{}
+}
+
+// NewAccessFromEnv reads who can use the bot from ALLOWED_USERS and ADMIN_USERS, it's public when both are empty
+func NewAccessFromEnv(logger *logrus.Logger) Access {
+	access := Access{
+		AdminIDs:  make(map[int64]struct{}),
+		TgIDs:     make(map[int64]struct{}),
+		Usernames: make(map[string]struct{}),
+	}
+
+	// Users are allowed by Telegram ID, which never changes, or by username
+	allowed := os.Getenv("ALLOWED_USERS")
+	if allowed != "" {
+		access.Private = true
+		for _, u := range strings.Split(allowed, ",") {
+			if tgID, ok := parseTgID(u); ok {
+				access.TgIDs[tgID] = struct{}{}
+				continue
+			}
+			if username := model.NormalizeUsername(u); username != "" {
+				access.Usernames[username] = struct{}{}
+			}
+		}
+	}
+
+	// With admins the deployment is private too, they allow users at runtime
+	admins := os.Getenv("ADMIN_USERS")
+	if admins != "" {
+		access.Private = true
+		for _, a := range strings.Split(admins, ",") {
+			tgID, ok := parseTgID(a)
+			if !ok {
+				logger.Warnf("Ignoring invalid admin Telegram ID %q", a)
+				continue
+			}
+			access.AdminIDs[tgID] = struct{}{}
+		}
+	}
+
+	return access
+}
+
+// IsAdmin checks whether the user is one of the admins of the deployment
+func (a Access) IsAdmin(tgID int64) bool {
+	_, ok := a.AdminIDs[tgID]
+	return ok
+}
+
+type AllowedUsers struct {
+	Repository
+	Access Access
+}
+
+// CanUse checks whether a user can use the bot and the web dashboard: anyone on a public deployment, otherwise
+// admins, users in ALLOWED_USERS and those allowed at runtime
+func (r *AllowedUsers) CanUse(tgID int64, username string) (bool, error) {
+	if !r.Access.Private || r.Access.IsAdmin(tgID) {
+		return true, nil
+	}
+	if _, ok := r.Access.TgIDs[tgID]; ok {
+		return true, nil
+	}
+	username = model.NormalizeUsername(username)
+	if _, ok := r.Access.Usernames[username]; ok && username != "" {
+		return true, nil
+	}
+	return r.DB.IsAllowedUser(tgID, username)
+}
+
+// Add allows a Telegram ID or username at runtime, false if it already was
+func (r *AllowedUsers) Add(username string, addedBy int64) (bool, error) {
+	if tgID, ok := parseTgID(username); ok {
+		return r.DB.AddAllowedTgID(tgID, addedBy)
+	}
+	username = model.NormalizeUsername(username)
+	if username == "" || len(username) > 64 {
+		return false, ErrInvalidUsername
+	}
+	return r.DB.AddAllowedUser(username, addedBy)
+}
+
+// Remove removes a Telegram ID or username allowed at runtime, false if it wasn't.
+// The user is logged out of the web dashboard and their API tokens revoked, unless they're still allowed.
+func (r *AllowedUsers) Remove(username string) (bool, error) {
+	if tgID, ok := parseTgID(username); ok {
+		removed, err := r.DB.DeleteAllowedTgID(tgID)
+		if err != nil || !removed {
+			return removed, err
+		}
+		return true, r.revokeIfDisallowed(&tgID, nil)
+	}
+
+	username = model.NormalizeUsername(username)
+	removed, err := r.DB.DeleteAllowedUser(username)
+	if err != nil || !removed {
+		return removed, err
+	}
+	return true, r.revokeIfDisallowed(nil, &username)
+}
+
+// RemoveByID removes a user allowed at runtime, false if they weren't.
+// The user is logged out of the web dashboard and their API tokens revoked, unless they're still allowed.
+func (r *AllowedUsers) RemoveByID(id int64) (bool, error) {
+	allowed, err := r.DB.GetAllowedUser(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	removed, err := r.DB.DeleteAllowedUserByID(id)
+	if err != nil || !removed {
+		return removed, err
+	}
+	return true, r.revokeIfDisallowed(allowed.TgID, allowed.Username)
+}
+
+// revokeIfDisallowed deletes the web sessions and revokes the API tokens of a user who can't use the bot anymore
+func (r *AllowedUsers) revokeIfDisallowed(tgID *int64, username *string) error {
+	var user *model.User
+	var err error
+	switch {
+	case tgID != nil:
+		user, err = r.DB.GetUser(*tgID)
+	case username != nil:
+		user, err = r.DB.GetUserByUsername(*username)
+	default:
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// They never wrote to the bot, so they have nothing to revoke
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	allowed, err := r.CanUse(user.TgID, user.TgUsername)
+	if err != nil || allowed {
+		return err
+	}
+
+	if err := r.DB.DeleteUserWebSessions(user.TgID); err != nil {
+		return err
+	}
+	return r.DB.RevokeUserAPITokens(user.TgID, time.Now().UTC())
+}
+
+// List retrieves the users allowed at runtime
+func (r *AllowedUsers) List() ([]model.AllowedUser, error) {
+	return r.DB.GetAllowedUsers()
+}
+
+// parseTgID parses a Telegram ID, usernames can't be numbers
+func parseTgID(s string) (int64, bool) {
+	tgID, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return tgID, err == nil && tgID != 0
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"cashout/internal/model"
+)
+
+type Anomalies struct {
+	Repository
+}
+
+// Create stores the anomaly and returns false if it was already known
+func (r *Anomalies) Create(anomaly *model.Anomaly) (bool, error) {
+	return r.DB.CreateAnomaly(anomaly)
+}
+
+func (r *Anomalies) Dismiss(id int64, tgID int64) error {
+	return r.DB.UpdateAnomalyStatus(id, tgID, model.AnomalyStatusDismissed)
+}
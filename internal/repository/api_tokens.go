@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"cashout/internal/model"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxAPITokens is how many active tokens a user can have
+	MaxAPITokens = 10
+
+	maxAPITokenNameLength = 64
+
+	// Skip the last used update if the token was used less than this ago
+	apiTokenTouchInterval = time.Minute
+)
+
+var (
+	ErrTooManyAPITokens  = errors.New("too many active tokens, revoke one first")
+	ErrEmptyAPITokenName = errors.New("token name can't be empty")
+)
+
+type APITokens struct {
+	Repository
+}
+
+// Create generates a new token for the user and returns it in clear, the only time it's available
+func (r *APITokens) Create(tgID int64, name string, scope model.APITokenScope) (string, *model.APIToken, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, ErrEmptyAPITokenName
+	}
+	name = truncateRunes(name, maxAPITokenNameLength)
+
+	tokens, err := r.DB.GetUserAPITokens(tgID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(tokens) >= MaxAPITokens {
+		return "", nil, ErrTooManyAPITokens
+	}
+
+	secret, err := generateSessionID()
+	if err != nil {
+		return "", nil, err
+	}
+	token := model.APITokenPrefix + secret
+
+	apiToken := &model.APIToken{
+		TgID:      tgID,
+		Name:      name,
+		Hint:      model.APITokenHint(token),
+		TokenHash: model.HashAPIToken(token),
+		Scope:     scope,
+	}
+
+	if err := r.DB.CreateAPIToken(apiToken); err != nil {
+		return "", nil, err
+	}
+
+	return token, apiToken, nil
+}
+
+// Authenticate returns the active token matching the given one, recording its use
+func (r *APITokens) Authenticate(token string) (*model.APIToken, error) {
+	if !strings.HasPrefix(token, model.APITokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	apiToken, err := r.DB.GetAPITokenByHash(model.HashAPIToken(token))
+	if err != nil || !apiToken.IsValid() {
+		return nil, ErrInvalidToken
+	}
+
+	now := time.Now().UTC()
+	if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) > apiTokenTouchInterval {
+		if err := r.DB.TouchAPIToken(apiToken.ID, now); err != nil {
+			r.Logger.Warnf("Failed to update last use of API token %d: %v", apiToken.ID, err)
+		}
+		apiToken.LastUsedAt = &now
+	}
+
+	return apiToken, nil
+}
+
+// GetUserTokens returns the active tokens of a user, newest first
+func (r *APITokens) GetUserTokens(tgID int64) ([]model.APIToken, error) {
+	return r.DB.GetUserAPITokens(tgID)
+}
+
+// Revoke disables one of the user's tokens
+func (r *APITokens) Revoke(id int64, tgID int64) error {
+	return r.DB.RevokeAPIToken(id, tgID)
+}
@@ -11,6 +11,13 @@ import (
 
 var ErrInvalidToken = errors.New("invalid or expired token")
 
+const (
+	// Skip the last seen update if the session was used less than this ago
+	webSessionTouchInterval = time.Minute
+
+	maxUserAgentLength = 512
+)
+
 type Auth struct {
 	Repository
 }
@@ -98,17 +105,30 @@ func (r *Auth) VerifyAuthToken(token string) (*model.User, error) {
 	return user, nil
 }
 
-// CreateWebSession creates a new web session for a user
-func (r *Auth) CreateWebSession(tgID int64) (*model.WebSession, error) {
+// CreateWebSession creates a new web session for a user on a device. Remembered sessions last longer.
+func (r *Auth) CreateWebSession(tgID int64, remember bool, userAgent, ipAddress string) (*model.WebSession, error) {
 	sessionID, err := generateSessionID()
 	if err != nil {
 		return nil, err
 	}
 
+	userAgent = truncateRunes(userAgent, maxUserAgentLength)
+
+	duration := model.SessionDuration
+	if remember {
+		duration = model.RememberSessionDuration
+	}
+
+	now := time.Now().UTC()
 	session := &model.WebSession{
-		ID:        sessionID,
-		TgID:      tgID,
-		ExpiresAt: time.Now().UTC().Add(24 * time.Hour), // 24 hours session in UTC
+		ID:         sessionID,
+		TgID:       tgID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		Remember:   remember,
+		LastSeenAt: now,
+		RotatedAt:  now,
+		ExpiresAt:  now.Add(duration),
 	}
 
 	if err := r.DB.CreateWebSession(session); err != nil {
@@ -128,6 +148,53 @@ func (r *Auth) DeleteWebSession(sessionID string) error {
 	return r.DB.DeleteWebSession(sessionID)
 }
 
+// GetUserWebSessions retrieves the active web sessions of a user
+func (r *Auth) GetUserWebSessions(tgID int64) ([]model.WebSession, error) {
+	return r.DB.GetUserWebSessions(tgID)
+}
+
+// RevokeWebSession logs out a user's web session by its public ID
+func (r *Auth) RevokeWebSession(publicID int64, tgID int64) error {
+	return r.DB.DeleteUserWebSession(publicID, tgID)
+}
+
+// RevokeOtherWebSessions logs out all the web sessions of a user but the given one, which can be empty
+func (r *Auth) RevokeOtherWebSessions(tgID int64, sessionID string) (int64, error) {
+	return r.DB.DeleteUserWebSessionsExcept(tgID, sessionID)
+}
+
+// TouchWebSession records that the session was just used, at most once a minute
+func (r *Auth) TouchWebSession(session *model.WebSession, ipAddress string) {
+	now := time.Now().UTC()
+	if now.Sub(session.LastSeenAt) < webSessionTouchInterval && session.IPAddress == ipAddress {
+		return
+	}
+
+	if err := r.DB.TouchWebSession(session.ID, now, ipAddress); err != nil {
+		r.Logger.Warnf("Failed to update last use of web session %d: %v", session.PublicID, err)
+		return
+	}
+	session.LastSeenAt = now
+	session.IPAddress = ipAddress
+}
+
+// RotateWebSession gives the session a new ID, so that a leaked cookie stops working
+func (r *Auth) RotateWebSession(session *model.WebSession) error {
+	newID, err := generateSessionID()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if err := r.DB.RotateWebSession(session.ID, newID, now); err != nil {
+		return err
+	}
+
+	session.ID = newID
+	session.RotatedAt = now
+	return nil
+}
+
 // CleanupExpiredTokens removes expired auth tokens and sessions
 func (r *Auth) CleanupExpiredTokens() error {
 	return r.DB.CleanupExpiredAuthData()
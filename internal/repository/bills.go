@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"errors"
+	"time"
+)
+
+const (
+	// MaxBills is how many bills a user can have
+	MaxBills = 30
+
+	// BillSnooze is how long a snoozed bill reminder waits
+	BillSnooze = 24 * time.Hour
+)
+
+var (
+	ErrTooManyBills    = errors.New("too many bills, delete one first")
+	ErrBillAlreadyPaid = errors.New("bill already paid")
+)
+
+type Bills struct {
+	Repository
+}
+
+// Create adds a bill of the user, due next on or after today and reminded days before that
+func (r *Bills) Create(user model.User, spec utils.BillSpec) (*model.Bill, error) {
+	bills, err := r.DB.GetUserBills(user.TgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(bills) >= MaxBills {
+		return nil, ErrTooManyBills
+	}
+
+	bill := &model.Bill{
+		TgID:             user.TgID,
+		Name:             spec.Name,
+		Amount:           spec.Amount,
+		Category:         spec.Category,
+		Frequency:        spec.Frequency,
+		DueDay:           spec.DueDay,
+		DueMonth:         spec.DueMonth,
+		RemindDaysBefore: spec.RemindDaysBefore,
+	}
+	bill.NextDueDate = bill.FirstDueDate(user.Today())
+	bill.RemindAt = billRemindAt(user, *bill)
+
+	if err := r.DB.CreateBill(bill); err != nil {
+		return nil, err
+	}
+	return bill, nil
+}
+
+// Get retrieves a bill of the user
+func (r *Bills) Get(id int64, tgID int64) (model.Bill, error) {
+	bill, err := r.DB.GetUserBill(id, tgID)
+	if err != nil {
+		return model.Bill{}, err
+	}
+	return *bill, nil
+}
+
+// List retrieves the bills of the user, the next due first
+func (r *Bills) List(tgID int64) ([]model.Bill, error) {
+	return r.DB.GetUserBills(tgID)
+}
+
+// Delete deletes a bill of the user, with its reminders
+func (r *Bills) Delete(id int64, tgID int64) error {
+	return r.DB.DeleteBill(id, tgID)
+}
+
+// MarkPaid pays the bill due on a date: it adds the expense transaction dated today, with the bill's amount
+// unless another one is given, and moves the bill to its next due date.
+// It fails with ErrBillAlreadyPaid when that date was already paid.
+func (r *Bills) MarkPaid(user model.User, bill model.Bill, dueDate time.Time, amount float64) (model.Transaction, error) {
+	if amount <= 0 {
+		amount = bill.Amount
+	}
+
+	transaction := model.Transaction{
+		TgID:        user.TgID,
+		Date:        user.Today(),
+		Type:        model.TypeExpense,
+		Category:    bill.Category,
+		Amount:      amount,
+		Currency:    model.CurrencyEUR,
+		Description: bill.Name,
+	}
+	if err := transaction.Validate(user.Today()); err != nil {
+		return model.Transaction{}, err
+	}
+
+	bill.NextDueDate = bill.FollowingDueDate()
+	bill.RemindAt = billRemindAt(user, bill)
+
+	paid, err := r.DB.PayBill(&bill, model.DateOf(dueDate), &transaction)
+	if err != nil {
+		return model.Transaction{}, err
+	}
+	if !paid {
+		return model.Transaction{}, ErrBillAlreadyPaid
+	}
+	return transaction, nil
+}
+
+// SkipMissed moves a bill that was never marked paid, long after its due date, on to its current due date
+// so the user is reminded of that one
+func (r *Bills) SkipMissed(user model.User, bill model.Bill) (model.Bill, error) {
+	due := bill.CurrentDueDate(user.Today())
+	if due.Equal(bill.NextDueDate) {
+		return bill, nil
+	}
+
+	bill.NextDueDate = due
+	bill.RemindAt = billRemindAt(user, bill)
+	if err := r.DB.UpdateBill(&bill); err != nil {
+		return model.Bill{}, err
+	}
+	return bill, r.DB.DeleteUpcomingBillReminders(bill.ID)
+}
+
+// Snooze reminds the bill again later, its pending reminders are cancelled
+func (r *Bills) Snooze(bill model.Bill, now time.Time) (model.Bill, error) {
+	bill.RemindAt = now.Add(BillSnooze)
+	if err := r.DB.UpdateBill(&bill); err != nil {
+		return model.Bill{}, err
+	}
+	return bill, r.DB.DeleteUpcomingBillReminders(bill.ID)
+}
+
+// billRemindAt returns when the user is reminded of the next payment of a bill, right away if it's already time
+func billRemindAt(user model.User, bill model.Bill) time.Time {
+	remindAt := bill.ReminderTime(user.Location())
+	if now := time.Now(); remindAt.Before(now) {
+		return now.UTC()
+	}
+	return remindAt.UTC()
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"cashout/internal/model"
+	"errors"
+	"strings"
+	"time"
+)
+
+// MaxInviteUses is how many users an invite code can let in
+const MaxInviteUses = 100
+
+var ErrInvalidInviteUses = errors.New("invalid number of uses")
+
+type Invites struct {
+	Repository
+}
+
+// Create generates an invite code for a number of users, valid for DefaultInviteValidity
+func (r *Invites) Create(createdBy int64, maxUses int, now time.Time) (*model.InviteCode, error) {
+	if maxUses < 1 || maxUses > MaxInviteUses {
+		return nil, ErrInvalidInviteUses
+	}
+
+	code, err := generateRandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(model.DefaultInviteValidity).UTC()
+	invite := &model.InviteCode{
+		Code:      strings.ToUpper(code),
+		MaxUses:   maxUses,
+		CreatedBy: createdBy,
+		ExpiresAt: &expiresAt,
+	}
+	if err := r.DB.CreateInviteCode(invite); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// Active retrieves the invite codes that can still be redeemed
+func (r *Invites) Active(now time.Time) ([]model.InviteCode, error) {
+	return r.DB.GetActiveInviteCodes(now)
+}
+
+// Revoke stops an invite code from being redeemed, false if it already was
+func (r *Invites) Revoke(id int64, now time.Time) (bool, error) {
+	return r.DB.RevokeInviteCode(id, now.UTC())
+}
+
+// Redeem allows the user with an invite code, false if the code is unknown, expired, revoked or used up
+func (r *Invites) Redeem(code string, tgID int64, username string, now time.Time) (bool, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return false, nil
+	}
+	return r.DB.RedeemInviteCode(code, tgID, model.NormalizeUsername(username), now.UTC())
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"cashout/internal/db"
 	"cashout/internal/model"
+	"context"
 	"time"
 )
 
@@ -13,20 +15,52 @@ func (r *Reminders) CreateReminder(reminder *model.Reminder) error {
 	return r.DB.CreateReminder(reminder)
 }
 
-func (r *Reminders) GetPendingReminders(reminderType model.ReminderType, scheduledBefore time.Time) ([]model.Reminder, error) {
-	return r.DB.GetPendingReminders(reminderType, scheduledBefore)
+// ClaimDue takes a batch of reminders of a type to send now, including retries and those left by a crashed sender.
+// Instances claiming at the same time get different reminders.
+func (r *Reminders) ClaimDue(reminderType model.ReminderType, now time.Time, limit int) ([]model.Reminder, error) {
+	return r.DB.ClaimDueReminders(reminderType, now, model.ReminderLease, limit)
 }
 
-func (r *Reminders) UpdateReminderStatusTransaction(reminderID int64, status model.ReminderStatus, errorMsg *string) error {
-	return r.DB.UpdateReminderStatusTransaction(reminderID, status, errorMsg)
+// MarkSent records that the reminder was delivered
+func (r *Reminders) MarkSent(reminder model.Reminder, now time.Time) error {
+	return r.DB.MarkReminderSent(reminder.ID, now)
 }
 
-func (r *Reminders) CreateOrUpdateWeeklyReminder(tgID int64, scheduledFor time.Time) error {
-	return r.DB.CreateOrUpdateWeeklyReminder(tgID, scheduledFor)
+// MarkFailed records a failed attempt to send the reminder: it's retried later with backoff,
+// or given up on when it ran out of attempts. It tells whether it's retried.
+func (r *Reminders) MarkFailed(reminder model.Reminder, now time.Time, sendErr error) (bool, error) {
+	nextAttemptAt, ok := reminder.NextRetry(now)
+	if !ok {
+		return false, r.DB.MarkReminderDead(reminder.ID, now, sendErr.Error())
+	}
+	return true, r.DB.MarkReminderFailed(reminder.ID, nextAttemptAt, sendErr.Error())
 }
 
-func (r *Reminders) CreateOrUpdateMonthlyReminder(tgID int64, scheduledFor time.Time) error {
-	return r.DB.CreateOrUpdateMonthlyReminder(tgID, scheduledFor)
+// Delay sends the reminder again later, without counting the attempt, e.g. when Telegram asks to slow down
+func (r *Reminders) Delay(reminder model.Reminder, nextAttemptAt time.Time, sendErr error) error {
+	return r.DB.DelayReminder(reminder.ID, nextAttemptAt, sendErr.Error())
+}
+
+// MarkDead gives up on the reminder, e.g. because the user blocked the bot
+func (r *Reminders) MarkDead(reminder model.Reminder, now time.Time, sendErr error) error {
+	return r.DB.MarkReminderDead(reminder.ID, now, sendErr.Error())
+}
+
+// ScheduleReminder creates a pending reminder for a user, unless it was already sent
+func (r *Reminders) ScheduleReminder(reminder model.Reminder) error {
+	return r.DB.UpsertReminder(reminder)
+}
+
+// CancelUpcomingRecaps deletes the pending recap reminders of the user after they changed their recap settings
+// or time zone. The scheduler creates them again with the new schedule.
+func (r *Reminders) CancelUpcomingRecaps(tgID int64, now time.Time) error {
+	return r.DB.DeleteUpcomingReminders(tgID, now, model.RecapReminderTypes)
+}
+
+// TryLockScheduling makes this instance the one scheduling reminders and running the periodic checks,
+// false if another instance already is
+func (r *Reminders) TryLockScheduling(ctx context.Context) (*db.AdvisoryLock, bool, error) {
+	return r.DB.TryAdvisoryLock(ctx, db.SchedulerLockKey)
 }
 
 func (r *Reminders) GetAllActiveUsers() ([]model.User, error) {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"cashout/internal/db"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
@@ -10,3 +11,14 @@ type Repository struct {
 	DB     *db.DB
 	Logger *logrus.Logger
 }
+
+// truncateRunes shortens a text to a number of characters, never cutting one in half as the database rejects
+// invalid UTF-8
+func truncateRunes(text string, length int) string {
+	text = strings.ToValidUTF8(text, "")
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length])
+}
@@ -1,16 +1,22 @@
 package repository
 
 import (
+	"cashout/internal/db"
 	"cashout/internal/model"
+	"cashout/internal/utils"
+	"errors"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Transactions struct {
 	Repository
 }
 
-func (r *Transactions) Add(transaction model.Transaction) error {
-	return r.DB.CreateTransaction(&transaction)
+// Add stores the transaction and sets its ID
+func (r *Transactions) Add(transaction *model.Transaction) error {
+	return r.DB.CreateTransaction(transaction)
 }
 
 func (r *Transactions) GetByID(id int64) (model.Transaction, error) {
@@ -51,6 +57,18 @@ func (r *Transactions) GetMonthCategorizedTotals(tgID int64, year int, month int
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, -1) // Last day of the month
 
+	return r.GetCategorizedTotalsByDateRange(tgID, startDate, endDate)
+}
+
+// GetMonthCategorizedTotalsUpTo returns the transaction totals for each category for the first days of a month,
+// all of them when days is 0, to compare it with a month in progress
+func (r *Transactions) GetMonthCategorizedTotalsUpTo(tgID int64, year int, month int, days int) (map[model.TransactionType]map[model.TransactionCategory]float64, error) {
+	startDate, endDate := utils.MonthPeriod(year, time.Month(month), days)
+	return r.GetCategorizedTotalsByDateRange(tgID, startDate, endDate)
+}
+
+// GetCategorizedTotalsByDateRange returns the transaction totals for each category within a date range
+func (r *Transactions) GetCategorizedTotalsByDateRange(tgID int64, startDate, endDate time.Time) (map[model.TransactionType]map[model.TransactionCategory]float64, error) {
 	// Get expense categories
 	expenseTotals, err := r.DB.GetUserTransactionsByCategory(tgID, startDate, endDate, model.TypeExpense)
 	if err != nil {
@@ -70,28 +88,47 @@ func (r *Transactions) GetMonthCategorizedTotals(tgID int64, year int, month int
 	}, nil
 }
 
+// GetMonthForecast returns the end-of-month projection for the month of now, based on the last utils.ForecastHistoryMonths months
+func (r *Transactions) GetMonthForecast(tgID int64, now time.Time) (utils.Forecast, error) {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	current, err := r.DB.GetUserTransactionsByDateRange(tgID, monthStart, now)
+	if err != nil {
+		return utils.Forecast{}, err
+	}
+
+	history, err := r.DB.GetUserTransactionsByDateRange(tgID, monthStart.AddDate(0, -utils.ForecastHistoryMonths, 0), monthStart.Add(-time.Nanosecond))
+	if err != nil {
+		return utils.Forecast{}, err
+	}
+
+	return utils.ForecastMonth(now, current, history), nil
+}
+
 // GetYearCategorizedTotals returns the transaction totals for each category for a specific year
 func (r *Transactions) GetYearCategorizedTotals(tgID int64, year int) (map[model.TransactionType]map[model.TransactionCategory]float64, error) {
 	startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
 	endDate := time.Date(year, 12, 31, 23, 59, 59, 0, time.UTC)
 
-	// Get expense categories
-	expenseTotals, err := r.DB.GetUserTransactionsByCategory(tgID, startDate, endDate, model.TypeExpense)
-	if err != nil {
-		return nil, err
-	}
+	return r.GetCategorizedTotalsByDateRange(tgID, startDate, endDate)
+}
 
-	// Get income categories
-	incomeTotals, err := r.DB.GetUserTransactionsByCategory(tgID, startDate, endDate, model.TypeIncome)
-	if err != nil {
-		return nil, err
+// GetYearTotals gets the monthly and category totals of a year, with the monthly totals of the previous one
+func (r *Transactions) GetYearTotals(tgID int64, year int) (utils.YearTotals, error) {
+	var totals utils.YearTotals
+	var err error
+
+	if totals.Monthly, err = r.GetMonthlyTotalsInYear(tgID, year); err != nil {
+		return totals, err
+	}
+	if totals.PreviousMonthly, err = r.GetMonthlyTotalsInYear(tgID, year-1); err != nil {
+		return totals, err
+	}
+	if totals.Categories, err = r.GetYearCategorizedTotals(tgID, year); err != nil {
+		return totals, err
 	}
 
-	// Return both in a map
-	return map[model.TransactionType]map[model.TransactionCategory]float64{
-		model.TypeExpense: expenseTotals,
-		model.TypeIncome:  incomeTotals,
-	}, nil
+	return totals, nil
 }
 
 // GetUserTransactions retrieves all transactions for a user (no pagination)
@@ -108,3 +145,49 @@ func (r *Transactions) GetUserTransactionsByDateRange(tgID int64, startDate, end
 func (r *Transactions) SearchUserTransactions(tgID int64, searchQuery string, category string, offset, limit int) ([]model.Transaction, int64, error) {
 	return r.DB.SearchUserTransactions(tgID, searchQuery, category, offset, limit)
 }
+
+// FindTransactions retrieves a page of transactions matching the query after the cursor, and whether more follow
+func (r *Transactions) FindTransactions(query db.TransactionQuery, cursor *db.TransactionCursor, limit int) ([]model.Transaction, bool, error) {
+	return r.DB.FindTransactions(query, cursor, limit)
+}
+
+// FindAllTransactions retrieves every transaction matching the query
+func (r *Transactions) FindAllTransactions(query db.TransactionQuery) ([]model.Transaction, error) {
+	return r.DB.FindAllTransactions(query)
+}
+
+// GetMonthlyCategoryTotals returns the totals by month, type and category within a date range
+func (r *Transactions) GetMonthlyCategoryTotals(tgID int64, startDate, endDate time.Time) ([]db.MonthlyCategoryTotal, error) {
+	return r.DB.GetMonthlyCategoryTotals(tgID, startDate, endDate)
+}
+
+// GetCategoryTotals returns the totals and counts by type and category within a date range
+func (r *Transactions) GetCategoryTotals(tgID int64, startDate, endDate time.Time) ([]db.CategoryTotal, error) {
+	return r.DB.GetCategoryTotals(tgID, startDate, endDate)
+}
+
+// GetTopMerchants returns the descriptions with the highest expenses within a date range
+func (r *Transactions) GetTopMerchants(tgID int64, startDate, endDate time.Time, limit int) ([]db.MerchantTotal, error) {
+	return r.DB.GetTopMerchants(tgID, startDate, endDate, limit)
+}
+
+// GetLastPayday returns the date of the user's most recent salary, if any
+func (r *Transactions) GetLastPayday(tgID int64) (*time.Time, error) {
+	transaction, err := r.DB.GetLatestUserTransactionByCategory(tgID, model.CategorySalary)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &transaction.Date, nil
+}
+
+// HasTransactionsCreatedSince tells if the user recorded any transaction after a time
+func (r *Transactions) HasTransactionsCreatedSince(tgID int64, since time.Time) (bool, error) {
+	count, err := r.DB.CountUserTransactionsCreatedSince(tgID, since)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -4,6 +4,7 @@ import (
 	"cashout/internal/model"
 	"errors"
 	"strings"
+	"time"
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 	"gorm.io/gorm"
@@ -27,15 +28,69 @@ func (r *Users) UpsertWithContext(user gotgbot.User, session model.UserSession)
 		TgUsername:  user.Username,
 		TgFirstname: user.FirstName,
 		TgLastname:  user.LastName,
+		Timezone:    model.DefaultTimezone,
+		// Recaps are on by default
+		WeeklyRecap:  true,
+		MonthlyRecap: true,
+		YearlyRecap:  true,
+		RecapWeekday: model.DefaultRecapWeekday,
+		RecapHour:    model.DefaultRecapHour,
+		NudgeHour:    model.DefaultNudgeHour,
 	})
 }
 
+// RefreshProfile keeps the username and names of the user as Telegram has them now. Writing to the bot unblocks
+// it, the scheduler creates their reminders again.
+func (u *Users) RefreshProfile(user *model.User, from gotgbot.User) error {
+	if user.TgUsername == from.Username && user.TgFirstname == from.FirstName && user.TgLastname == from.LastName && user.BotBlockedAt == nil {
+		return nil
+	}
+	if err := u.DB.SetUserProfile(user.TgID, from.Username, from.FirstName, from.LastName); err != nil {
+		return err
+	}
+	user.TgUsername = from.Username
+	user.TgFirstname = from.FirstName
+	user.TgLastname = from.LastName
+	user.BotBlockedAt = nil
+	return nil
+}
+
+// ResetNudges starts the nudges of the user again from one a day
+func (u *Users) ResetNudges(tgID int64) error {
+	return u.DB.ResetUserNudges(tgID)
+}
+
+// Update saves the user, except for the nudge and blocked columns owned by the scheduler
 func (u *Users) Update(user *model.User) error {
 	return u.DB.SetUser(user)
 }
 
-// GetByUsername retrieves a user by their Telegram username
+// SetBotBlocked records that the bot can't write to the user anymore, who gets no reminders until they write to it.
+// Their upcoming reminders are cancelled.
+func (u *Users) SetBotBlocked(tgID int64, now time.Time) error {
+	if err := u.DB.SetUserBotBlocked(tgID, &now); err != nil {
+		return err
+	}
+	// Bill reminders are created again once they write to the bot, broadcasts are just missed
+	return u.DB.DeleteUpcomingReminders(tgID, now, []model.ReminderType{
+		model.ReminderTypeWeeklyRecap,
+		model.ReminderTypeMonthlyRecap,
+		model.ReminderTypeYearlyRecap,
+		model.ReminderTypeBillDue,
+		model.ReminderTypeBroadcast,
+	})
+}
+
+// SetNudgeSent records that the user was nudged to log, after ignoring the given number of nudges in a row
+func (u *Users) SetNudgeSent(tgID int64, nudgedAt time.Time, ignored int) error {
+	return u.DB.SetUserNudge(tgID, nudgedAt, ignored)
+}
+
+// GetByUsername retrieves a user by their current Telegram username, false for an empty one
 func (r *Users) GetByUsername(username string) (model.User, bool, error) {
+	if model.NormalizeUsername(username) == "" {
+		return model.User{}, false, nil
+	}
 	user, err := r.DB.GetUserByUsername(username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -46,6 +101,18 @@ func (r *Users) GetByUsername(username string) (model.User, bool, error) {
 	return *user, true, nil
 }
 
+// FindByTgID retrieves a user by their Telegram ID, false if they never wrote to the bot
+func (r *Users) FindByTgID(tgID int64) (model.User, bool, error) {
+	user, err := r.DB.GetUser(tgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.User{}, false, nil
+		}
+		return model.User{}, false, err
+	}
+	return *user, true, nil
+}
+
 // GetByTgID retrieves a user by their Telegram ID
 func (r *Users) GetByTgID(tgID int64) (model.User, error) {
 	user, err := r.DB.GetUser(tgID)
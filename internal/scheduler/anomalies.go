@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// checkAnomalies looks for unusual spending of all active users: transactions recorded since the previous check
+// (e.g. from the web dashboard) and categories spiking this week. Anomalies already notified are skipped.
+func (s *Scheduler) checkAnomalies() error {
+	users, err := s.repositories.Reminders.GetAllActiveUsers()
+	if err != nil {
+		return fmt.Errorf("failed to get active users: %w", err)
+	}
+
+	now := time.Now().UTC()
+	// Overlap with the previous check, duplicates are filtered out when saving
+	since := now.Add(-2 * ANOMALY_CHECK_MIN * time.Minute)
+
+	for _, user := range users {
+		today := user.Now()
+		history, err := s.repositories.Transactions.GetUserTransactionsByDateRange(user.TgID, today.AddDate(0, 0, -utils.AnomalyHistoryDays), today)
+		if err != nil {
+			s.logger.Errorf("Failed to get transactions for user %d: %v", user.TgID, err)
+			continue
+		}
+
+		anomalies := utils.DetectCategorySpikes(today, history)
+		for i := range anomalies {
+			anomalies[i].TgID = user.TgID
+		}
+		for _, t := range history {
+			if t.CreatedAt.After(since) {
+				anomalies = append(anomalies, utils.DetectTransactionAnomalies(t, history)...)
+			}
+		}
+
+		for _, anomaly := range anomalies {
+			if err := s.notifyAnomaly(anomaly); err != nil {
+				s.logger.Errorf("Failed to notify anomaly %s to user %d: %v", anomaly.Key, user.TgID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// notifyAnomaly records the anomaly and, unless the user already heard about it, sends them a notice
+func (s *Scheduler) notifyAnomaly(anomaly model.Anomaly) error {
+	created, err := s.repositories.Anomalies.Create(&anomaly)
+	if err != nil {
+		return fmt.Errorf("failed to save anomaly: %w", err)
+	}
+	if !created {
+		return nil
+	}
+
+	_, err = s.bot.SendMessage(anomaly.TgID, utils.FormatAnomaly(anomaly), &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{
+			InlineKeyboard: utils.AnomalyKeyboard(anomaly),
+		},
+	})
+	return err
+}
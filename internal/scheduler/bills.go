@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"html"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+func init() {
+	RegisterReminder(ReminderHandler{
+		Type:     model.ReminderTypeBillDue,
+		Name:     "bill reminder",
+		Schedule: (*Scheduler).billReminders,
+		Message:  (*Scheduler).billReminder,
+	})
+}
+
+// billReminders returns the next reminder of each bill of a user, days before it's due or when it was snoozed to.
+// Bills left unpaid long after their due date move on to the next one, so every due date is reminded.
+func (s *Scheduler) billReminders(user model.User, now time.Time) ([]model.Reminder, error) {
+	bills, err := s.repositories.Bills.List(user.TgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bills: %w", err)
+	}
+
+	reminders := make([]model.Reminder, len(bills))
+	for i := range bills {
+		bill, err := s.repositories.Bills.SkipMissed(user, bills[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to move bill %d to its next due date: %w", bills[i].ID, err)
+		}
+
+		reminders[i] = model.Reminder{
+			TgID:         user.TgID,
+			Type:         model.ReminderTypeBillDue,
+			BillID:       &bill.ID,
+			ScheduledFor: bill.RemindAt,
+		}
+	}
+	return reminders, nil
+}
+
+// billReminder generates the reminder of a bill about to be due, with buttons to pay or snooze it
+func (s *Scheduler) billReminder(user model.User, reminder model.Reminder) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	if reminder.BillID == nil {
+		return "", nil, fmt.Errorf("bill reminder %d has no bill", reminder.ID)
+	}
+
+	bill, err := s.repositories.Bills.Get(*reminder.BillID, user.TgID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get bill: %w", err)
+	}
+
+	message := fmt.Sprintf("🧾 <b>%s, a bill is coming up</b>\n\n<b>%s</b> %s\n%s\n\nLet me know when it's paid and I'll record the expense.",
+		user.Name, html.EscapeString(bill.Name), utils.FormatBillAmount(bill), utils.FormatBillDue(bill, user.Today()))
+
+	keyboard := [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: "✅ Mark paid", CallbackData: utils.BillPaidCallback(bill)},
+			{Text: "⏰ Remind me tomorrow", CallbackData: fmt.Sprintf("bills.snooze.%d", bill.ID)},
+		},
+	}
+
+	return message, keyboard, nil
+}
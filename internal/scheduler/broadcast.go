@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// broadcastInterval keeps announcements well under Telegram's limit of 30 messages per second
+const broadcastInterval = 50 * time.Millisecond
+
+func init() {
+	// Queued for all users by the admins with /admin broadcast
+	RegisterReminder(ReminderHandler{
+		Type:     model.ReminderTypeBroadcast,
+		Name:     "broadcast",
+		Message:  (*Scheduler).broadcast,
+		Interval: broadcastInterval,
+	})
+}
+
+// broadcast generates the announcement a broadcast reminder delivers
+func (s *Scheduler) broadcast(user model.User, reminder model.Reminder) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	if reminder.BroadcastID == nil {
+		return "", nil, fmt.Errorf("broadcast reminder %d has no announcement", reminder.ID)
+	}
+
+	broadcast, err := s.repositories.Admin.GetBroadcast(*reminder.BroadcastID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get broadcast: %w", err)
+	}
+
+	return utils.FormatBroadcast(broadcast.Message), nil, nil
+}
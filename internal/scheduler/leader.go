@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// leaderTimeout bounds the database calls of the leader election
+const leaderTimeout = 10 * time.Second
+
+// refreshLeadership keeps this instance the leader, or makes it the leader when no instance is:
+// the leader holds the scheduler lock in Postgres and is the only one scheduling reminders and
+// running the periodic checks. The lock is released if the leader crashes, letting another take over.
+func (s *Scheduler) refreshLeadership() {
+	s.leaderMu.Lock()
+	defer s.leaderMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaderTimeout)
+	defer cancel()
+
+	if s.leaderLock != nil {
+		if s.leaderLock.Held(ctx) {
+			return
+		}
+
+		s.logger.Warn("Lost the scheduler lock, another instance may take over")
+		_ = s.leaderLock.Release(ctx)
+		s.leaderLock = nil
+	}
+
+	lock, ok, err := s.repositories.Reminders.TryLockScheduling(ctx)
+	if err != nil {
+		s.logger.Errorf("Failed to take the scheduler lock: %v", err)
+		return
+	}
+	if ok {
+		s.leaderLock = lock
+		s.logger.Info("This instance is now scheduling reminders")
+	}
+}
+
+// isLeader tells if this instance holds the scheduler lock
+func (s *Scheduler) isLeader() bool {
+	s.leaderMu.Lock()
+	defer s.leaderMu.Unlock()
+
+	return s.leaderLock != nil
+}
+
+// resignLeadership releases the scheduler lock, if held, for another instance to take over
+func (s *Scheduler) resignLeadership() {
+	s.leaderMu.Lock()
+	defer s.leaderMu.Unlock()
+
+	if s.leaderLock == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaderTimeout)
+	defer cancel()
+
+	if err := s.leaderLock.Release(ctx); err != nil {
+		s.logger.Errorf("Failed to release the scheduler lock: %v", err)
+	}
+	s.leaderLock = nil
+}
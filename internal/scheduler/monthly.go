@@ -11,116 +11,19 @@ import (
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 )
 
-func getNextFirstDayOfMonth() time.Time {
-	now := time.Now()
-	// Get first day of next month
-	firstOfNextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
-
-	return firstOfNextMonth
-}
-
-// createMonthlyReminders creates reminder records for all active users
-func (s *Scheduler) createMonthlyReminders() error {
-	s.logger.Info("Creating monthly reminders...")
-
-	// Get all active users
-	users, err := s.repositories.Reminders.GetAllActiveUsers()
-	if err != nil {
-		return fmt.Errorf("failed to get active users: %w", err)
-	}
-
-	// Schedule for 6:00 GMT on the first day of next month
-	firstDay := getNextFirstDayOfMonth()
-	scheduledFor := time.Date(firstDay.Year(), firstDay.Month(), firstDay.Day(), 6, 0, 0, 0, time.UTC)
-
-	createdCount := 0
-	for _, user := range users {
-		err := s.repositories.Reminders.CreateOrUpdateMonthlyReminder(user.TgID, scheduledFor)
-		if err != nil {
-			s.logger.Errorf("Failed to create monthly reminder for user %d: %v", user.TgID, err)
-			continue
-		}
-		createdCount++
-	}
-
-	s.logger.Infof("Created %d monthly reminders for %d active users", createdCount, len(users))
-	return nil
-}
-
-// processMonthlyReminders processes all pending monthly reminders
-func (s *Scheduler) processMonthlyReminders() error {
-	// Get all pending reminders that should be sent now
-	reminders, err := s.repositories.Reminders.GetPendingReminders(
-		model.ReminderTypeMonthlyRecap,
-		time.Now().UTC(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to get pending monthly reminders: %w", err)
-	}
-
-	if len(reminders) == 0 {
-		return nil
-	}
-
-	s.logger.Infof("Processing %d pending monthly reminders", len(reminders))
-
-	for _, reminder := range reminders {
-		// Update status to processing (with transaction to prevent double processing)
-		err := s.repositories.Reminders.UpdateReminderStatusTransaction(
-			reminder.ID,
-			model.ReminderStatusProcessing,
-			nil,
-		)
-		if err != nil {
-			s.logger.Errorf("Failed to update monthly reminder %d to processing: %v", reminder.ID, err)
-			continue
-		}
-
-		// Send the monthly recap
-		err = s.sendMonthlyRecap(reminder.TgID)
-
-		if err != nil {
-			errMsg := err.Error()
-			s.logger.Errorf("Failed to send monthly recap for user %d: %v", reminder.TgID, err)
-
-			err = s.repositories.Reminders.UpdateReminderStatusTransaction(
-				reminder.ID,
-				model.ReminderStatusFailed,
-				&errMsg,
-			)
-			if err != nil {
-				s.logger.Errorf("Failed to update monthly reminder %d to failed: %v", reminder.ID, err)
-			}
-
-		} else {
-			s.logger.Infof("Successfully sent monthly recap to user %d", reminder.TgID)
-			err = s.repositories.Reminders.UpdateReminderStatusTransaction(
-				reminder.ID,
-				model.ReminderStatusSent,
-				nil,
-			)
-			if err != nil {
-				s.logger.Errorf("Failed to update monthly reminder %d to sent: %v", reminder.ID, err)
-			}
-
-		}
-	}
-
-	return nil
+func init() {
+	RegisterReminder(ReminderHandler{
+		Type:     model.ReminderTypeMonthlyRecap,
+		Name:     "monthly recap",
+		Schedule: recapSchedule(model.ReminderTypeMonthlyRecap),
+		Message:  recapMessage((*Scheduler).monthlyRecap),
+	})
 }
 
-// sendMonthlyRecap sends the previous month's recap to a user
-func (s *Scheduler) sendMonthlyRecap(tgID int64) error {
-	// Get user data
-	user, err := s.repositories.Users.GetByTgID(tgID)
-	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
-	}
-
-	// Calculate previous month
-	now := time.Now().UTC()
-	// Go to first day of current month
-	firstOfCurrentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+// monthlyRecap generates the previous month's recap of a user
+func (s *Scheduler) monthlyRecap(user model.User, day time.Time) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	// Go to first day of the month the recap is sent in
+	firstOfCurrentMonth := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
 	// Go back one day to get last day of previous month
 	lastOfPrevMonth := firstOfCurrentMonth.AddDate(0, 0, -1)
 	// Get year and month of previous month
@@ -130,24 +33,16 @@ func (s *Scheduler) sendMonthlyRecap(tgID int64) error {
 	// Get monthly totals
 	totals, err := s.repositories.Transactions.GetMonthlyTotalsInYear(user.TgID, prevYear)
 	if err != nil {
-		return fmt.Errorf("failed to get monthly totals: %w", err)
+		return "", nil, fmt.Errorf("failed to get monthly totals: %w", err)
 	}
 
 	// Get category breakdown
 	categoryTotals, err := s.repositories.Transactions.GetMonthCategorizedTotals(user.TgID, prevYear, prevMonth)
 	if err != nil {
-		return fmt.Errorf("failed to get category totals: %w", err)
+		return "", nil, fmt.Errorf("failed to get category totals: %w", err)
 	}
 
-	// Generate the recap message
-	message := s.generateMonthlyRecapMessage(user, totals, categoryTotals, prevYear, prevMonth)
-
-	// Send the message
-	_, err = s.bot.SendMessage(tgID, message, &gotgbot.SendMessageOpts{
-		ParseMode: "HTML",
-	})
-
-	return err
+	return s.generateMonthlyRecapMessage(user, totals, categoryTotals, prevYear, prevMonth), nil, nil
 }
 
 // generateMonthlyRecapMessage generates the monthly recap message
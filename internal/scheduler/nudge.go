@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"cashout/internal/utils"
+	"fmt"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// sendDailyNudges reminds the users who turned the daily nudge on to log, when they recorded nothing today.
+// Users who keep ignoring it are nudged less and less often (see model.User.NudgeDue).
+func (s *Scheduler) sendDailyNudges() error {
+	users, err := s.repositories.Reminders.GetAllActiveUsers()
+	if err != nil {
+		return fmt.Errorf("failed to get active users: %w", err)
+	}
+
+	now := time.Now()
+	sentCount := 0
+	for _, user := range users {
+		if !user.NudgeDue(now) {
+			continue
+		}
+
+		today := user.Today()
+		transactions, err := s.repositories.Transactions.GetUserTransactionsByDateRange(user.TgID, today, today)
+		if err != nil {
+			s.logger.Errorf("Failed to get today's transactions for user %d: %v", user.TgID, err)
+			continue
+		}
+		if len(transactions) > 0 {
+			continue
+		}
+
+		// The previous nudge was ignored if nothing was recorded since
+		ignored := 0
+		if user.LastNudgeAt != nil {
+			logged, err := s.repositories.Transactions.HasTransactionsCreatedSince(user.TgID, *user.LastNudgeAt)
+			if err != nil {
+				s.logger.Errorf("Failed to check transactions of user %d: %v", user.TgID, err)
+				continue
+			}
+			if !logged {
+				ignored = user.NudgesIgnored + 1
+			}
+		}
+
+		if err := s.sendDailyNudge(user.TgID, user.Name); err != nil {
+			// Telegram asks to slow down: the rest of the users are nudged on the next run
+			if _, ok := utils.TelegramRetryAfter(err); ok {
+				s.logger.Warnf("Rate limited sending daily nudges: %v", err)
+				break
+			}
+			if utils.IsBotBlocked(err) {
+				s.logger.Warnf("User %d blocked the bot, disabling their reminders: %v", user.TgID, err)
+				if err := s.repositories.Users.SetBotBlocked(user.TgID, now); err != nil {
+					s.logger.Errorf("Failed to disable reminders of user %d: %v", user.TgID, err)
+				}
+				continue
+			}
+			s.logger.Errorf("Failed to send daily nudge to user %d: %v", user.TgID, err)
+			continue
+		}
+
+		if err := s.repositories.Users.SetNudgeSent(user.TgID, now, ignored); err != nil {
+			s.logger.Errorf("Failed to save daily nudge of user %d: %v", user.TgID, err)
+		}
+		sentCount++
+	}
+
+	if sentCount > 0 {
+		s.logger.Infof("Sent %d daily nudges", sentCount)
+	}
+	return nil
+}
+
+// sendDailyNudge sends the prompt to log with the add transaction keyboard
+func (s *Scheduler) sendDailyNudge(tgID int64, name string) error {
+	message := fmt.Sprintf("✍️ <b>Anything to log today, %s?</b>\n\n"+
+		"You haven't recorded anything today. Add what you spent or earned while you still remember it.", name)
+
+	_, err := s.bot.SendMessage(tgID, message, &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+		ReplyMarkup: gotgbot.InlineKeyboardMarkup{
+			InlineKeyboard: [][]gotgbot.InlineKeyboardButton{
+				{
+					{Text: "💰 Add Income", CallbackData: "transactions.new.income"},
+					{Text: "💸 Add Expense", CallbackData: "transactions.new.expense"},
+				},
+				{
+					{Text: "🔕 Turn off", CallbackData: "settings.toggle.nudge"},
+				},
+			},
+		},
+	})
+
+	return err
+}
@@ -0,0 +1,204 @@
+package scheduler
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// ReminderHandler defines a type of reminder: when it's due for a user and the message it sends.
+// Adding a type of reminder is registering its handler with RegisterReminder.
+type ReminderHandler struct {
+	Type model.ReminderType
+	// Name of the reminder in the logs, e.g. "weekly recap"
+	Name string
+	// Schedule returns the next reminders due for the user after now, none if they don't receive it.
+	// It's nil for reminders created otherwise, e.g. broadcasts.
+	Schedule func(s *Scheduler, user model.User, now time.Time) ([]model.Reminder, error)
+	// Message generates the message of a reminder for the user, with its inline keyboard (if any)
+	Message func(s *Scheduler, user model.User, reminder model.Reminder) (string, [][]gotgbot.InlineKeyboardButton, error)
+	// Interval is the pause between messages, for reminders sent to many users at once
+	Interval time.Duration
+}
+
+// Available reminders - this is populated by the file of each reminder type
+var reminderHandlers []ReminderHandler
+
+// RegisterReminder adds a type of reminder to those the scheduler creates and sends
+func RegisterReminder(handler ReminderHandler) {
+	reminderHandlers = append(reminderHandlers, handler)
+}
+
+// recapSchedule returns when the next recap of a type is due, on the user's day and hour, if they receive it
+func recapSchedule(reminderType model.ReminderType) func(s *Scheduler, user model.User, now time.Time) ([]model.Reminder, error) {
+	return func(s *Scheduler, user model.User, now time.Time) ([]model.Reminder, error) {
+		if !user.RecapEnabled(reminderType) {
+			return nil, nil
+		}
+		return []model.Reminder{{
+			TgID:         user.TgID,
+			Type:         reminderType,
+			ScheduledFor: user.NextRecap(reminderType, now),
+		}}, nil
+	}
+}
+
+// recapMessage adapts the message of a recap, which covers the period before the day it was scheduled for in
+// the user's time zone. A recap sent late, e.g. retried after a failure, still reports the same period.
+func recapMessage(message func(s *Scheduler, user model.User, day time.Time) (string, [][]gotgbot.InlineKeyboardButton, error)) func(s *Scheduler, user model.User, reminder model.Reminder) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	return func(s *Scheduler, user model.User, reminder model.Reminder) (string, [][]gotgbot.InlineKeyboardButton, error) {
+		return message(s, user, model.DateOf(reminder.ScheduledFor.In(user.Location())))
+	}
+}
+
+// createReminders creates the next reminder of each type for all active users. Only the instance holding
+// the scheduler lock runs it.
+// Reminders already created are left as they are, and those cancelled by a change of the user's
+// settings are created again with the new schedule.
+func (s *Scheduler) createReminders() error {
+	// Get all active users
+	users, err := s.repositories.Reminders.GetAllActiveUsers()
+	if err != nil {
+		return fmt.Errorf("failed to get active users: %w", err)
+	}
+
+	now := time.Now()
+	for _, user := range users {
+		for _, handler := range reminderHandlers {
+			if handler.Schedule == nil {
+				continue
+			}
+
+			reminders, err := handler.Schedule(s, user, now)
+			if err != nil {
+				s.logger.Errorf("Failed to schedule %s reminders for user %d: %v", handler.Name, user.TgID, err)
+				continue
+			}
+
+			for _, reminder := range reminders {
+				reminder.ScheduledFor = reminder.ScheduledFor.UTC()
+				if err := s.repositories.Reminders.ScheduleReminder(reminder); err != nil {
+					s.logger.Errorf("Failed to create %s reminder for user %d: %v", handler.Name, user.TgID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// processReminders sends the reminders of every type that are due
+func (s *Scheduler) processReminders() error {
+	for _, handler := range reminderHandlers {
+		if err := s.processRemindersOfType(handler); err != nil {
+			s.logger.Errorf("Failed to process %s reminders: %v", handler.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// processRemindersOfType sends the reminders of a type that are due, claiming them in batches so that
+// instances running at the same time send different ones. Failed ones are retried with backoff until they
+// run out of attempts, and those of users who blocked the bot are given up on.
+func (s *Scheduler) processRemindersOfType(handler ReminderHandler) error {
+	for {
+		reminders, err := s.repositories.Reminders.ClaimDue(handler.Type, time.Now().UTC(), REMINDER_BATCH_SIZE)
+		if err != nil {
+			return fmt.Errorf("failed to claim due reminders: %w", err)
+		}
+
+		if len(reminders) == 0 {
+			return nil
+		}
+
+		s.logger.Infof("Processing %d due %s reminders", len(reminders), handler.Name)
+
+		if rateLimited := s.sendReminders(handler, reminders); rateLimited || len(reminders) < REMINDER_BATCH_SIZE {
+			return nil
+		}
+	}
+}
+
+// sendReminders sends a batch of claimed reminders and records the outcome of each.
+// It tells if Telegram asked to slow down, in which case the rest of the batch is put back for later.
+func (s *Scheduler) sendReminders(handler ReminderHandler, reminders []model.Reminder) bool {
+	for i, reminder := range reminders {
+		if i > 0 && handler.Interval > 0 {
+			time.Sleep(handler.Interval)
+		}
+		now := time.Now().UTC()
+
+		sendErr := s.sendReminder(handler, reminder)
+		if sendErr == nil {
+			s.logger.Infof("Successfully sent %s to user %d", handler.Name, reminder.TgID)
+			if err := s.repositories.Reminders.MarkSent(reminder, now); err != nil {
+				s.logger.Errorf("Failed to update reminder %d to sent: %v", reminder.ID, err)
+			}
+			continue
+		}
+
+		// Telegram asks to slow down: this reminder and the rest of the batch wait
+		if retryAfter, ok := utils.TelegramRetryAfter(sendErr); ok {
+			s.logger.Warnf("Rate limited sending %s to user %d, retrying in %v", handler.Name, reminder.TgID, retryAfter)
+			for _, delayed := range reminders[i:] {
+				if err := s.repositories.Reminders.Delay(delayed, now.Add(retryAfter), sendErr); err != nil {
+					s.logger.Errorf("Failed to delay reminder %d: %v", delayed.ID, err)
+				}
+			}
+			return true
+		}
+
+		if utils.IsBotBlocked(sendErr) {
+			s.logger.Warnf("User %d blocked the bot, disabling their reminders: %v", reminder.TgID, sendErr)
+			if err := s.repositories.Reminders.MarkDead(reminder, now, sendErr); err != nil {
+				s.logger.Errorf("Failed to update reminder %d to dead: %v", reminder.ID, err)
+			}
+			if err := s.repositories.Users.SetBotBlocked(reminder.TgID, now); err != nil {
+				s.logger.Errorf("Failed to disable reminders of user %d: %v", reminder.TgID, err)
+			}
+			continue
+		}
+
+		retried, err := s.repositories.Reminders.MarkFailed(reminder, now, sendErr)
+		if err != nil {
+			s.logger.Errorf("Failed to update reminder %d to failed: %v", reminder.ID, err)
+		}
+		if retried {
+			s.logger.Errorf("Failed to send %s to user %d (attempt %d of %d): %v",
+				handler.Name, reminder.TgID, reminder.Attempts, model.MaxReminderAttempts, sendErr)
+		} else {
+			s.logger.Errorf("Giving up on %s to user %d after %d attempts: %v",
+				handler.Name, reminder.TgID, reminder.Attempts, sendErr)
+		}
+	}
+
+	return false
+}
+
+// sendReminder generates the message of a reminder for its user and sends it
+func (s *Scheduler) sendReminder(handler ReminderHandler, reminder model.Reminder) error {
+	// Get user data
+	user, err := s.repositories.Users.GetByTgID(reminder.TgID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	message, keyboard, err := handler.Message(s, user, reminder)
+	if err != nil {
+		return err
+	}
+
+	opts := &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+	}
+	if len(keyboard) > 0 {
+		opts.ReplyMarkup = gotgbot.InlineKeyboardMarkup{InlineKeyboard: keyboard}
+	}
+
+	_, err = s.bot.SendMessage(reminder.TgID, message, opts)
+	return err
+}
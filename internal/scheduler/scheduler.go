@@ -1,7 +1,9 @@
 package scheduler
 
 import (
-	"cashout/internal/client"
+	"cashout/internal/db"
+	"cashout/internal/repository"
+	"sync"
 	"time"
 
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
@@ -10,19 +12,38 @@ import (
 )
 
 const (
-	WEEKLY_REMINDER_PROCESSING_MIN  = 60
-	MONTHLY_REMINDER_PROCESSING_MIN = 60
+	REMINDER_SCHEDULING_MIN = 15
+	REMINDER_PROCESSING_MIN = 5
+	ANOMALY_CHECK_MIN       = 60
+	DAILY_NUDGE_CHECK_MIN   = 15
+	LEADER_CHECK_SEC        = 30
+	// Reminders claimed at once by an instance
+	REMINDER_BATCH_SIZE = 50
 )
 
+// Repositories are those the reminders and the periodic checks use
+type Repositories struct {
+	Users        repository.Users
+	Transactions repository.Transactions
+	Reminders    repository.Reminders
+	Anomalies    repository.Anomalies
+	Bills        repository.Bills
+	Admin        repository.Admin
+}
+
 type Scheduler struct {
 	scheduler    *gocron.Scheduler
 	bot          *gotgbot.Bot
-	repositories client.Repositories
+	repositories Repositories
 	logger       *logrus.Logger
+
+	// Held while this instance is the one scheduling reminders and running the periodic checks
+	leaderLock *db.AdvisoryLock
+	leaderMu   sync.Mutex
 }
 
-func NewScheduler(bot *gotgbot.Bot, repos client.Repositories, logger *logrus.Logger) *Scheduler {
-	// Create scheduler with UTC timezone
+func NewScheduler(bot *gotgbot.Bot, repos Repositories, logger *logrus.Logger) *Scheduler {
+	// Create scheduler with UTC timezone, the reminders are scheduled in the time zone of each user
 	s := gocron.NewScheduler(time.UTC)
 
 	return &Scheduler{
@@ -35,46 +56,60 @@ func NewScheduler(bot *gotgbot.Bot, repos client.Repositories, logger *logrus.Lo
 
 func (s *Scheduler) Start() {
 	var err error
-	// Schedule the creation of weekly recaps
-	// s.scheduler.Every(1).Minute().Do(func() { /* TEST */
-	_, err = s.scheduler.Every(1).Day().At("15:00").Do(func() {
-		if err := s.createWeeklyReminders(); err != nil {
-			s.logger.Errorf("Failed to create weekly reminders: %v", err)
+	// Only one instance schedules reminders and runs the periodic checks, all of them send the reminders
+	s.refreshLeadership()
+	_, err = s.scheduler.Every(LEADER_CHECK_SEC).Seconds().Do(s.refreshLeadership)
+	if err != nil {
+		s.logger.Errorf("Failed to schedule leader election: %v", err)
+	}
+
+	// Create the next reminders of each type, often enough to follow changes of the users' settings
+	_, err = s.scheduler.Every(REMINDER_SCHEDULING_MIN).Minute().Do(func() {
+		if !s.isLeader() {
+			return
+		}
+		if err := s.createReminders(); err != nil {
+			s.logger.Errorf("Failed to create reminders: %v", err)
 		}
 	})
 	if err != nil {
-		s.logger.Errorf("Failed to schedule weekly reminders: %v", err)
+		s.logger.Errorf("Failed to schedule reminder creation: %v", err)
 	}
 
-	// Schedule the creation of monthly recaps
-	// s.scheduler.Every(1).Minute().Do(func() { /* TEST */
-	_, err = s.scheduler.Every(1).Day().At("10:00").Do(func() {
-		if err := s.createMonthlyReminders(); err != nil {
-			s.logger.Errorf("Failed to create monthly reminders: %v", err)
+	// Send the reminders that are due
+	_, err = s.scheduler.Every(REMINDER_PROCESSING_MIN).Minute().Do(func() {
+		if err := s.processReminders(); err != nil {
+			s.logger.Errorf("Failed to process reminders: %v", err)
 		}
 	})
 	if err != nil {
-		s.logger.Errorf("Failed to schedule monthly reminders: %v", err)
+		s.logger.Errorf("Failed to schedule reminder processing: %v", err)
 	}
 
-	// Process weekly reminders
-	_, err = s.scheduler.Every(WEEKLY_REMINDER_PROCESSING_MIN).Minute().Do(func() {
-		if err := s.processWeeklyReminders(); err != nil {
-			s.logger.Errorf("Failed to process weekly reminders: %v", err)
+	// Check for unusual spending
+	_, err = s.scheduler.Every(ANOMALY_CHECK_MIN).Minute().Do(func() {
+		if !s.isLeader() {
+			return
+		}
+		if err := s.checkAnomalies(); err != nil {
+			s.logger.Errorf("Failed to check anomalies: %v", err)
 		}
 	})
 	if err != nil {
-		s.logger.Errorf("Failed to schedule weekly reminders: %v", err)
+		s.logger.Errorf("Failed to schedule anomaly checks: %v", err)
 	}
 
-	// Process monthly reminders
-	_, err = s.scheduler.Every(MONTHLY_REMINDER_PROCESSING_MIN).Minute().Do(func() {
-		if err := s.processMonthlyReminders(); err != nil {
-			s.logger.Errorf("Failed to process monthly reminders: %v", err)
+	// Remind users who recorded nothing today to log
+	_, err = s.scheduler.Every(DAILY_NUDGE_CHECK_MIN).Minute().Do(func() {
+		if !s.isLeader() {
+			return
+		}
+		if err := s.sendDailyNudges(); err != nil {
+			s.logger.Errorf("Failed to send daily nudges: %v", err)
 		}
 	})
 	if err != nil {
-		s.logger.Errorf("Failed to schedule monthly reminders: %v", err)
+		s.logger.Errorf("Failed to schedule daily nudges: %v", err)
 	}
 
 	// Start the scheduler
@@ -84,5 +119,6 @@ func (s *Scheduler) Start() {
 
 func (s *Scheduler) Stop() {
 	s.scheduler.Stop()
+	s.resignLeadership()
 	s.logger.Info("Scheduler stopped")
 }
@@ -3,7 +3,6 @@ package scheduler
 import (
 	"cashout/internal/model"
 	"cashout/internal/utils"
-	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,151 +10,39 @@ import (
 	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
 )
 
-func getNextMonday() time.Time {
-	now := time.Now()
-	daysUntilMonday := (8 - int(now.Weekday())) % 7
-	if daysUntilMonday == 0 {
-		daysUntilMonday = 7
-	}
-	nextMonday := now.AddDate(0, 0, daysUntilMonday)
-	return nextMonday
-}
-
-// createWeeklyReminders creates reminder records for all active users
-func (s *Scheduler) createWeeklyReminders() error {
-	s.logger.Info("Creating weekly reminders...")
-
-	// Get all active users
-	users, err := s.repositories.Reminders.GetAllActiveUsers()
-	if err != nil {
-		return fmt.Errorf("failed to get active users: %w", err)
-	}
-
-	// Schedule for 6:00 GMT on Monday
-	monday := getNextMonday()
-	scheduledFor := time.Date(monday.Year(), monday.Month(), monday.Day(), 6, 0, 0, 0, time.UTC)
-
-	createdCount := 0
-	for _, user := range users {
-		err := s.repositories.Reminders.CreateOrUpdateWeeklyReminder(user.TgID, scheduledFor)
-		if err != nil {
-			s.logger.Errorf("Failed to create reminder for user %d: %v", user.TgID, err)
-			continue
-		}
-		createdCount++
-	}
-
-	s.logger.Infof("Created %d weekly reminders for %d active users", createdCount, len(users))
-	return nil
-}
-
-// processWeeklyReminders processes all pending weekly reminders
-func (s *Scheduler) processWeeklyReminders() error {
-	// Get all pending reminders that should be sent now
-	reminders, err := s.repositories.Reminders.GetPendingReminders(
-		model.ReminderTypeWeeklyRecap,
-		time.Now().UTC(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to get pending reminders: %w", err)
-	}
-
-	if len(reminders) == 0 {
-		return nil
-	}
-
-	s.logger.Infof("Processing %d pending weekly reminders", len(reminders))
-
-	for _, reminder := range reminders {
-		// Update status to processing (with transaction to prevent double processing)
-		err := s.repositories.Reminders.UpdateReminderStatusTransaction(
-			reminder.ID,
-			model.ReminderStatusProcessing,
-			nil,
-		)
-		if err != nil {
-			s.logger.Errorf("Failed to update reminder %d to processing: %v", reminder.ID, err)
-			continue
-		}
-
-		// Send the weekly recap
-		err = s.sendWeeklyRecap(reminder.TgID)
-
-		if err != nil {
-			errMsg := err.Error()
-			s.logger.Errorf("Failed to send weekly recap for user %d: %v", reminder.TgID, err)
-
-			err = s.repositories.Reminders.UpdateReminderStatusTransaction(
-				reminder.ID,
-				model.ReminderStatusFailed,
-				&errMsg,
-			)
-			if err != nil {
-				s.logger.Errorf("Failed to update reminder %d to failed: %v", reminder.ID, err)
-				return err
-			}
-		} else {
-			err = errors.Join(err, s.repositories.Reminders.UpdateReminderStatusTransaction(
-				reminder.ID,
-				model.ReminderStatusSent,
-				nil,
-			))
-			if err != nil {
-				s.logger.Errorf("Failed to update reminder %d to sent: %v", reminder.ID, err)
-				return err
-			}
-			s.logger.Infof("Successfully sent weekly recap to user %d", reminder.TgID)
-		}
-	}
-
-	return err
+func init() {
+	RegisterReminder(ReminderHandler{
+		Type:     model.ReminderTypeWeeklyRecap,
+		Name:     "weekly recap",
+		Schedule: recapSchedule(model.ReminderTypeWeeklyRecap),
+		Message:  recapMessage((*Scheduler).weeklyRecap),
+	})
 }
 
-// sendWeeklyRecap sends the previous week's recap to a user
-func (s *Scheduler) sendWeeklyRecap(tgID int64) error {
-	// Get user data
-	user, err := s.repositories.Users.GetByTgID(tgID)
-	if err != nil {
-		return fmt.Errorf("failed to get user: %w", err)
-	}
-
-	// Calculate previous week boundaries (Monday to Sunday)
-	now := time.Now().UTC()
-	weekday := int(now.Weekday())
-	if weekday == 0 {
-		weekday = 7
-	}
-	// Get to this week's Monday
-	daysToMonday := weekday - 1
-	thisMonday := now.AddDate(0, 0, -daysToMonday)
-
-	// Previous week is 7 days before
-	startOfPrevWeek := thisMonday.AddDate(0, 0, -7)
-	startOfPrevWeek = time.Date(startOfPrevWeek.Year(), startOfPrevWeek.Month(), startOfPrevWeek.Day(), 0, 0, 0, 0, time.UTC)
-
-	endOfPrevWeek := startOfPrevWeek.AddDate(0, 0, 6)
-	endOfPrevWeek = time.Date(endOfPrevWeek.Year(), endOfPrevWeek.Month(), endOfPrevWeek.Day(), 23, 59, 59, 999999999, time.UTC)
+// weeklyRecap generates the recap of the 7 days before the day it's sent on, which the user chooses
+func (s *Scheduler) weeklyRecap(user model.User, day time.Time) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	startOfPrevWeek := day.AddDate(0, 0, -7)
+	endOfPrevWeek := day.Add(-time.Nanosecond)
+	now := user.Now()
 
 	// Get transactions for the previous week
 	transactions, err := s.repositories.Transactions.GetUserTransactionsByDateRange(user.TgID, startOfPrevWeek, endOfPrevWeek)
 	if err != nil {
-		return fmt.Errorf("failed to get weekly transactions: %w", err)
+		return "", nil, fmt.Errorf("failed to get weekly transactions: %w", err)
 	}
 
-	// Generate the recap message
-	message := s.generateWeeklyRecapMessage(user, transactions, startOfPrevWeek, endOfPrevWeek)
-
-	// Send the message
-	_, err = s.bot.SendMessage(tgID, message, &gotgbot.SendMessageOpts{
-		ParseMode: "HTML",
-	})
+	// Get the projection for the current month
+	forecast, err := s.repositories.Transactions.GetMonthForecast(user.TgID, now)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get month forecast: %w", err)
+	}
 
-	return err
+	return s.generateWeeklyRecapMessage(user, transactions, startOfPrevWeek, endOfPrevWeek, forecast), nil, nil
 }
 
 // generateWeeklyRecapMessage generates the weekly recap message
-// This reuses the logic from the WeekRecap function but adapted for previous week
-func (s *Scheduler) generateWeeklyRecapMessage(user model.User, transactions []model.Transaction, startOfWeek, endOfWeek time.Time) string {
+// This reuses the logic from the WeekRecap function but adapted for the past 7 days
+func (s *Scheduler) generateWeeklyRecapMessage(user model.User, transactions []model.Transaction, startOfWeek, endOfWeek time.Time, forecast utils.Forecast) string {
 	var text strings.Builder
 
 	// Header
@@ -165,7 +52,7 @@ func (s *Scheduler) generateWeeklyRecapMessage(user model.User, transactions []m
 		endOfWeek.Format("02 Jan")))
 
 	if len(transactions) == 0 {
-		text.WriteString("You had no transactions last week.\n\n")
+		text.WriteString("You had no transactions in these 7 days.\n\n")
 		text.WriteString("💡 <i>Start tracking your expenses to get insights!</i>")
 		return text.String()
 	}
@@ -255,6 +142,11 @@ func (s *Scheduler) generateWeeklyRecapMessage(user model.User, transactions []m
 		text.WriteString(fmt.Sprintf("\n📈 <b>Avg Daily Spending:</b> %.2f€\n", avgDaily))
 	}
 
+	// Month forecast, once there's something to project
+	if forecast.SpentToDate > 0 || forecast.HistoryMonths > 0 {
+		text.WriteString("\n" + utils.FormatForecast(forecast) + "\n")
+	}
+
 	text.WriteString("\n💡 <i>Type /week to see this week's progress!</i>")
 
 	return text.String()
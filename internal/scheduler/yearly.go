@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"fmt"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+func init() {
+	RegisterReminder(ReminderHandler{
+		Type:     model.ReminderTypeYearlyRecap,
+		Name:     "yearly recap",
+		Schedule: recapSchedule(model.ReminderTypeYearlyRecap),
+		Message:  recapMessage((*Scheduler).yearlyRecap),
+	})
+}
+
+// yearlyRecap generates the previous year's recap of a user, like /year does, with its PDF statement
+func (s *Scheduler) yearlyRecap(user model.User, day time.Time) (string, [][]gotgbot.InlineKeyboardButton, error) {
+	// The year before the one the recap is sent in
+	year := day.Year() - 1
+
+	totals, err := s.repositories.Transactions.GetYearTotals(user.TgID, year)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get year totals: %w", err)
+	}
+	recap := utils.FormatYearRecap(totals, year, user.Now())
+
+	message := fmt.Sprintf("🎆 <b>%s, here's your %d in review!</b>\n\n%s", user.Name, year, recap)
+	keyboard := [][]gotgbot.InlineKeyboardButton{
+		{{Text: "📄 PDF Statement", CallbackData: fmt.Sprintf("statement.year.%d", year)}},
+	}
+
+	return message, keyboard, nil
+}
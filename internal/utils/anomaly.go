@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+const (
+	// AnomalyHistoryDays is how far back the history used to detect anomalies goes
+	AnomalyHistoryDays = 70
+
+	// A transaction is unusually large when it's at least largeAmountFactor times the category median
+	// (and at least largeAmountMinDiff above it), given largeAmountMinSamples past transactions
+	largeAmountFactor     = 3.0
+	largeAmountMinDiff    = 20.0
+	largeAmountMinSamples = 5
+
+	// Two transactions are duplicates when they're identical and recorded within duplicateWindow
+	duplicateWindow = 10 * time.Minute
+
+	// A category spikes when this week's total is spikeFactor times its weekly average (and spikeMinDiff above it),
+	// averaged over the previous spikeWeeks weeks, given at least spikeMinWeeks weeks with expenses
+	spikeFactor   = 2.0
+	spikeMinDiff  = 50.0
+	spikeWeeks    = 8
+	spikeMinWeeks = 4
+)
+
+// DetectTransactionAnomalies checks a transaction against the user's history for an unusually large amount
+// or a duplicate-looking entry (only the later entry of a pair is reported). history can include the transaction itself.
+func DetectTransactionAnomalies(t model.Transaction, history []model.Transaction) []model.Anomaly {
+	var anomalies []model.Anomaly
+
+	if t.Type == model.TypeExpense {
+		var amounts []float64
+		for _, h := range history {
+			if h.ID != t.ID && h.Type == t.Type && h.Category == t.Category {
+				amounts = append(amounts, h.Amount)
+			}
+		}
+
+		if len(amounts) >= largeAmountMinSamples {
+			typical := median(amounts)
+			if t.Amount >= typical*largeAmountFactor && t.Amount-typical >= largeAmountMinDiff {
+				anomalies = append(anomalies, newTransactionAnomaly(model.AnomalyLargeAmount, t, typical))
+			}
+		}
+	}
+
+	for _, h := range history {
+		if h.ID == t.ID || h.Type != t.Type || h.Category != t.Category || h.Amount != t.Amount {
+			continue
+		}
+		if !strings.EqualFold(strings.TrimSpace(h.Description), strings.TrimSpace(t.Description)) {
+			continue
+		}
+
+		// Only the later of the two is the duplicate
+		gap := t.CreatedAt.Sub(h.CreatedAt)
+		if gap < 0 || (gap == 0 && h.ID > t.ID) {
+			continue
+		}
+		if gap <= duplicateWindow {
+			anomalies = append(anomalies, newTransactionAnomaly(model.AnomalyDuplicate, t, h.Amount))
+			break
+		}
+	}
+
+	return anomalies
+}
+
+func newTransactionAnomaly(kind model.AnomalyKind, t model.Transaction, typical float64) model.Anomaly {
+	id := t.ID
+	tx := t
+	return model.Anomaly{
+		TgID:          t.TgID,
+		Key:           fmt.Sprintf("%s:%d", kind, t.ID),
+		Kind:          kind,
+		TransactionID: &id,
+		Category:      t.Category,
+		Amount:        t.Amount,
+		Typical:       typical,
+		Transaction:   &tx,
+	}
+}
+
+// DetectCategorySpikes compares each expense category's total of the current week (Monday to now)
+// with its weekly average over the previous weeks
+func DetectCategorySpikes(now time.Time, transactions []model.Transaction) []model.Anomaly {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	weekday := int(today.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	weekStart := today.AddDate(0, 0, -(weekday - 1))
+	historyStart := weekStart.AddDate(0, 0, -7*spikeWeeks)
+
+	current := make(map[model.TransactionCategory]float64)
+	past := make(map[model.TransactionCategory]float64)
+	weeksWithExpenses := make(map[int]bool)
+
+	for _, t := range transactions {
+		if t.Type != model.TypeExpense || t.Date.Before(historyStart) || t.Date.After(today) {
+			continue
+		}
+		if !t.Date.Before(weekStart) {
+			current[t.Category] += t.Amount
+			continue
+		}
+		past[t.Category] += t.Amount
+		weeksWithExpenses[int(t.Date.Sub(historyStart).Hours()/24/7)] = true
+	}
+
+	if len(weeksWithExpenses) < spikeMinWeeks {
+		return nil
+	}
+
+	var anomalies []model.Anomaly
+	for category, amount := range current {
+		average := past[category] / float64(spikeWeeks)
+		if average == 0 || amount < average*spikeFactor || amount-average < spikeMinDiff {
+			continue
+		}
+
+		anomalies = append(anomalies, model.Anomaly{
+			Key:      fmt.Sprintf("%s:%s:%s", model.AnomalyCategorySpike, category, weekStart.Format("2006-01-02")),
+			Kind:     model.AnomalyCategorySpike,
+			Category: category,
+			Amount:   math.Round(amount*100) / 100,
+			Typical:  math.Round(average*100) / 100,
+		})
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		return anomalies[i].Category < anomalies[j].Category
+	})
+
+	return anomalies
+}
+
+// FormatAnomaly describes the anomaly to the user
+func FormatAnomaly(anomaly model.Anomaly) string {
+	var text strings.Builder
+	emoji := GetCategoryEmoji(anomaly.Category)
+
+	switch anomaly.Kind {
+	case model.AnomalyLargeAmount:
+		text.WriteString("🧐 <b>Unusually large expense</b>\n\n")
+		writeAnomalyTransaction(&text, anomaly)
+		text.WriteString(fmt.Sprintf("\nThat's %.1fx your typical %s %s expense of %.2f€.", anomaly.Amount/anomaly.Typical, emoji, anomaly.Category, anomaly.Typical))
+	case model.AnomalyDuplicate:
+		text.WriteString("👯 <b>Possible duplicate</b>\n\n")
+		writeAnomalyTransaction(&text, anomaly)
+		text.WriteString("\nIt looks just like another transaction recorded a few minutes earlier.")
+	case model.AnomalyCategorySpike:
+		text.WriteString(fmt.Sprintf("📈 <b>%s %s is spiking</b>\n\n", emoji, anomaly.Category))
+		text.WriteString(fmt.Sprintf("This week you spent %.2f€ on %s, %.1fx your weekly average of %.2f€.", anomaly.Amount, anomaly.Category, anomaly.Amount/anomaly.Typical, anomaly.Typical))
+	}
+
+	return text.String()
+}
+
+// writeAnomalyTransaction writes the amount, date and description of the transaction of an anomaly
+func writeAnomalyTransaction(text *strings.Builder, anomaly model.Anomaly) {
+	if anomaly.Transaction == nil {
+		text.WriteString(fmt.Sprintf("%s <b>%s:</b> %.2f€\n", GetCategoryEmoji(anomaly.Category), anomaly.Category, anomaly.Amount))
+		return
+	}
+
+	t := anomaly.Transaction
+	text.WriteString(fmt.Sprintf("%s <b>%s:</b> %.2f€\n", GetCategoryEmoji(t.Category), t.Category, t.Amount))
+	text.WriteString(fmt.Sprintf("📅 %s", t.Date.Format("02-01-2006")))
+	if t.Description != "" {
+		text.WriteString(fmt.Sprintf(" - %s", t.Description))
+	}
+	text.WriteString("\n")
+}
+
+// AnomalyKeyboard offers to dismiss the anomaly, and to edit or delete its transaction or see the month recap
+func AnomalyKeyboard(anomaly model.Anomaly) [][]gotgbot.InlineKeyboardButton {
+	keyboard := [][]gotgbot.InlineKeyboardButton{
+		{
+			{Text: "👍 That's fine", CallbackData: fmt.Sprintf("anomaly.fine.%d", anomaly.ID)},
+		},
+	}
+	if anomaly.TransactionID != nil {
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: "✏️ Edit", CallbackData: fmt.Sprintf("edit.select.%d", *anomaly.TransactionID)},
+			{Text: "🗑 Delete", CallbackData: fmt.Sprintf("delete.confirm.%d", *anomaly.TransactionID)},
+		})
+	} else {
+		keyboard = append(keyboard, []gotgbot.InlineKeyboardButton{
+			{Text: "📊 Month Recap", CallbackData: "home.month"},
+		})
+	}
+
+	return keyboard
+}
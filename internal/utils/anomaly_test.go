@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectTransactionAnomalies(t *testing.T) {
+	created := time.Date(2025, 5, 14, 12, 0, 0, 0, time.UTC)
+
+	var history []model.Transaction
+	for i := int64(1); i <= 6; i++ {
+		history = append(history, model.Transaction{
+			ID:        i,
+			Type:      model.TypeExpense,
+			Category:  model.CategoryGrocery,
+			Amount:    40 + float64(i),
+			CreatedAt: created.AddDate(0, 0, -int(i)),
+		})
+	}
+
+	tests := []struct {
+		name        string
+		transaction model.Transaction
+		extra       []model.Transaction
+		want        []model.AnomalyKind
+	}{
+		{
+			name:        "typical amount",
+			transaction: model.Transaction{ID: 10, Type: model.TypeExpense, Category: model.CategoryGrocery, Amount: 50, CreatedAt: created},
+		},
+		{
+			name:        "large amount",
+			transaction: model.Transaction{ID: 10, Type: model.TypeExpense, Category: model.CategoryGrocery, Amount: 300, CreatedAt: created},
+			want:        []model.AnomalyKind{model.AnomalyLargeAmount},
+		},
+		{
+			name:        "not enough history for the category",
+			transaction: model.Transaction{ID: 10, Type: model.TypeExpense, Category: model.CategoryTravel, Amount: 900, CreatedAt: created},
+		},
+		{
+			name:        "duplicate within minutes",
+			transaction: model.Transaction{ID: 10, Type: model.TypeExpense, Category: model.CategoryEatingOut, Amount: 12.5, Description: "Pizza", CreatedAt: created},
+			extra: []model.Transaction{
+				{ID: 9, Type: model.TypeExpense, Category: model.CategoryEatingOut, Amount: 12.5, Description: "pizza ", CreatedAt: created.Add(-3 * time.Minute)},
+			},
+			want: []model.AnomalyKind{model.AnomalyDuplicate},
+		},
+		{
+			name:        "original of a duplicate",
+			transaction: model.Transaction{ID: 9, Type: model.TypeExpense, Category: model.CategoryEatingOut, Amount: 12.5, Description: "Pizza", CreatedAt: created.Add(-3 * time.Minute)},
+			extra: []model.Transaction{
+				{ID: 10, Type: model.TypeExpense, Category: model.CategoryEatingOut, Amount: 12.5, Description: "Pizza", CreatedAt: created},
+			},
+		},
+		{
+			name:        "same entry hours apart",
+			transaction: model.Transaction{ID: 10, Type: model.TypeExpense, Category: model.CategoryEatingOut, Amount: 12.5, Description: "Pizza", CreatedAt: created},
+			extra: []model.Transaction{
+				{ID: 9, Type: model.TypeExpense, Category: model.CategoryEatingOut, Amount: 12.5, Description: "Pizza", CreatedAt: created.Add(-3 * time.Hour)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			all := append(append([]model.Transaction{tt.transaction}, history...), tt.extra...)
+			got := DetectTransactionAnomalies(tt.transaction, all)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("DetectTransactionAnomalies() = %+v, want kinds %v", got, tt.want)
+			}
+			for i, kind := range tt.want {
+				if got[i].Kind != kind {
+					t.Errorf("anomaly %d kind = %s, want %s", i, got[i].Kind, kind)
+				}
+				if got[i].TransactionID == nil || *got[i].TransactionID != tt.transaction.ID {
+					t.Errorf("anomaly %d transaction = %v, want %d", i, got[i].TransactionID, tt.transaction.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectCategorySpikes(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 5, 14, 18, 0, 0, 0, time.UTC)
+
+	// 8 past weeks with 50€ of groceries and 20€ of transport each
+	var transactions []model.Transaction
+	for w := 1; w <= 8; w++ {
+		day := time.Date(2025, 5, 13, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -7*w)
+		transactions = append(transactions,
+			model.Transaction{Date: day, Type: model.TypeExpense, Category: model.CategoryGrocery, Amount: 50},
+			model.Transaction{Date: day, Type: model.TypeExpense, Category: model.CategoryTransport, Amount: 20},
+		)
+	}
+
+	// This week: groceries spike, transport as usual, a new category without history
+	transactions = append(transactions,
+		model.Transaction{Date: time.Date(2025, 5, 12, 0, 0, 0, 0, time.UTC), Type: model.TypeExpense, Category: model.CategoryGrocery, Amount: 180},
+		model.Transaction{Date: time.Date(2025, 5, 13, 0, 0, 0, 0, time.UTC), Type: model.TypeExpense, Category: model.CategoryTransport, Amount: 25},
+		model.Transaction{Date: time.Date(2025, 5, 13, 0, 0, 0, 0, time.UTC), Type: model.TypeExpense, Category: model.CategoryTravel, Amount: 400},
+	)
+
+	got := DetectCategorySpikes(now, transactions)
+	if len(got) != 1 {
+		t.Fatalf("DetectCategorySpikes() = %+v, want one spike", got)
+	}
+	if got[0].Category != model.CategoryGrocery || got[0].Amount != 180 || got[0].Typical != 50 {
+		t.Errorf("DetectCategorySpikes() = %+v, want Grocery 180 vs 50", got[0])
+	}
+	if got[0].Key != "category_spike:Grocery:2025-05-12" {
+		t.Errorf("Key = %s", got[0].Key)
+	}
+
+	// Without enough history nothing is reported
+	if got := DetectCategorySpikes(now, transactions[14:]); len(got) != 0 {
+		t.Errorf("DetectCategorySpikes() with little history = %+v, want none", got)
+	}
+}
+
+func TestAnomalyKeyboard(t *testing.T) {
+	transactionID := int64(7)
+
+	tests := []struct {
+		name    string
+		anomaly model.Anomaly
+		want    []string
+	}{
+		{
+			name:    "transaction",
+			anomaly: model.Anomaly{ID: 3, Kind: model.AnomalyDuplicate, TransactionID: &transactionID},
+			want:    []string{"anomaly.fine.3", "edit.select.7", "delete.confirm.7"},
+		},
+		{
+			name:    "category spike",
+			anomaly: model.Anomaly{ID: 4, Kind: model.AnomalyCategorySpike},
+			want:    []string{"anomaly.fine.4", "home.month"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, row := range AnomalyKeyboard(tt.anomaly) {
+				for _, button := range row {
+					got = append(got, button.CallbackData)
+				}
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("AnomalyKeyboard() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
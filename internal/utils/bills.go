@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidBill is returned when a bill can't be parsed
+var ErrInvalidBill = errors.New("invalid bill")
+
+// BillDateLayout is the layout of due dates in the bill buttons
+const BillDateLayout = "20060102"
+
+// maxBillRemindDaysBefore caps how early a bill is reminded
+const maxBillRemindDaysBefore = 60
+
+// BillSpec is a bill as described by the user
+type BillSpec struct {
+	Name      string
+	Amount    float64
+	Category  model.TransactionCategory
+	Frequency model.BillFrequency
+	DueDay    int
+	// Only for yearly bills
+	DueMonth         time.Month
+	RemindDaysBefore int
+}
+
+var (
+	billRemindPattern = regexp.MustCompile(`^(.*?)\s*,?\s*(?:remind(?:\s+me)?\s+)?(\d+)\s+days?\s+before$`)
+	billDayPattern    = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)?$`)
+	billDatePattern   = regexp.MustCompile(`^(\d{1,2})[-/.](\d{1,2})$`)
+	billCarPattern    = regexp.MustCompile(`(?i)\bcar\b`)
+)
+
+// billFillerWords are ignored in the schedule of a bill, e.g. "every month on the 5th"
+var billFillerWords = map[string]bool{"on": true, "the": true, "of": true, "each": true}
+
+// ParseBill parses a bill like "internet 29.90 every 5th" or "car insurance yearly on 12-03".
+// Supported formats:
+// - NAME [AMOUNT] SCHEDULE [, N days before] [in CATEGORY]
+// - SCHEDULE is "monthly on DAY", "every DAY", "every month on the DAY", "yearly on DATE" or "every year on DATE"
+// - DAY is a day of the month like 5 or 5th, DATE is a day and month like 12-03, 12/03 or 12 march
+//
+// Without an amount it's asked when the bill is paid. The category is Bills, or Car for car bills,
+// unless another expense category is given.
+func ParseBill(input string) (BillSpec, error) {
+	fields := strings.Fields(input)
+
+	schedule := -1
+	for i, field := range fields {
+		switch strings.ToLower(field) {
+		case "every", "monthly", "yearly", "annually":
+			schedule = i
+		}
+		if schedule >= 0 {
+			break
+		}
+	}
+	if schedule < 0 {
+		return BillSpec{}, fmt.Errorf("%w: missing when it's due, e.g. \"every 5th\" or \"yearly on 12-03\"", ErrInvalidBill)
+	}
+
+	spec := BillSpec{RemindDaysBefore: model.DefaultBillRemindDaysBefore}
+
+	// The amount is the last word before the schedule, if it's a number
+	head := fields[:schedule]
+	if len(head) > 0 {
+		amountStr := strings.ReplaceAll(strings.TrimSuffix(head[len(head)-1], "€"), ",", ".")
+		if amount, err := strconv.ParseFloat(amountStr, 64); err == nil {
+			if amount <= 0 {
+				return BillSpec{}, fmt.Errorf("%w: the amount must be greater than zero", ErrInvalidBill)
+			}
+			spec.Amount = amount
+			head = head[:len(head)-1]
+		}
+	}
+
+	spec.Name = strings.Join(head, " ")
+	if spec.Name == "" {
+		return BillSpec{}, fmt.Errorf("%w: missing the name of the bill", ErrInvalidBill)
+	}
+	if len(spec.Name) > 64 {
+		return BillSpec{}, fmt.Errorf("%w: the name is too long", ErrInvalidBill)
+	}
+
+	tail := fields[schedule:]
+
+	// Category at the end, e.g. "in Car"
+	spec.Category = model.CategoryBills
+	if billCarPattern.MatchString(spec.Name) {
+		spec.Category = model.CategoryCar
+	}
+	for i := len(tail) - 1; i > 0; i-- {
+		if strings.ToLower(tail[i]) != "in" {
+			continue
+		}
+		category, ok := parseExpenseCategory(strings.Join(tail[i+1:], ""))
+		if !ok {
+			return BillSpec{}, fmt.Errorf("%w: unknown expense category %q", ErrInvalidBill, strings.Join(tail[i+1:], " "))
+		}
+		spec.Category = category
+		tail = tail[:i]
+		break
+	}
+
+	// How early to remind it, e.g. "5 days before"
+	when := strings.ToLower(strings.Join(tail, " "))
+	if match := billRemindPattern.FindStringSubmatch(when); match != nil {
+		days, err := strconv.Atoi(match[2])
+		if err != nil || days > maxBillRemindDaysBefore {
+			return BillSpec{}, fmt.Errorf("%w: it can be reminded up to %d days before", ErrInvalidBill, maxBillRemindDaysBefore)
+		}
+		spec.RemindDaysBefore = days
+		when = match[1]
+	}
+
+	words := strings.Fields(strings.ReplaceAll(when, ",", " "))
+	switch words[0] {
+	case "monthly":
+		spec.Frequency = model.BillFrequencyMonthly
+	case "yearly", "annually":
+		spec.Frequency = model.BillFrequencyYearly
+	}
+
+	var date []string
+	for _, word := range words[1:] {
+		switch {
+		case billFillerWords[word]:
+		case word == "month" && words[0] == "every":
+			spec.Frequency = model.BillFrequencyMonthly
+		case word == "year" && words[0] == "every":
+			spec.Frequency = model.BillFrequencyYearly
+		default:
+			date = append(date, word)
+		}
+	}
+
+	day, month, err := parseBillDate(date)
+	if err != nil {
+		return BillSpec{}, err
+	}
+
+	switch {
+	case month == 0 && spec.Frequency == model.BillFrequencyYearly:
+		return BillSpec{}, fmt.Errorf("%w: yearly bills need a day and month, e.g. \"yearly on 12-03\"", ErrInvalidBill)
+	case month != 0 && spec.Frequency == model.BillFrequencyMonthly:
+		return BillSpec{}, fmt.Errorf("%w: monthly bills need only a day, e.g. \"monthly on the 5th\"", ErrInvalidBill)
+	case month != 0:
+		spec.Frequency = model.BillFrequencyYearly
+	default:
+		spec.Frequency = model.BillFrequencyMonthly
+	}
+
+	spec.DueDay = day
+	spec.DueMonth = month
+	return spec, nil
+}
+
+// parseBillDate parses the day a bill is due, with its month for yearly bills (zero otherwise)
+func parseBillDate(words []string) (int, time.Month, error) {
+	var day, month int
+	switch len(words) {
+	case 1:
+		if match := billDatePattern.FindStringSubmatch(words[0]); match != nil {
+			day, _ = strconv.Atoi(match[1])
+			month, _ = strconv.Atoi(match[2])
+		} else if match := billDayPattern.FindStringSubmatch(words[0]); match != nil {
+			day, _ = strconv.Atoi(match[1])
+		} else {
+			return 0, 0, fmt.Errorf("%w: unknown due date %q", ErrInvalidBill, words[0])
+		}
+	case 2:
+		// A day and a month name, in any order
+		dayWord, monthWord := words[0], words[1]
+		if billDayPattern.MatchString(monthWord) {
+			dayWord, monthWord = monthWord, dayWord
+		}
+		match := billDayPattern.FindStringSubmatch(dayWord)
+		m, ok := parseMonthName(monthWord)
+		if match == nil || !ok {
+			return 0, 0, fmt.Errorf("%w: unknown due date %q", ErrInvalidBill, strings.Join(words, " "))
+		}
+		day, _ = strconv.Atoi(match[1])
+		month = int(m)
+	default:
+		return 0, 0, fmt.Errorf("%w: missing the due date, e.g. \"every 5th\" or \"yearly on 12-03\"", ErrInvalidBill)
+	}
+
+	if month < 0 || month > 12 {
+		return 0, 0, fmt.Errorf("%w: invalid month %d", ErrInvalidBill, month)
+	}
+	maxDay := 31
+	if month != 0 {
+		// A leap year, bills due on February 29th are due on the 28th in other years
+		maxDay = time.Date(2024, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	}
+	if day < 1 || day > maxDay {
+		return 0, 0, fmt.Errorf("%w: invalid day %d", ErrInvalidBill, day)
+	}
+
+	return day, time.Month(month), nil
+}
+
+// parseExpenseCategory finds an expense category by name, ignoring case and spaces
+func parseExpenseCategory(name string) (model.TransactionCategory, bool) {
+	for _, c := range model.GetTransactionCategories() {
+		category := model.TransactionCategory(c)
+		if strings.EqualFold(c, name) && !model.IsIncomeCategory(category) {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// BillPaidCallback is the callback data of the button paying the next due date of a bill
+func BillPaidCallback(bill model.Bill) string {
+	return fmt.Sprintf("bills.paid.%d.%s", bill.ID, bill.NextDueDate.Format(BillDateLayout))
+}
+
+// FormatBillAmount describes the amount of a bill
+func FormatBillAmount(bill model.Bill) string {
+	if bill.Amount <= 0 {
+		return "(amount varies)"
+	}
+	return fmt.Sprintf("%.2f€", bill.Amount)
+}
+
+// FormatBillSchedule describes when a bill is due, e.g. "monthly on the 5th"
+func FormatBillSchedule(bill model.Bill) string {
+	if bill.Frequency == model.BillFrequencyYearly {
+		return fmt.Sprintf("yearly on %d %s", bill.DueDay, bill.DueMonth)
+	}
+	return fmt.Sprintf("monthly on the %s", ordinal(bill.DueDay))
+}
+
+// FormatBillDue describes when the next payment of a bill is due relative to today
+func FormatBillDue(bill model.Bill, today time.Time) string {
+	date := bill.NextDueDate.Format("Mon 02 Jan")
+	days := int(bill.NextDueDate.Sub(model.DateOf(today)).Hours() / 24)
+	switch {
+	case days < 0:
+		return fmt.Sprintf("⚠️ Overdue since %s", date)
+	case days == 0:
+		return "📅 Due today"
+	case days == 1:
+		return "📅 Due tomorrow"
+	default:
+		return fmt.Sprintf("📅 Due %s, in %d days", date, days)
+	}
+}
+
+// ordinal formats a day of the month, e.g. 1st or 22nd
+func ordinal(day int) string {
+	suffix := "th"
+	switch {
+	case day%100 >= 11 && day%100 <= 13:
+	case day%10 == 1:
+		suffix = "st"
+	case day%10 == 2:
+		suffix = "nd"
+	case day%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", day, suffix)
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseBill(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    BillSpec
+		wantErr bool
+	}{
+		{
+			name:  "monthly with ordinal",
+			input: "internet 29.90 every 5th",
+			want: BillSpec{Name: "internet", Amount: 29.90, Category: model.CategoryBills,
+				Frequency: model.BillFrequencyMonthly, DueDay: 5, RemindDaysBefore: 3},
+		},
+		{
+			name:  "yearly car bill without amount",
+			input: "car insurance yearly on 12-03",
+			want: BillSpec{Name: "car insurance", Category: model.CategoryCar,
+				Frequency: model.BillFrequencyYearly, DueDay: 12, DueMonth: time.March, RemindDaysBefore: 3},
+		},
+		{
+			name:  "every month on the day",
+			input: "Rent 750,50€ every month on the 1st",
+			want: BillSpec{Name: "Rent", Amount: 750.50, Category: model.CategoryBills,
+				Frequency: model.BillFrequencyMonthly, DueDay: 1, RemindDaysBefore: 3},
+		},
+		{
+			name:  "every date is yearly",
+			input: "domain 12 every 28/02",
+			want: BillSpec{Name: "domain", Amount: 12, Category: model.CategoryBills,
+				Frequency: model.BillFrequencyYearly, DueDay: 28, DueMonth: time.February, RemindDaysBefore: 3},
+		},
+		{
+			name:  "month name, days before and category",
+			input: "gym 300 every year on 15 september, 7 days before in sport",
+			want: BillSpec{Name: "gym", Amount: 300, Category: model.CategorySport,
+				Frequency: model.BillFrequencyYearly, DueDay: 15, DueMonth: time.September, RemindDaysBefore: 7},
+		},
+		{
+			name:  "number in the name",
+			input: "tim 5g monthly on 20",
+			want: BillSpec{Name: "tim 5g", Category: model.CategoryBills,
+				Frequency: model.BillFrequencyMonthly, DueDay: 20, RemindDaysBefore: 3},
+		},
+		{
+			name:    "no schedule",
+			input:   "internet 29.90",
+			wantErr: true,
+		},
+		{
+			name:    "no name",
+			input:   "29.90 every 5th",
+			wantErr: true,
+		},
+		{
+			name:    "no due date",
+			input:   "internet every month",
+			wantErr: true,
+		},
+		{
+			name:    "monthly with a month",
+			input:   "internet monthly on 12-03",
+			wantErr: true,
+		},
+		{
+			name:    "yearly without a month",
+			input:   "insurance yearly on 12",
+			wantErr: true,
+		},
+		{
+			name:    "day out of the month",
+			input:   "insurance yearly on 31-04",
+			wantErr: true,
+		},
+		{
+			name:    "income category",
+			input:   "internet every 5th in salary",
+			wantErr: true,
+		},
+		{
+			name:    "negative amount",
+			input:   "internet -10 every 5th",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBill(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidBill) {
+					t.Fatalf("ParseBill(%q) error = %v, want ErrInvalidBill", tt.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBill(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseBill(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBillSchedule(t *testing.T) {
+	tests := []struct {
+		bill model.Bill
+		want string
+	}{
+		{model.Bill{Frequency: model.BillFrequencyMonthly, DueDay: 1}, "monthly on the 1st"},
+		{model.Bill{Frequency: model.BillFrequencyMonthly, DueDay: 12}, "monthly on the 12th"},
+		{model.Bill{Frequency: model.BillFrequencyMonthly, DueDay: 22}, "monthly on the 22nd"},
+		{model.Bill{Frequency: model.BillFrequencyMonthly, DueDay: 23}, "monthly on the 23rd"},
+		{model.Bill{Frequency: model.BillFrequencyYearly, DueDay: 5, DueMonth: time.March}, "yearly on 5 March"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatBillSchedule(tt.bill); got != tt.want {
+				t.Errorf("FormatBillSchedule() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBillDue(t *testing.T) {
+	today := time.Date(2025, 5, 10, 22, 30, 0, 0, time.UTC)
+	due := func(day int) model.Bill {
+		return model.Bill{NextDueDate: time.Date(2025, 5, day, 0, 0, 0, 0, time.UTC)}
+	}
+
+	tests := []struct {
+		name string
+		bill model.Bill
+		want string
+	}{
+		{"overdue", due(8), "⚠️ Overdue since Thu 08 May"},
+		{"today", due(10), "📅 Due today"},
+		{"tomorrow", due(11), "📅 Due tomorrow"},
+		{"later", due(15), "📅 Due Thu 15 May, in 5 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatBillDue(tt.bill, today); got != tt.want {
+				t.Errorf("FormatBillDue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
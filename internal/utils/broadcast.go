@@ -0,0 +1,8 @@
+package utils
+
+import "html"
+
+// FormatBroadcast formats an announcement the way users receive it
+func FormatBroadcast(message string) string {
+	return "📣 <b>Announcement</b>\n\n" + html.EscapeString(message)
+}
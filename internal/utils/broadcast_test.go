@@ -0,0 +1,10 @@
+package utils
+
+import "testing"
+
+func TestFormatBroadcast(t *testing.T) {
+	want := "📣 <b>Announcement</b>\n\nNew &lt;b&gt;export&lt;/b&gt; &amp; more"
+	if got := FormatBroadcast("New <b>export</b> & more"); got != want {
+		t.Errorf("FormatBroadcast() = %q, want %q", got, want)
+	}
+}
@@ -49,3 +49,16 @@ func IsAnIncomeTransactionPrompt(text string) bool {
 
 	return matched
 }
+
+// GuessTransactionType applies the free text heuristics: the text must contain a digit to be a transaction
+// (ok is false otherwise), expenses are more common than incomes, unless income words are found
+func GuessTransactionType(text string) (transactionType model.TransactionType, ok bool) {
+	if !strings.ContainsAny(text, "0123456789") {
+		return "", false
+	}
+
+	if IsAnIncomeTransactionPrompt(text) {
+		return model.TypeIncome, true
+	}
+	return model.TypeExpense, true
+}
@@ -108,3 +108,26 @@ func TestIsAnIncomeTransactionPrompt(t *testing.T) {
 		})
 	}
 }
+
+func TestGuessTransactionType(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   model.TransactionType
+		wantOk bool
+	}{
+		{input: "coffee 3.50", want: model.TypeExpense, wantOk: true},
+		{input: "salary 3000 euro", want: model.TypeIncome, wantOk: true},
+		{input: "amazon refund 50", want: model.TypeIncome, wantOk: true},
+		{input: "hello there", wantOk: false},
+		{input: "got my salary", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := GuessTransactionType(tt.input)
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("GuessTransactionType(%q) = %q, %v, want %q, %v", tt.input, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
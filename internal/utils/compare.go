@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Delta is the change of an amount between two periods
+type Delta struct {
+	Current  float64 `json:"current"`
+	Previous float64 `json:"previous"`
+	Change   float64 `json:"change"`
+	// Percent is the relative change, 0 when there's nothing to compare against (see New)
+	Percent float64 `json:"percent"`
+	// New is true when the previous amount was zero
+	New bool `json:"new"`
+}
+
+// NewDelta computes the change from previous to current
+func NewDelta(current, previous float64) Delta {
+	d := Delta{
+		Current:  current,
+		Previous: previous,
+		Change:   current - previous,
+	}
+
+	if previous == 0 {
+		d.New = current != 0
+		return d
+	}
+
+	// Relative to the size of the previous amount, so a negative balance going up is an increase
+	d.Percent = d.Change / math.Abs(previous) * 100
+	return d
+}
+
+// CategoryDelta is the change of a category total between two periods
+type CategoryDelta struct {
+	Type     model.TransactionType     `json:"type"`
+	Category model.TransactionCategory `json:"category"`
+	Delta
+}
+
+// Comparison holds the changes between two periods
+type Comparison struct {
+	Expenses   Delta           `json:"expenses"`
+	Income     Delta           `json:"income"`
+	Balance    Delta           `json:"balance"`
+	Categories []CategoryDelta `json:"categories"`
+}
+
+// ComparePeriods compares the categorized totals of two periods.
+// Categories are sorted by absolute change (descending).
+func ComparePeriods(current, previous map[model.TransactionType]map[model.TransactionCategory]float64) Comparison {
+	var c Comparison
+
+	sum := func(m map[model.TransactionCategory]float64) float64 {
+		var total float64
+		for _, amount := range m {
+			total += amount
+		}
+		return total
+	}
+
+	curExpenses, prevExpenses := sum(current[model.TypeExpense]), sum(previous[model.TypeExpense])
+	curIncome, prevIncome := sum(current[model.TypeIncome]), sum(previous[model.TypeIncome])
+
+	c.Expenses = NewDelta(curExpenses, prevExpenses)
+	c.Income = NewDelta(curIncome, prevIncome)
+	c.Balance = NewDelta(curIncome-curExpenses, prevIncome-prevExpenses)
+
+	for _, tt := range []model.TransactionType{model.TypeExpense, model.TypeIncome} {
+		categories := make(map[model.TransactionCategory]struct{})
+		for cat := range current[tt] {
+			categories[cat] = struct{}{}
+		}
+		for cat := range previous[tt] {
+			categories[cat] = struct{}{}
+		}
+
+		for cat := range categories {
+			c.Categories = append(c.Categories, CategoryDelta{
+				Type:     tt,
+				Category: cat,
+				Delta:    NewDelta(current[tt][cat], previous[tt][cat]),
+			})
+		}
+	}
+
+	sort.Slice(c.Categories, func(i, j int) bool {
+		ai, aj := math.Abs(c.Categories[i].Change), math.Abs(c.Categories[j].Change)
+		if ai != aj {
+			return ai > aj
+		}
+		return c.Categories[i].Category < c.Categories[j].Category
+	})
+
+	return c
+}
+
+// BiggestIncreases returns up to n expense categories that grew the most
+func (c Comparison) BiggestIncreases(n int) []CategoryDelta {
+	return c.topExpenseChanges(n, func(d CategoryDelta) bool { return d.Change > 0 })
+}
+
+// BiggestDecreases returns up to n expense categories that shrank the most
+func (c Comparison) BiggestDecreases(n int) []CategoryDelta {
+	return c.topExpenseChanges(n, func(d CategoryDelta) bool { return d.Change < 0 })
+}
+
+func (c Comparison) topExpenseChanges(n int, keep func(CategoryDelta) bool) []CategoryDelta {
+	var result []CategoryDelta
+	for _, d := range c.Categories {
+		if len(result) == n {
+			break
+		}
+		if d.Type == model.TypeExpense && keep(d) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// SumMonthlyTotals adds up the monthly totals (as returned by GetMonthlyTotalsInYear) from January up to month
+func SumMonthlyTotals(totals map[int]map[model.TransactionType]float64, upToMonth int) map[model.TransactionType]float64 {
+	result := make(map[model.TransactionType]float64)
+	for m := 1; m <= upToMonth; m++ {
+		for tt, amount := range totals[m] {
+			result[tt] += amount
+		}
+	}
+	return result
+}
+
+// ComparedMonthDays returns how many days of a month are compared with other months: those elapsed up to today
+// for the month in progress, 0 for a complete month, meaning all of them
+func ComparedMonthDays(year int, month time.Month, today time.Time) int {
+	if today.Year() == year && today.Month() == month {
+		return today.Day()
+	}
+	return 0
+}
+
+// MonthPeriod returns the first and last day of a month, or of its first days when given and the month is longer
+func MonthPeriod(year int, month time.Month, days int) (from, to time.Time) {
+	from = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	to = from.AddDate(0, 1, -1)
+	if days > 0 && days < to.Day() {
+		to = from.AddDate(0, 0, days-1)
+	}
+	return from, to
+}
+
+// WriteComparisonSummary writes a short comparison section, used at the bottom of the recaps
+func WriteComparisonSummary(text *strings.Builder, title string, comparison Comparison) {
+	if comparison.Expenses.Previous == 0 && comparison.Income.Previous == 0 {
+		return
+	}
+
+	text.WriteString(fmt.Sprintf("\n\n<b>%s:</b>\n", title))
+	text.WriteString(fmt.Sprintf("  💸 Expenses %s\n", FormatDelta(comparison.Expenses)))
+	text.WriteString(fmt.Sprintf("  💰 Income %s", FormatDelta(comparison.Income)))
+
+	if increases := comparison.BiggestIncreases(1); len(increases) > 0 {
+		d := increases[0]
+		text.WriteString(fmt.Sprintf("\n  📈 Biggest increase: %s %s %s", GetCategoryEmoji(d.Category), d.Category, FormatDelta(d.Delta)))
+	}
+	if decreases := comparison.BiggestDecreases(1); len(decreases) > 0 {
+		d := decreases[0]
+		text.WriteString(fmt.Sprintf("\n  📉 Biggest decrease: %s %s %s", GetCategoryEmoji(d.Category), d.Category, FormatDelta(d.Delta)))
+	}
+}
+
+// FormatDelta formats a change as "(+12.50€, +8.3%)"
+func FormatDelta(d Delta) string {
+	switch {
+	case d.Change == 0:
+		return "(=)"
+	case d.New:
+		return fmt.Sprintf("(%+.2f€, new)", d.Change)
+	default:
+		return fmt.Sprintf("(%+.2f€, %+.1f%%)", d.Change, d.Percent)
+	}
+}
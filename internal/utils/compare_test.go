@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"testing"
+	"time"
+)
+
+func TestNewDelta(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  float64
+		previous float64
+		want     Delta
+	}{
+		{
+			name:     "increase",
+			current:  150,
+			previous: 100,
+			want:     Delta{Current: 150, Previous: 100, Change: 50, Percent: 50},
+		},
+		{
+			name:     "decrease",
+			current:  75,
+			previous: 100,
+			want:     Delta{Current: 75, Previous: 100, Change: -25, Percent: -25},
+		},
+		{
+			name:     "negative balance improving",
+			current:  -50,
+			previous: -100,
+			want:     Delta{Current: -50, Previous: -100, Change: 50, Percent: 50},
+		},
+		{
+			name:     "negative balance worsening",
+			current:  -150,
+			previous: -100,
+			want:     Delta{Current: -150, Previous: -100, Change: -50, Percent: -50},
+		},
+		{
+			name:     "new amount",
+			current:  40,
+			previous: 0,
+			want:     Delta{Current: 40, Previous: 0, Change: 40, New: true},
+		},
+		{
+			name: "both zero",
+			want: Delta{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewDelta(tt.current, tt.previous); got != tt.want {
+				t.Errorf("NewDelta() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	current := map[model.TransactionType]map[model.TransactionCategory]float64{
+		model.TypeExpense: {
+			model.CategoryGrocery:   300,
+			model.CategoryEatingOut: 50,
+			model.CategoryTravel:    400,
+		},
+		model.TypeIncome: {
+			model.CategorySalary: 2000,
+		},
+	}
+	previous := map[model.TransactionType]map[model.TransactionCategory]float64{
+		model.TypeExpense: {
+			model.CategoryGrocery:   250,
+			model.CategoryEatingOut: 150,
+			model.CategoryCar:       80,
+		},
+		model.TypeIncome: {
+			model.CategorySalary: 2000,
+		},
+	}
+
+	c := ComparePeriods(current, previous)
+
+	if c.Expenses.Current != 750 || c.Expenses.Previous != 480 {
+		t.Errorf("Expenses = %+v, want current 750 and previous 480", c.Expenses)
+	}
+	if c.Income.Change != 0 {
+		t.Errorf("Income change = %v, want 0", c.Income.Change)
+	}
+	if c.Balance.Current != 1250 || c.Balance.Previous != 1520 {
+		t.Errorf("Balance = %+v, want current 1250 and previous 1520", c.Balance)
+	}
+
+	// Travel (+400), EatingOut (-100), Car (-80), Grocery (+50), Salary (0)
+	if len(c.Categories) != 5 {
+		t.Fatalf("Categories has %d entries, want 5", len(c.Categories))
+	}
+	if c.Categories[0].Category != model.CategoryTravel || !c.Categories[0].New {
+		t.Errorf("Categories[0] = %+v, want a new Travel entry", c.Categories[0])
+	}
+
+	increases := c.BiggestIncreases(3)
+	if len(increases) != 2 || increases[0].Category != model.CategoryTravel || increases[1].Category != model.CategoryGrocery {
+		t.Errorf("BiggestIncreases() = %+v", increases)
+	}
+
+	decreases := c.BiggestDecreases(1)
+	if len(decreases) != 1 || decreases[0].Category != model.CategoryEatingOut {
+		t.Errorf("BiggestDecreases() = %+v", decreases)
+	}
+}
+
+func TestSumMonthlyTotals(t *testing.T) {
+	totals := map[int]map[model.TransactionType]float64{
+		1: {model.TypeExpense: 100, model.TypeIncome: 1000},
+		2: {model.TypeExpense: 200},
+		5: {model.TypeExpense: 500},
+	}
+
+	got := SumMonthlyTotals(totals, 4)
+	if got[model.TypeExpense] != 300 || got[model.TypeIncome] != 1000 {
+		t.Errorf("SumMonthlyTotals() = %v", got)
+	}
+}
+
+func TestComparedMonthDays(t *testing.T) {
+	today := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+
+	if got := ComparedMonthDays(2025, time.March, today); got != 12 {
+		t.Errorf("ComparedMonthDays() of the month in progress = %d, want 12", got)
+	}
+	if got := ComparedMonthDays(2025, time.February, today); got != 0 {
+		t.Errorf("ComparedMonthDays() of a complete month = %d, want 0", got)
+	}
+	if got := ComparedMonthDays(2024, time.March, today); got != 0 {
+		t.Errorf("ComparedMonthDays() of the same month last year = %d, want 0", got)
+	}
+}
+
+func TestMonthPeriod(t *testing.T) {
+	tests := []struct {
+		name   string
+		month  time.Month
+		days   int
+		wantTo time.Time
+	}{
+		{name: "whole month", month: time.February, days: 0, wantTo: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "first days", month: time.February, days: 12, wantTo: time.Date(2025, 2, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "more days than the month has", month: time.February, days: 31, wantTo: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := MonthPeriod(2025, tt.month, tt.days)
+			if want := time.Date(2025, tt.month, 1, 0, 0, 0, 0, time.UTC); !from.Equal(want) {
+				t.Errorf("MonthPeriod() from = %v, want %v", from, want)
+			}
+			if !to.Equal(tt.wantTo) {
+				t.Errorf("MonthPeriod() to = %v, want %v", to, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	tests := []struct {
+		name  string
+		delta Delta
+		want  string
+	}{
+		{"unchanged", NewDelta(10, 10), "(=)"},
+		{"new", NewDelta(12.5, 0), "(+12.50€, new)"},
+		{"increase", NewDelta(110, 100), "(+10.00€, +10.0%)"},
+		{"decrease", NewDelta(75, 100), "(-25.00€, -25.0%)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDelta(tt.delta); got != tt.want {
+				t.Errorf("FormatDelta() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
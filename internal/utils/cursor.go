@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor can't be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of the last item of a page: the field the listing is sorted by,
+// the item's value of that field and its ID
+type Cursor struct {
+	Sort  string
+	Value string
+	ID    int64
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c Cursor) Encode() string {
+	raw := c.Sort + ":" + c.Value + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor built by Cursor.Encode
+func DecodeCursor(cursor string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	sort, rest, ok := strings.Cut(string(raw), ":")
+	if !ok || sort == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	// The value could contain the separator, the ID can't
+	i := strings.LastIndex(rest, ":")
+	if i < 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(rest[i+1:], 10, 64)
+	if err != nil || id <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{Sort: sort, Value: rest[:i], ID: id}, nil
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []Cursor{
+		{Sort: "date", Value: "2025-05-14", ID: 42},
+		{Sort: "amount", Value: "12.5", ID: 7},
+		{Sort: "category", Value: "Eating Out", ID: 1},
+		{Sort: "category", Value: "with:colon", ID: 3},
+		{Sort: "date", Value: "", ID: 9},
+	}
+
+	for _, want := range tests {
+		t.Run(want.Sort+" "+want.Value, func(t *testing.T) {
+			encoded := want.Encode()
+			got, err := DecodeCursor(encoded)
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q) error = %v", encoded, err)
+			}
+			if got != want {
+				t.Errorf("DecodeCursor() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("date")),
+		base64.RawURLEncoding.EncodeToString([]byte("date:2025-05-14")),
+		base64.RawURLEncoding.EncodeToString([]byte(":2025-05-14:1")),
+		base64.RawURLEncoding.EncodeToString([]byte("date:2025-05-14:0")),
+		base64.RawURLEncoding.EncodeToString([]byte("date:2025-05-14:x")),
+	}
+
+	for _, cursor := range tests {
+		t.Run(cursor, func(t *testing.T) {
+			if _, err := DecodeCursor(cursor); err != ErrInvalidCursor {
+				t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", cursor, err)
+			}
+		})
+	}
+}
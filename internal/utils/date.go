@@ -17,6 +17,11 @@ import (
 // - d m (single digit day/month, uses current year, same separators)
 // - Any combination of the above (d-mm-yyyy, dd/m/yy, etc.)
 func ParseDate(dateStr string) (time.Time, error) {
+	return ParseDateInYear(dateStr, time.Now().Year())
+}
+
+// ParseDateInYear parses a date like ParseDate, using currentYear when the year is omitted, e.g. the user's current year
+func ParseDateInYear(dateStr string, currentYear int) (time.Time, error) {
 	// Trim spaces and normalize the string
 	dateStr = strings.TrimSpace(dateStr)
 
@@ -52,7 +57,6 @@ func ParseDate(dateStr string) (time.Time, error) {
 
 	// Check if year is present
 	var year int
-	currentYear := time.Now().Year()
 
 	if len(matches) > 3 && matches[3] != "" {
 		year, err = strconv.Atoi(matches[3])
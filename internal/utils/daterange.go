@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrPaydayUnknown is returned when a range refers to the payday but none is known
+var ErrPaydayUnknown = errors.New("no payday found")
+
+// DateRange is an inclusive range of days
+type DateRange struct {
+	From  time.Time
+	To    time.Time
+	Label string
+}
+
+// Days returns the number of days in the range
+func (r DateRange) Days() int {
+	return int(r.To.Sub(r.From).Hours()/24) + 1
+}
+
+// Until returns the range ending on a day at the latest, e.g. today for a period still in progress
+func (r DateRange) Until(day time.Time) DateRange {
+	if r.To.After(day) {
+		r.To = day
+	}
+	return r
+}
+
+var (
+	rangeRelativePattern = regexp.MustCompile(`^(this|current|last|previous|past) (week|month|quarter|year)$`)
+	rangeRollingPattern  = regexp.MustCompile(`^(?:last|past) (\d+) (days?|weeks?|months?|years?)$`)
+	rangeQuarterPattern  = regexp.MustCompile(`^q([1-4])(?:\s+(\d{2}|\d{4}))?$`)
+	rangeBetweenPattern  = regexp.MustCompile(`^(?:from\s+)?(.+?)\s+(?:to|until|-)\s+(.+)$`)
+	rangeSincePattern    = regexp.MustCompile(`^since\s+(.+)$`)
+	rangeMonthPattern    = regexp.MustCompile(`^([a-z]+)(?:\s+(\d{4}))?$`)
+	rangeYearPattern     = regexp.MustCompile(`^(\d{4})$`)
+)
+
+// ParseDateRange parses a natural language date range relative to now.
+// Supported formats include:
+// - today, yesterday
+// - this/last week, month, quarter, year
+// - last N days/weeks/months/years (rolling, ending today)
+// - Q1-Q4 with optional year (e.g. "Q2 2025")
+// - month names with optional year (e.g. "march", "march 2024")
+// - a year (e.g. "2024")
+// - from X to Y, X to Y, since X, where X and Y are dates accepted by ParseDate, today, yesterday or payday
+// - a single date accepted by ParseDate
+//
+// payday is the date of the last salary, used by "since payday"; it can be nil if unknown.
+func ParseDateRange(text string, now time.Time, payday *time.Time) (DateRange, error) {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if text == "" {
+		return DateRange{}, fmt.Errorf("empty date range")
+	}
+
+	if text == "today" || text == "yesterday" {
+		day, _ := parseRangeEndpoint(text, today, payday)
+		return DateRange{From: day, To: day, Label: strings.ToUpper(text[:1]) + text[1:]}, nil
+	}
+
+	if m := rangeRelativePattern.FindStringSubmatch(text); m != nil {
+		previous := m[1] == "last" || m[1] == "previous" || m[1] == "past"
+		return relativeRange(m[2], previous, today), nil
+	}
+
+	if m := rangeRollingPattern.FindStringSubmatch(text); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n < 1 {
+			return DateRange{}, fmt.Errorf("invalid amount: %s", m[1])
+		}
+
+		var from time.Time
+		switch strings.TrimSuffix(m[2], "s") {
+		case "day":
+			from = today.AddDate(0, 0, -n)
+		case "week":
+			from = today.AddDate(0, 0, -7*n)
+		case "month":
+			from = today.AddDate(0, -n, 0)
+		case "year":
+			from = today.AddDate(-n, 0, 0)
+		}
+
+		return DateRange{From: from.AddDate(0, 0, 1), To: today, Label: fmt.Sprintf("Last %d %s", n, m[2])}, nil
+	}
+
+	if m := rangeQuarterPattern.FindStringSubmatch(text); m != nil {
+		q, _ := strconv.Atoi(m[1])
+		year := today.Year()
+		if m[2] != "" {
+			year = expandYear(m[2])
+		}
+		from := time.Date(year, time.Month((q-1)*3+1), 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{From: from, To: from.AddDate(0, 3, -1), Label: fmt.Sprintf("Q%d %d", q, year)}, nil
+	}
+
+	if m := rangeSincePattern.FindStringSubmatch(text); m != nil {
+		from, err := parseRangeEndpoint(m[1], today, payday)
+		if err != nil {
+			return DateRange{}, err
+		}
+		return validateRange(DateRange{From: from, To: today, Label: fmt.Sprintf("Since %s", from.Format("02 Jan 2006"))})
+	}
+
+	if m := rangeBetweenPattern.FindStringSubmatch(text); m != nil {
+		from, err := parseRangeEndpoint(m[1], today, payday)
+		if err != nil {
+			return DateRange{}, err
+		}
+		to, err := parseRangeEndpoint(m[2], today, payday)
+		if err != nil {
+			return DateRange{}, err
+		}
+		return validateRange(DateRange{From: from, To: to, Label: fmt.Sprintf("%s - %s", from.Format("02 Jan 2006"), to.Format("02 Jan 2006"))})
+	}
+
+	if m := rangeYearPattern.FindStringSubmatch(text); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return DateRange{From: from, To: from.AddDate(1, 0, -1), Label: fmt.Sprintf("Year %d", year)}, nil
+	}
+
+	if m := rangeMonthPattern.FindStringSubmatch(text); m != nil {
+		if month, ok := parseMonthName(m[1]); ok {
+			year := today.Year()
+			if m[2] != "" {
+				year, _ = strconv.Atoi(m[2])
+			}
+			from := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+			return DateRange{From: from, To: from.AddDate(0, 1, -1), Label: from.Format("January 2006")}, nil
+		}
+	}
+
+	day, err := ParseDateInYear(text, today.Year())
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid date range: %s", text)
+	}
+	return DateRange{From: day, To: day, Label: day.Format("02 Jan 2006")}, nil
+}
+
+// relativeRange returns the current or previous calendar week (Monday to Sunday), month, quarter or year
+func relativeRange(unit string, previous bool, today time.Time) DateRange {
+	prefix := "This"
+	if previous {
+		prefix = "Last"
+	}
+
+	var from, to time.Time
+	switch unit {
+	case "week":
+		weekday := int(today.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		from = today.AddDate(0, 0, -(weekday - 1))
+		if previous {
+			from = from.AddDate(0, 0, -7)
+		}
+		to = from.AddDate(0, 0, 6)
+	case "month":
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if previous {
+			from = from.AddDate(0, -1, 0)
+		}
+		to = from.AddDate(0, 1, -1)
+	case "quarter":
+		from = time.Date(today.Year(), (today.Month()-1)/3*3+1, 1, 0, 0, 0, 0, time.UTC)
+		if previous {
+			from = from.AddDate(0, -3, 0)
+		}
+		to = from.AddDate(0, 3, -1)
+	case "year":
+		from = time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		if previous {
+			from = from.AddDate(-1, 0, 0)
+		}
+		to = from.AddDate(1, 0, -1)
+	}
+
+	return DateRange{From: from, To: to, Label: fmt.Sprintf("%s %s", prefix, unit)}
+}
+
+// parseRangeEndpoint parses a single boundary of a date range
+func parseRangeEndpoint(text string, today time.Time, payday *time.Time) (time.Time, error) {
+	switch strings.TrimSpace(text) {
+	case "today", "now":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "payday", "last payday", "salary":
+		if payday == nil {
+			return time.Time{}, ErrPaydayUnknown
+		}
+		return time.Date(payday.Year(), payday.Month(), payday.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return ParseDateInYear(text, today.Year())
+}
+
+func validateRange(r DateRange) (DateRange, error) {
+	if r.To.Before(r.From) {
+		return DateRange{}, fmt.Errorf("invalid date range: %s is after %s", r.From.Format("02-01-2006"), r.To.Format("02-01-2006"))
+	}
+	return r, nil
+}
+
+// MentionsPayday reports whether the range text refers to the payday
+func MentionsPayday(text string) bool {
+	text = strings.ToLower(text)
+	return strings.Contains(text, "payday") || strings.Contains(text, "salary")
+}
+
+func parseMonthName(name string) (time.Month, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	for m := time.January; m <= time.December; m++ {
+		full := strings.ToLower(m.String())
+		if strings.HasPrefix(full, name) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+func expandYear(s string) int {
+	year, _ := strconv.Atoi(s)
+	if year < 100 {
+		year += 2000
+	}
+	return year
+}
@@ -0,0 +1,203 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDateRange(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 5, 14, 18, 30, 0, 0, time.UTC)
+	payday := time.Date(2025, 4, 27, 0, 0, 0, 0, time.UTC)
+
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "today",
+			input:    "today",
+			wantFrom: day(2025, 5, 14),
+			wantTo:   day(2025, 5, 14),
+		},
+		{
+			name:     "yesterday",
+			input:    "Yesterday",
+			wantFrom: day(2025, 5, 13),
+			wantTo:   day(2025, 5, 13),
+		},
+		{
+			name:     "last 30 days",
+			input:    "last 30 days",
+			wantFrom: day(2025, 4, 15),
+			wantTo:   day(2025, 5, 14),
+		},
+		{
+			name:     "past 2 weeks",
+			input:    "past  2 weeks",
+			wantFrom: day(2025, 5, 1),
+			wantTo:   day(2025, 5, 14),
+		},
+		{
+			name:     "this week starts on monday",
+			input:    "this week",
+			wantFrom: day(2025, 5, 12),
+			wantTo:   day(2025, 5, 18),
+		},
+		{
+			name:     "last month",
+			input:    "last month",
+			wantFrom: day(2025, 4, 1),
+			wantTo:   day(2025, 4, 30),
+		},
+		{
+			name:     "this quarter",
+			input:    "this quarter",
+			wantFrom: day(2025, 4, 1),
+			wantTo:   day(2025, 6, 30),
+		},
+		{
+			name:     "last year",
+			input:    "last year",
+			wantFrom: day(2024, 1, 1),
+			wantTo:   day(2024, 12, 31),
+		},
+		{
+			name:     "quarter with year",
+			input:    "Q2 2024",
+			wantFrom: day(2024, 4, 1),
+			wantTo:   day(2024, 6, 30),
+		},
+		{
+			name:     "quarter without year",
+			input:    "q1",
+			wantFrom: day(2025, 1, 1),
+			wantTo:   day(2025, 3, 31),
+		},
+		{
+			name:     "from to",
+			input:    "from 01-03 to 15-04",
+			wantFrom: day(2025, 3, 1),
+			wantTo:   day(2025, 4, 15),
+		},
+		{
+			name:     "to without from",
+			input:    "01/12/2024 to today",
+			wantFrom: day(2024, 12, 1),
+			wantTo:   day(2025, 5, 14),
+		},
+		{
+			name:     "since payday",
+			input:    "since payday",
+			wantFrom: day(2025, 4, 27),
+			wantTo:   day(2025, 5, 14),
+		},
+		{
+			name:     "since date",
+			input:    "since 01-05",
+			wantFrom: day(2025, 5, 1),
+			wantTo:   day(2025, 5, 14),
+		},
+		{
+			name:     "month name",
+			input:    "february",
+			wantFrom: day(2025, 2, 1),
+			wantTo:   day(2025, 2, 28),
+		},
+		{
+			name:     "short month name with year",
+			input:    "feb 2024",
+			wantFrom: day(2024, 2, 1),
+			wantTo:   day(2024, 2, 29),
+		},
+		{
+			name:     "year",
+			input:    "2023",
+			wantFrom: day(2023, 1, 1),
+			wantTo:   day(2023, 12, 31),
+		},
+		{
+			name:     "single date",
+			input:    "10-05",
+			wantFrom: day(2025, 5, 10),
+			wantTo:   day(2025, 5, 10),
+		},
+		{
+			name:    "reversed range",
+			input:   "from 15-04 to 01-03",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			input:   "  ",
+			wantErr: true,
+		},
+		{
+			name:    "gibberish",
+			input:   "whenever",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateRange(tt.input, now, &payday)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.From.Equal(tt.wantFrom) || !got.To.Equal(tt.wantTo) {
+				t.Errorf("ParseDateRange() = %s - %s, want %s - %s",
+					got.From.Format("2006-01-02"), got.To.Format("2006-01-02"),
+					tt.wantFrom.Format("2006-01-02"), tt.wantTo.Format("2006-01-02"))
+			}
+			if got.Label == "" {
+				t.Errorf("ParseDateRange() returned an empty label")
+			}
+		})
+	}
+}
+
+func TestParseDateRangeWithoutPayday(t *testing.T) {
+	_, err := ParseDateRange("since payday", time.Now(), nil)
+	if !errors.Is(err, ErrPaydayUnknown) {
+		t.Errorf("ParseDateRange() error = %v, want ErrPaydayUnknown", err)
+	}
+}
+
+func TestDateRangeDays(t *testing.T) {
+	r := DateRange{
+		From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	}
+	if got := r.Days(); got != 31 {
+		t.Errorf("Days() = %d, want 31", got)
+	}
+}
+
+func TestDateRangeUntil(t *testing.T) {
+	r := DateRange{
+		From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	if got := r.Until(today); !got.To.Equal(today) || got.Days() != 69 {
+		t.Errorf("Until() = %v to %v, want until %v (69 days)", got.From, got.To, today)
+	}
+
+	later := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	if got := r.Until(later); !got.To.Equal(r.To) {
+		t.Errorf("Until() a later day = %v, want %v", got.To, r.To)
+	}
+}
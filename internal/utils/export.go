@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// WriteTransactionsCSV writes the transactions as CSV, with a header row
+func WriteTransactionsCSV(w io.Writer, transactions []model.Transaction) error {
+	writer := csv.NewWriter(w)
+
+	// Write header
+	header := []string{
+		"tg_id",
+		"date",
+		"type",
+		"category",
+		"amount",
+		"currency",
+		"description",
+		"created_at",
+		"updated_at",
+	}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write transactions
+	for _, t := range transactions {
+		record := []string{
+			strconv.FormatInt(t.TgID, 10),
+			t.Date.Format("2006-01-02"),
+			string(t.Type),
+			string(t.Category),
+			fmt.Sprintf("%.2f", t.Amount),
+			string(t.Currency),
+			t.Description,
+			t.CreatedAt.Format("2006-01-02 15:04"),
+			t.UpdatedAt.Format("2006-01-02 15:04"),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("CSV writer error: %w", err)
+	}
+
+	return nil
+}
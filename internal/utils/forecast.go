@@ -0,0 +1,342 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ForecastHistoryMonths is the number of past months used to build a forecast
+const ForecastHistoryMonths = 3
+
+// forecastZ is the z-score of the confidence range (80%)
+const forecastZ = 1.28
+
+// RecurringTransaction is a transaction expected every month (e.g. rent, subscriptions, salary)
+type RecurringTransaction struct {
+	Type        model.TransactionType
+	Category    model.TransactionCategory
+	Description string
+	Amount      float64
+	Day         int
+}
+
+// Forecast is the projection of a month's expenses and balance
+type Forecast struct {
+	Month        time.Time
+	DaysLeft     int
+	SpentToDate  float64
+	IncomeToDate float64
+
+	ExpensesLow  float64
+	Expenses     float64
+	ExpensesHigh float64
+	Income       float64
+
+	BalanceLow  float64
+	Balance     float64
+	BalanceHigh float64
+
+	// PendingRecurring lists the recurring transactions not yet recorded this month
+	PendingRecurring []RecurringTransaction
+	// HistoryMonths is the number of past months with data the forecast is based on
+	HistoryMonths int
+}
+
+// ForecastMonth projects the end-of-month expenses and balance for the month of now.
+// current are the transactions of the month so far, history those of the previous months.
+// The projection adds to the spend-to-date:
+//   - the recurring transactions (same category and description in most of the past months) not yet recorded
+//   - the variable spending expected for the remaining days, based on the weekday averages of the past months
+//     blended with the current month's pace
+//
+// The confidence range comes from the variability of the past daily spending.
+func ForecastMonth(now time.Time, current, history []model.Transaction) Forecast {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	daysInMonth := monthStart.AddDate(0, 1, -1).Day()
+	elapsed := now.Day()
+
+	f := Forecast{
+		Month:    monthStart,
+		DaysLeft: daysInMonth - elapsed,
+	}
+
+	// --- RECURRING TRANSACTIONS ---
+	recurring := findRecurring(history, monthStart)
+	f.HistoryMonths = countMonths(history, monthStart)
+
+	seen := make(map[string]bool)
+	var variableToDate float64
+	for _, t := range current {
+		seen[recurringKey(t)] = true
+		if t.Type == model.TypeIncome {
+			f.IncomeToDate += t.Amount
+			continue
+		}
+		f.SpentToDate += t.Amount
+		if _, ok := recurring[recurringKey(t)]; !ok {
+			variableToDate += t.Amount
+		}
+	}
+
+	var pendingExpenses, pendingIncome float64
+	for key, r := range recurring {
+		if seen[key] {
+			continue
+		}
+		f.PendingRecurring = append(f.PendingRecurring, r)
+		if r.Type == model.TypeIncome {
+			pendingIncome += r.Amount
+		} else {
+			pendingExpenses += r.Amount
+		}
+	}
+	sort.Slice(f.PendingRecurring, func(i, j int) bool {
+		if f.PendingRecurring[i].Day != f.PendingRecurring[j].Day {
+			return f.PendingRecurring[i].Day < f.PendingRecurring[j].Day
+		}
+		return f.PendingRecurring[i].Description < f.PendingRecurring[j].Description
+	})
+
+	// --- VARIABLE SPENDING ---
+	var remaining, stddev float64
+	if f.DaysLeft > 0 {
+		currentDaily := variableToDate / float64(elapsed)
+
+		daily := dailyVariableSpending(history, recurring, monthStart)
+		if len(daily) == 0 {
+			// No history: follow the current pace, with the current month's variability
+			remaining = currentDaily * float64(f.DaysLeft)
+			tomorrow := monthStart.AddDate(0, 0, elapsed)
+			stddev = stdDev(mapValues(dailyVariableSpending(current, recurring, tomorrow))) * math.Sqrt(float64(f.DaysLeft))
+		} else {
+			var weekdaySum [7]float64
+			var weekdayCount [7]int
+			for day, amount := range daily {
+				weekdaySum[day.Weekday()] += amount
+				weekdayCount[day.Weekday()]++
+			}
+
+			var historical float64
+			for d := elapsed + 1; d <= daysInMonth; d++ {
+				wd := time.Date(now.Year(), now.Month(), d, 0, 0, 0, 0, time.UTC).Weekday()
+				if weekdayCount[wd] > 0 {
+					historical += weekdaySum[wd] / float64(weekdayCount[wd])
+				}
+			}
+
+			// The further into the month, the more the current pace counts
+			w := float64(elapsed) / float64(daysInMonth)
+			remaining = w*currentDaily*float64(f.DaysLeft) + (1-w)*historical
+
+			stddev = stdDev(mapValues(daily)) * math.Sqrt(float64(f.DaysLeft))
+		}
+	}
+
+	f.Expenses = f.SpentToDate + pendingExpenses + remaining
+	f.ExpensesLow = f.SpentToDate + pendingExpenses + math.Max(0, remaining-forecastZ*stddev)
+	f.ExpensesHigh = f.SpentToDate + pendingExpenses + remaining + forecastZ*stddev
+	f.Income = f.IncomeToDate + pendingIncome
+
+	f.Balance = f.Income - f.Expenses
+	f.BalanceLow = f.Income - f.ExpensesHigh
+	f.BalanceHigh = f.Income - f.ExpensesLow
+
+	return f
+}
+
+func recurringKey(t model.Transaction) string {
+	return string(t.Type) + "|" + string(t.Category) + "|" + strings.ToLower(strings.TrimSpace(t.Description))
+}
+
+// countMonths returns the number of months with at least one transaction before the given month
+func countMonths(transactions []model.Transaction, before time.Time) int {
+	months := make(map[string]bool)
+	for _, t := range transactions {
+		if t.Date.Before(before) {
+			months[t.Date.Format("2006-01")] = true
+		}
+	}
+	return len(months)
+}
+
+// findRecurring returns the transactions found once a month in at least 3/4 of the months with data (and at least 2).
+// Transactions repeated within a month (e.g. a daily coffee) are variable spending, not recurring.
+func findRecurring(history []model.Transaction, before time.Time) map[string]RecurringTransaction {
+	months := countMonths(history, before)
+	if months < 2 {
+		return nil
+	}
+	threshold := int(math.Ceil(float64(months) * 0.75))
+	if threshold < 2 {
+		threshold = 2
+	}
+
+	type occurrence struct {
+		amounts map[string]float64
+		days    []int
+		sample  model.Transaction
+	}
+	occurrences := make(map[string]*occurrence)
+	for _, t := range history {
+		if !t.Date.Before(before) {
+			continue
+		}
+		key := recurringKey(t)
+		o, ok := occurrences[key]
+		if !ok {
+			o = &occurrence{amounts: make(map[string]float64), sample: t}
+			occurrences[key] = o
+		}
+		o.amounts[t.Date.Format("2006-01")] += t.Amount
+		o.days = append(o.days, t.Date.Day())
+	}
+
+	result := make(map[string]RecurringTransaction)
+	for key, o := range occurrences {
+		if len(o.amounts) < threshold || len(o.days) != len(o.amounts) {
+			continue
+		}
+		amounts := make([]float64, 0, len(o.amounts))
+		for _, amount := range o.amounts {
+			amounts = append(amounts, amount)
+		}
+		days := make([]float64, len(o.days))
+		for i, d := range o.days {
+			days[i] = float64(d)
+		}
+		result[key] = RecurringTransaction{
+			Type:        o.sample.Type,
+			Category:    o.sample.Category,
+			Description: o.sample.Description,
+			Amount:      median(amounts),
+			Day:         int(median(days)),
+		}
+	}
+	return result
+}
+
+// dailyVariableSpending returns the non-recurring expenses of each day (including days without expenses)
+// from the first to the last month with data, stopping before the given month
+func dailyVariableSpending(transactions []model.Transaction, recurring map[string]RecurringTransaction, before time.Time) map[time.Time]float64 {
+	var first time.Time
+	daily := make(map[time.Time]float64)
+	for _, t := range transactions {
+		if !t.Date.Before(before) {
+			continue
+		}
+		if first.IsZero() || t.Date.Before(first) {
+			first = t.Date
+		}
+		if t.Type != model.TypeExpense {
+			continue
+		}
+		if _, ok := recurring[recurringKey(t)]; ok {
+			continue
+		}
+		day := time.Date(t.Date.Year(), t.Date.Month(), t.Date.Day(), 0, 0, 0, 0, time.UTC)
+		daily[day] += t.Amount
+	}
+
+	if first.IsZero() {
+		return nil
+	}
+
+	// Fill the days without expenses, from the start of the first month
+	last := before.AddDate(0, 0, -1)
+	for day := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if _, ok := daily[day]; !ok {
+			daily[day] = 0
+		}
+	}
+	return daily
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func mapValues(m map[time.Time]float64) []float64 {
+	values := make([]float64, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// stdDev returns the sample standard deviation
+func stdDev(amounts []float64) float64 {
+	if len(amounts) < 2 {
+		return 0
+	}
+
+	var mean float64
+	for _, a := range amounts {
+		mean += a
+	}
+	mean /= float64(len(amounts))
+
+	var variance float64
+	for _, a := range amounts {
+		variance += (a - mean) * (a - mean)
+	}
+	return math.Sqrt(variance / float64(len(amounts)-1))
+}
+
+// maxForecastRecurring is the number of pending recurring transactions listed in the forecast
+const maxForecastRecurring = 5
+
+// FormatForecast formats the end-of-month projection of the current month
+func FormatForecast(f Forecast) string {
+	var text strings.Builder
+
+	text.WriteString(fmt.Sprintf("🔮 <b>%s Forecast</b>\n", f.Month.Format("January")))
+	if f.DaysLeft == 0 {
+		text.WriteString("<i>Last day of the month, no projection left to make.</i>")
+		return text.String()
+	}
+
+	text.WriteString(fmt.Sprintf("💸 <b>Expected Expenses:</b> %.2f€ <i>(%.0f€ - %.0f€)</i>\n", f.Expenses, f.ExpensesLow, f.ExpensesHigh))
+	if f.Income > 0 {
+		text.WriteString(fmt.Sprintf("💰 <b>Expected Income:</b> %.2f€\n", f.Income))
+	}
+
+	var balanceEmoji string
+	if f.Balance >= 0 {
+		balanceEmoji = "✅"
+	} else {
+		balanceEmoji = "❌"
+	}
+	text.WriteString(fmt.Sprintf("%s <b>Expected Balance:</b> %.2f€ <i>(%.0f€ - %.0f€)</i>\n", balanceEmoji, f.Balance, f.BalanceLow, f.BalanceHigh))
+
+	if len(f.PendingRecurring) > 0 {
+		text.WriteString("\n<b>Still Expected:</b>\n")
+		for i, r := range f.PendingRecurring {
+			if i == maxForecastRecurring {
+				text.WriteString(fmt.Sprintf("  <i>...and %d more</i>\n", len(f.PendingRecurring)-maxForecastRecurring))
+				break
+			}
+			text.WriteString(fmt.Sprintf("  %s %s: %.2f€ <i>(around the %d)</i>\n", GetCategoryEmoji(r.Category), r.Description, r.Amount, r.Day))
+		}
+	}
+
+	if f.HistoryMonths == 0 {
+		text.WriteString("\n<i>Based on this month's spending only, it will get better as you track more months.</i>")
+	} else {
+		text.WriteString(fmt.Sprintf("\n<i>%d days left, based on your last %d months.</i>", f.DaysLeft, f.HistoryMonths))
+	}
+
+	return text.String()
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func forecastTx(date time.Time, tt model.TransactionType, cat model.TransactionCategory, desc string, amount float64) model.Transaction {
+	return model.Transaction{Date: date, Type: tt, Category: cat, Description: desc, Amount: amount}
+}
+
+func TestForecastMonth(t *testing.T) {
+	day := func(m time.Month, d int) time.Time {
+		return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Three months of history: rent on the 1st, salary on the 27th, 10€ of groceries every day
+	var history []model.Transaction
+	for _, m := range []time.Month{time.February, time.March, time.April} {
+		history = append(history,
+			forecastTx(day(m, 1), model.TypeExpense, model.CategoryHouse, "Rent", 800),
+			forecastTx(day(m, 27), model.TypeIncome, model.CategorySalary, "Salary", 2500),
+		)
+		for d := 1; d <= day(m, 1).AddDate(0, 1, -1).Day(); d++ {
+			history = append(history, forecastTx(day(m, d), model.TypeExpense, model.CategoryGrocery, "Shop", 10))
+		}
+	}
+
+	// May 10th: rent paid, 10€ a day so far
+	now := time.Date(2025, 5, 10, 12, 0, 0, 0, time.UTC)
+	current := []model.Transaction{
+		forecastTx(day(time.May, 1), model.TypeExpense, model.CategoryHouse, "rent ", 800),
+	}
+	for d := 1; d <= 10; d++ {
+		current = append(current, forecastTx(day(time.May, d), model.TypeExpense, model.CategoryGrocery, "Shop", 10))
+	}
+
+	f := ForecastMonth(now, current, history)
+
+	if f.DaysLeft != 21 {
+		t.Errorf("DaysLeft = %d, want 21", f.DaysLeft)
+	}
+	if f.HistoryMonths != 3 {
+		t.Errorf("HistoryMonths = %d, want 3", f.HistoryMonths)
+	}
+	if f.SpentToDate != 900 {
+		t.Errorf("SpentToDate = %v, want 900", f.SpentToDate)
+	}
+
+	// Only the salary is still expected
+	if len(f.PendingRecurring) != 1 || f.PendingRecurring[0].Category != model.CategorySalary || f.PendingRecurring[0].Day != 27 {
+		t.Fatalf("PendingRecurring = %+v, want the salary on the 27th", f.PendingRecurring)
+	}
+
+	// 900 so far + 21 days at 10€
+	if math.Abs(f.Expenses-1110) > 0.01 {
+		t.Errorf("Expenses = %v, want 1110", f.Expenses)
+	}
+	if math.Abs(f.Income-2500) > 0.01 {
+		t.Errorf("Income = %v, want 2500", f.Income)
+	}
+	if math.Abs(f.Balance-1390) > 0.01 {
+		t.Errorf("Balance = %v, want 1390", f.Balance)
+	}
+	if f.ExpensesLow > f.Expenses || f.ExpensesHigh < f.Expenses {
+		t.Errorf("Expenses range %v - %v doesn't contain %v", f.ExpensesLow, f.ExpensesHigh, f.Expenses)
+	}
+	if f.BalanceLow > f.Balance || f.BalanceHigh < f.Balance {
+		t.Errorf("Balance range %v - %v doesn't contain %v", f.BalanceLow, f.BalanceHigh, f.Balance)
+	}
+}
+
+func TestForecastMonthWithoutHistory(t *testing.T) {
+	now := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	current := []model.Transaction{
+		forecastTx(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), model.TypeExpense, model.CategoryGrocery, "Shop", 50),
+		forecastTx(time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC), model.TypeExpense, model.CategoryEatingOut, "Pizza", 50),
+	}
+
+	f := ForecastMonth(now, current, nil)
+
+	// 100€ in 10 days, 20 days left at the same pace
+	if math.Abs(f.Expenses-300) > 0.01 {
+		t.Errorf("Expenses = %v, want 300", f.Expenses)
+	}
+	if len(f.PendingRecurring) != 0 {
+		t.Errorf("PendingRecurring = %+v, want none", f.PendingRecurring)
+	}
+	if f.ExpensesLow < f.SpentToDate {
+		t.Errorf("ExpensesLow = %v, want at least the spend to date", f.ExpensesLow)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if got := median([]float64{3, 1, 2}); got != 2 {
+		t.Errorf("median() = %v, want 2", got)
+	}
+	if got := median([]float64{4, 1, 2, 3}); got != 2.5 {
+		t.Errorf("median() = %v, want 2.5", got)
+	}
+}
+
+func TestFormatForecast(t *testing.T) {
+	may := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	pending := make([]RecurringTransaction, maxForecastRecurring+2)
+	for i := range pending {
+		pending[i] = RecurringTransaction{Type: model.TypeExpense, Category: model.CategoryHouse, Description: "Rent", Amount: 800, Day: 1}
+	}
+
+	tests := []struct {
+		name     string
+		forecast Forecast
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "last day of the month",
+			forecast: Forecast{Month: may, Expenses: 100},
+			want:     []string{"May Forecast", "Last day of the month"},
+			notWant:  []string{"Expected Expenses"},
+		},
+		{
+			name:     "no history",
+			forecast: Forecast{Month: may, DaysLeft: 10, Expenses: 100, Balance: -100},
+			want:     []string{"Expected Expenses:</b> 100.00€", "❌ <b>Expected Balance:</b> -100.00€", "this month's spending only"},
+			notWant:  []string{"Expected Income", "Still Expected"},
+		},
+		{
+			name:     "pending recurring over the limit",
+			forecast: Forecast{Month: may, DaysLeft: 10, Income: 2500, Balance: 1500, PendingRecurring: pending, HistoryMonths: 3},
+			want:     []string{"Expected Income:</b> 2500.00€", "✅ <b>Expected Balance", "...and 2 more", "based on your last 3 months"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatForecast(tt.forecast)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("FormatForecast() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("FormatForecast() = %q, don't want it to contain %q", got, w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidTelegramAuth is returned when Telegram login data is malformed or isn't signed with the bot token
+	ErrInvalidTelegramAuth = errors.New("invalid telegram authentication data")
+	// ErrExpiredTelegramAuth is returned when Telegram login data is older than allowed
+	ErrExpiredTelegramAuth = errors.New("telegram authentication data expired")
+)
+
+// TelegramUser is the Telegram account signed in with the Login Widget or a Mini App
+type TelegramUser struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Username  string `json:"username"`
+}
+
+// VerifyLoginWidget checks the fields sent by the Telegram Login Widget against the bot token,
+// see https://core.telegram.org/widgets/login#checking-authorization
+func VerifyLoginWidget(fields url.Values, botToken string, maxAge time.Duration, now time.Time) (TelegramUser, error) {
+	secret := sha256.Sum256([]byte(botToken))
+	if err := checkTelegramHash(fields, secret[:]); err != nil {
+		return TelegramUser{}, err
+	}
+
+	if err := checkTelegramAuthDate(fields.Get("auth_date"), maxAge, now); err != nil {
+		return TelegramUser{}, err
+	}
+
+	id, err := strconv.ParseInt(fields.Get("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return TelegramUser{}, ErrInvalidTelegramAuth
+	}
+
+	return TelegramUser{
+		ID:        id,
+		FirstName: fields.Get("first_name"),
+		LastName:  fields.Get("last_name"),
+		Username:  fields.Get("username"),
+	}, nil
+}
+
+// VerifyWebAppInitData checks the initData of a Telegram Mini App against the bot token,
+// see https://core.telegram.org/bots/webapps#validating-data-received-via-the-mini-app
+func VerifyWebAppInitData(initData string, botToken string, maxAge time.Duration, now time.Time) (TelegramUser, error) {
+	fields, err := url.ParseQuery(initData)
+	if err != nil {
+		return TelegramUser{}, ErrInvalidTelegramAuth
+	}
+
+	mac := hmac.New(sha256.New, []byte("WebAppData"))
+	mac.Write([]byte(botToken))
+	if err := checkTelegramHash(fields, mac.Sum(nil)); err != nil {
+		return TelegramUser{}, err
+	}
+
+	if err := checkTelegramAuthDate(fields.Get("auth_date"), maxAge, now); err != nil {
+		return TelegramUser{}, err
+	}
+
+	var user TelegramUser
+	if err := json.Unmarshal([]byte(fields.Get("user")), &user); err != nil || user.ID <= 0 {
+		return TelegramUser{}, ErrInvalidTelegramAuth
+	}
+
+	return user, nil
+}
+
+// telegramDataCheckString joins the fields but the hash as sorted key=value lines
+func telegramDataCheckString(fields url.Values) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if key != "hash" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = key + "=" + fields.Get(key)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkTelegramHash compares the hash field with the HMAC of the other fields
+func checkTelegramHash(fields url.Values, secret []byte) error {
+	hash, err := hex.DecodeString(fields.Get("hash"))
+	if err != nil || len(hash) == 0 {
+		return ErrInvalidTelegramAuth
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(telegramDataCheckString(fields)))
+	if !hmac.Equal(hash, mac.Sum(nil)) {
+		return ErrInvalidTelegramAuth
+	}
+
+	return nil
+}
+
+// checkTelegramAuthDate rejects data signed more than maxAge ago
+func checkTelegramAuthDate(authDate string, maxAge time.Duration, now time.Time) error {
+	unix, err := strconv.ParseInt(authDate, 10, 64)
+	if err != nil {
+		return ErrInvalidTelegramAuth
+	}
+
+	if now.Sub(time.Unix(unix, 0)) > maxAge {
+		return ErrExpiredTelegramAuth
+	}
+
+	return nil
+}
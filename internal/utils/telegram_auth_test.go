@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+const testBotToken = "123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11"
+
+func signTelegram(secret []byte, dataCheckString string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(dataCheckString))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyLoginWidget(t *testing.T) {
+	now := time.Unix(1750000000, 0)
+	authDate := strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)
+
+	secret := sha256.Sum256([]byte(testBotToken))
+	valid := url.Values{
+		"id":         {"42"},
+		"first_name": {"Ada"},
+		"username":   {"ada"},
+		"auth_date":  {authDate},
+	}
+	valid.Set("hash", signTelegram(secret[:], "auth_date="+authDate+"\nfirst_name=Ada\nid=42\nusername=ada"))
+
+	user, err := VerifyLoginWidget(valid, testBotToken, time.Hour, now)
+	if err != nil {
+		t.Fatalf("VerifyLoginWidget() error = %v", err)
+	}
+	if user.ID != 42 || user.FirstName != "Ada" || user.Username != "ada" {
+		t.Errorf("VerifyLoginWidget() = %+v", user)
+	}
+
+	tampered := url.Values{}
+	for k, v := range valid {
+		tampered[k] = v
+	}
+	tampered.Set("id", "43")
+
+	tests := []struct {
+		name   string
+		fields url.Values
+		token  string
+		now    time.Time
+		want   error
+	}{
+		{"tampered field", tampered, testBotToken, now, ErrInvalidTelegramAuth},
+		{"other bot", valid, "654321:other", now, ErrInvalidTelegramAuth},
+		{"missing hash", url.Values{"id": {"42"}, "auth_date": {authDate}}, testBotToken, now, ErrInvalidTelegramAuth},
+		{"expired", valid, testBotToken, now.Add(2 * time.Hour), ErrExpiredTelegramAuth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyLoginWidget(tt.fields, tt.token, time.Hour, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyLoginWidget() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyWebAppInitData(t *testing.T) {
+	now := time.Unix(1750000000, 0)
+	authDate := strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)
+	userJSON := `{"id":42,"first_name":"Ada","username":"ada"}`
+
+	mac := hmac.New(sha256.New, []byte("WebAppData"))
+	mac.Write([]byte(testBotToken))
+	secret := mac.Sum(nil)
+
+	fields := url.Values{
+		"auth_date": {authDate},
+		"query_id":  {"AAH"},
+		"user":      {userJSON},
+	}
+	fields.Set("hash", signTelegram(secret, "auth_date="+authDate+"\nquery_id=AAH\nuser="+userJSON))
+	initData := fields.Encode()
+
+	user, err := VerifyWebAppInitData(initData, testBotToken, time.Hour, now)
+	if err != nil {
+		t.Fatalf("VerifyWebAppInitData() error = %v", err)
+	}
+	if user.ID != 42 || user.Username != "ada" {
+		t.Errorf("VerifyWebAppInitData() = %+v", user)
+	}
+
+	// Signed like the Login Widget instead of a Mini App
+	widgetSecret := sha256.Sum256([]byte(testBotToken))
+	widgetSigned := url.Values{"auth_date": {authDate}, "query_id": {"AAH"}, "user": {userJSON}}
+	widgetSigned.Set("hash", signTelegram(widgetSecret[:], "auth_date="+authDate+"\nquery_id=AAH\nuser="+userJSON))
+
+	tests := []struct {
+		name     string
+		initData string
+		now      time.Time
+		want     error
+	}{
+		{"empty", "", now, ErrInvalidTelegramAuth},
+		{"widget secret", widgetSigned.Encode(), now, ErrInvalidTelegramAuth},
+		{"extra field", initData + "&extra=1", now, ErrInvalidTelegramAuth},
+		{"expired", initData, now.Add(2 * time.Hour), ErrExpiredTelegramAuth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyWebAppInitData(tt.initData, testBotToken, time.Hour, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyWebAppInitData() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
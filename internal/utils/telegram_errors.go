@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+// TelegramRetryAfter tells if Telegram refused a request because the bot sends too many messages,
+// and how long to wait before the next one
+func TelegramRetryAfter(err error) (time.Duration, bool) {
+	var tgErr *gotgbot.TelegramError
+	if !errors.As(err, &tgErr) || tgErr.Code != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	retryAfter := time.Second
+	if tgErr.ResponseParams != nil && tgErr.ResponseParams.RetryAfter > 0 {
+		retryAfter = time.Duration(tgErr.ResponseParams.RetryAfter) * time.Second
+	}
+	return retryAfter, true
+}
+
+// IsBotBlocked tells if Telegram refused a message because the bot can't write to the user anymore:
+// they blocked it, deleted their account or never started it
+func IsBotBlocked(err error) bool {
+	var tgErr *gotgbot.TelegramError
+	if !errors.As(err, &tgErr) {
+		return false
+	}
+
+	switch tgErr.Code {
+	case http.StatusForbidden:
+		return true
+	case http.StatusBadRequest:
+		return strings.Contains(strings.ToLower(tgErr.Description), "chat not found")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+func TestTelegramRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   time.Duration
+		wantOK bool
+	}{
+		{
+			name: "too many requests",
+			err: &gotgbot.TelegramError{
+				Code:           429,
+				Description:    "Too Many Requests: retry after 35",
+				ResponseParams: &gotgbot.ResponseParameters{RetryAfter: 35},
+			},
+			want:   35 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "too many requests without retry after",
+			err:    &gotgbot.TelegramError{Code: 429, Description: "Too Many Requests"},
+			want:   time.Second,
+			wantOK: true,
+		},
+		{
+			name: "wrapped",
+			err: fmt.Errorf("failed to send: %w", &gotgbot.TelegramError{
+				Code:           429,
+				ResponseParams: &gotgbot.ResponseParameters{RetryAfter: 3},
+			}),
+			want:   3 * time.Second,
+			wantOK: true,
+		},
+		{
+			name:   "other telegram error",
+			err:    &gotgbot.TelegramError{Code: 400, Description: "Bad Request: message is too long"},
+			wantOK: false,
+		},
+		{
+			name:   "network error",
+			err:    errors.New("connection reset by peer"),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TelegramRetryAfter(tt.err)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("TelegramRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestIsBotBlocked(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "blocked by the user",
+			err:  &gotgbot.TelegramError{Code: 403, Description: "Forbidden: bot was blocked by the user"},
+			want: true,
+		},
+		{
+			name: "deactivated user",
+			err:  fmt.Errorf("failed to send: %w", &gotgbot.TelegramError{Code: 403, Description: "Forbidden: user is deactivated"}),
+			want: true,
+		},
+		{
+			name: "chat not found",
+			err:  &gotgbot.TelegramError{Code: 400, Description: "Bad Request: chat not found"},
+			want: true,
+		},
+		{
+			name: "other bad request",
+			err:  &gotgbot.TelegramError{Code: 400, Description: "Bad Request: can't parse entities"},
+			want: false,
+		},
+		{
+			name: "too many requests",
+			err:  &gotgbot.TelegramError{Code: 429, Description: "Too Many Requests: retry after 5"},
+			want: false,
+		},
+		{
+			name: "network error",
+			err:  errors.New("connection reset by peer"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsBotBlocked(tt.err); got != tt.want {
+				t.Errorf("IsBotBlocked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownTimezone is returned when a time zone is neither an IANA name nor a whole-hour UTC offset
+var ErrUnknownTimezone = errors.New("unknown time zone")
+
+var utcOffsetPattern = regexp.MustCompile(`^(?i:utc|gmt)?\s*([+-])\s*(\d{1,2})(?::00)?$`)
+
+// ParseTimezone returns the IANA name of a time zone typed by a user, e.g. "Europe/Rome", "europe/rome",
+// "UTC" or a whole-hour offset like "UTC+2" or "-5", which have no daylight saving time
+func ParseTimezone(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", ErrUnknownTimezone
+	}
+
+	if matches := utcOffsetPattern.FindStringSubmatch(input); matches != nil {
+		hours, _ := strconv.Atoi(matches[2])
+		if matches[1] == "-" {
+			hours = -hours
+		}
+		if hours < -12 || hours > 14 {
+			return "", ErrUnknownTimezone
+		}
+		return offsetTimezone(hours), nil
+	}
+
+	// Time zone names are case-sensitive, try the usual capitalization too
+	for _, name := range []string{input, titleTimezone(input)} {
+		// LoadLocation also accepts "Local" and paths, which aren't zones
+		if name == "Local" || strings.Contains(name, "..") {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc.String(), nil
+		}
+	}
+
+	return "", ErrUnknownTimezone
+}
+
+// TimezoneFromLongitude guesses the time zone of a location from its longitude, as a whole-hour offset
+// without daylight saving time
+func TimezoneFromLongitude(longitude float64) string {
+	hours := int(math.Round(longitude / 15))
+	return offsetTimezone(max(-12, min(14, hours)))
+}
+
+// FormatTimezone returns a time zone name for display, offsets as "UTC+2" rather than "Etc/GMT-2"
+func FormatTimezone(name string) string {
+	if offset, ok := strings.CutPrefix(name, "Etc/GMT"); ok && offset != "" {
+		hours, err := strconv.Atoi(offset)
+		if err == nil {
+			// The Etc zones have the POSIX sign, inverted
+			return fmt.Sprintf("UTC%+d", -hours)
+		}
+	}
+	if name == "" {
+		return "UTC"
+	}
+	return name
+}
+
+// offsetTimezone returns the Etc zone of a UTC offset in hours
+func offsetTimezone(hours int) string {
+	if hours == 0 {
+		return "UTC"
+	}
+	return fmt.Sprintf("Etc/GMT%+d", -hours)
+}
+
+// titleTimezone capitalizes the words of a time zone name, e.g. "america/new_york" as "America/New_York"
+func titleTimezone(name string) string {
+	if strings.EqualFold(name, "utc") {
+		return "UTC"
+	}
+
+	runes := []rune(strings.ToLower(name))
+	for i := range runes {
+		if i == 0 || strings.ContainsRune("/_-", runes[i-1]) {
+			runes[i] = []rune(strings.ToUpper(string(runes[i])))[0]
+		}
+	}
+	return string(runes)
+}
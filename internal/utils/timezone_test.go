@@ -0,0 +1,82 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseTimezone(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{"iana name", "Europe/Rome", "Europe/Rome", nil},
+		{"lowercase name", "america/new_york", "America/New_York", nil},
+		{"utc", "utc", "UTC", nil},
+		{"positive offset", "UTC+2", "Etc/GMT-2", nil},
+		{"negative offset", "GMT-5:00", "Etc/GMT+5", nil},
+		{"bare offset", "+9", "Etc/GMT-9", nil},
+		{"zero offset", "UTC+0", "UTC", nil},
+		{"offset out of range", "UTC+15", "", ErrUnknownTimezone},
+		{"unknown name", "Mars/Olympus", "", ErrUnknownTimezone},
+		{"local", "Local", "", ErrUnknownTimezone},
+		{"empty", "  ", "", ErrUnknownTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimezone(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseTimezone() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseTimezone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimezoneFromLongitude(t *testing.T) {
+	tests := []struct {
+		name      string
+		longitude float64
+		want      string
+	}{
+		{"greenwich", -0.0015, "UTC"},
+		{"rome", 12.4964, "Etc/GMT-1"},
+		{"new york", -74.006, "Etc/GMT+5"},
+		{"date line east", 179.9, "Etc/GMT-12"},
+		{"date line west", -179.9, "Etc/GMT+12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TimezoneFromLongitude(tt.longitude); got != tt.want {
+				t.Errorf("TimezoneFromLongitude() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatTimezone(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Europe/Rome", "Europe/Rome"},
+		{"Etc/GMT-2", "UTC+2"},
+		{"Etc/GMT+5", "UTC-5"},
+		{"UTC", "UTC"},
+		{"", "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatTimezone(tt.name); got != tt.want {
+				t.Errorf("FormatTimezone() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
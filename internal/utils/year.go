@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// YearTotals are the totals a year recap is made of
+type YearTotals struct {
+	// Monthly are the totals of the year by month (1-12) and type
+	Monthly map[int]map[model.TransactionType]float64
+	// PreviousMonthly are the same totals of the previous year, to compare with
+	PreviousMonthly map[int]map[model.TransactionType]float64
+	// Categories are the totals of the year by type and category
+	Categories map[model.TransactionType]map[model.TransactionCategory]float64
+}
+
+// FormatYearRecap summarizes a year month by month and by category, compared with the previous year.
+// The current year, by now, is summarized up to the current month.
+func FormatYearRecap(totals YearTotals, year int, now time.Time) string {
+	res := totals.Monthly
+	categoryTotals := totals.Categories
+
+	var msg strings.Builder
+	var yearTotal float64
+	var yearExpense float64
+	var yearIncome float64
+
+	// Determine which months to show
+	endMonth := 12
+	if year == now.Year() {
+		endMonth = int(now.Month())
+	}
+
+	// Format header
+	msg.WriteString(fmt.Sprintf("📊 <b>%d Year Summary</b>\n\n", year))
+
+	// Check if there are any transactions
+	hasTransactions := false
+	for m := 1; m <= endMonth; m++ {
+		if _, ok := res[m]; ok {
+			hasTransactions = true
+			break
+		}
+	}
+
+	if !hasTransactions {
+		msg.WriteString(fmt.Sprintf("No transactions recorded for %d", year))
+		return msg.String()
+	}
+
+	// --- MONTHLY BREAKDOWN SECTION ---
+	msg.WriteString("<b>Monthly Breakdown:</b>\n\n")
+
+	for m := 1; m <= endMonth; m++ {
+		monthT, hasTransactions := res[m]
+		if !hasTransactions {
+			continue // Skip months with no transactions
+		}
+
+		msg.WriteString(fmt.Sprintf("🗓 <b>%s</b>\n", time.Month(m).String()))
+		var monthTotal float64
+
+		if expenseAmount, ok := monthT[model.TypeExpense]; ok && expenseAmount > 0 {
+			msg.WriteString(fmt.Sprintf("  💸 <b>Expenses:</b> %.2f€\n", expenseAmount))
+			monthTotal -= expenseAmount
+			yearExpense += expenseAmount
+		}
+
+		if incomeAmount, ok := monthT[model.TypeIncome]; ok && incomeAmount > 0 {
+			msg.WriteString(fmt.Sprintf("  💰 <b>Income:</b> %.2f€\n", incomeAmount))
+			monthTotal += incomeAmount
+			yearIncome += incomeAmount
+		}
+
+		yearTotal += monthTotal
+
+		var balanceEmoji string
+		if monthTotal >= 0 {
+			balanceEmoji = "✅"
+		} else {
+			balanceEmoji = "❌"
+		}
+
+		msg.WriteString(fmt.Sprintf("  %s <b>Balance:</b> %.2f€\n\n", balanceEmoji, monthTotal))
+	}
+
+	// --- YEAR TOTAL SECTION ---
+	msg.WriteString("\n<b>💰 Year Summary</b>\n")
+
+	// Add expense summary with category breakdown
+	if yearExpense > 0 {
+		msg.WriteString(fmt.Sprintf("💸 <b>Total Expenses:</b> %.2f€\n", yearExpense))
+
+		// Add category breakdown for expenses
+		if expenseCats, ok := categoryTotals[model.TypeExpense]; ok && len(expenseCats) > 0 {
+			msg.WriteString("\n<b>Expense Categories:</b>\n")
+
+			// Sort categories by amount (descending)
+			categories := make([]struct {
+				Category model.TransactionCategory
+				Amount   float64
+			}, 0, len(expenseCats))
+
+			for cat, amount := range expenseCats {
+				categories = append(categories, struct {
+					Category model.TransactionCategory
+					Amount   float64
+				}{cat, amount})
+			}
+
+			sort.Slice(categories, func(i, j int) bool {
+				return categories[i].Amount > categories[j].Amount
+			})
+
+			// Display top categories (limit to top 5 for readability)
+			maxCategories := 5
+			if len(categories) < maxCategories {
+				maxCategories = len(categories)
+			}
+
+			for i := 0; i < maxCategories; i++ {
+				entry := categories[i]
+				emoji := GetCategoryEmoji(entry.Category)
+				percentage := (entry.Amount / yearExpense) * 100
+				msg.WriteString(fmt.Sprintf("  %s <b>%s:</b> %.2f€ (%.1f%%)\n",
+					emoji, entry.Category, entry.Amount, percentage))
+			}
+
+			// Show "Other" for remaining categories if more than 5
+			if len(categories) > maxCategories {
+				var otherAmount float64
+				for i := maxCategories; i < len(categories); i++ {
+					otherAmount += categories[i].Amount
+				}
+				percentage := (otherAmount / yearExpense) * 100
+				msg.WriteString(fmt.Sprintf("  📌 <b>Others:</b> %.2f€ (%.1f%%)\n",
+					otherAmount, percentage))
+			}
+
+			msg.WriteString("\n")
+		}
+	}
+
+	// Add income summary with category breakdown
+	if yearIncome > 0 {
+		msg.WriteString(fmt.Sprintf("💰 <b>Total Income:</b> %.2f€\n", yearIncome))
+
+		// Add category breakdown for income
+		if incomeCats, ok := categoryTotals[model.TypeIncome]; ok && len(incomeCats) > 0 {
+			msg.WriteString("\n<b>Income Categories:</b>\n")
+
+			// Sort categories by amount (descending)
+			categories := make([]struct {
+				Category model.TransactionCategory
+				Amount   float64
+			}, 0, len(incomeCats))
+
+			for cat, amount := range incomeCats {
+				categories = append(categories, struct {
+					Category model.TransactionCategory
+					Amount   float64
+				}{cat, amount})
+			}
+
+			sort.Slice(categories, func(i, j int) bool {
+				return categories[i].Amount > categories[j].Amount
+			})
+
+			// Display all income categories (usually fewer than expenses)
+			for _, entry := range categories {
+				emoji := GetCategoryEmoji(entry.Category)
+				percentage := (entry.Amount / yearIncome) * 100
+				msg.WriteString(fmt.Sprintf("  %s <b>%s:</b> %.2f€ (%.1f%%)\n",
+					emoji, entry.Category, entry.Amount, percentage))
+			}
+
+			msg.WriteString("\n")
+		}
+	}
+
+	// Add final balance
+	var balanceEmoji string
+	if yearTotal >= 0 {
+		balanceEmoji = "✅"
+	} else {
+		balanceEmoji = "❌"
+	}
+
+	msg.WriteString(fmt.Sprintf("\n%s <b>Year Balance:</b> %.2f€", balanceEmoji, yearTotal))
+
+	// --- COMPARISON SECTION ---
+	// Compare the same months of the previous year, so a year in progress isn't compared to a full one
+	prevTotals := SumMonthlyTotals(totals.PreviousMonthly, endMonth)
+	title := fmt.Sprintf("vs %d", year-1)
+	if endMonth < 12 {
+		title = fmt.Sprintf("vs Jan-%s %d", time.Month(endMonth).String()[:3], year-1)
+	}
+	WriteComparisonSummary(&msg, title, Comparison{
+		Expenses: NewDelta(yearExpense, prevTotals[model.TypeExpense]),
+		Income:   NewDelta(yearIncome, prevTotals[model.TypeIncome]),
+		Balance:  NewDelta(yearTotal, prevTotals[model.TypeIncome]-prevTotals[model.TypeExpense]),
+	})
+
+	return msg.String()
+}
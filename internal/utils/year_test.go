@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"cashout/internal/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatYearRecap(t *testing.T) {
+	monthly := func(months ...int) map[int]map[model.TransactionType]float64 {
+		totals := make(map[int]map[model.TransactionType]float64)
+		for _, m := range months {
+			totals[m] = map[model.TransactionType]float64{model.TypeExpense: 100, model.TypeIncome: 150}
+		}
+		return totals
+	}
+	totals := YearTotals{
+		Monthly:         monthly(1, 2, 6),
+		PreviousMonthly: monthly(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+		Categories: map[model.TransactionType]map[model.TransactionCategory]float64{
+			model.TypeExpense: {model.CategoryGrocery: 300},
+			model.TypeIncome:  {model.CategorySalary: 450},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		totals  YearTotals
+		year    int
+		now     time.Time
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "past year",
+			totals: totals,
+			year:   2024,
+			now:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:   []string{"2024 Year Summary", "January", "June", "Total Expenses:</b> 300.00€", "Year Balance:</b> 150.00€", "<b>vs 2023:</b>", "(-900.00€, -75.0%)"},
+		},
+		{
+			name:    "year in progress",
+			totals:  totals,
+			year:    2025,
+			now:     time.Date(2025, 2, 20, 0, 0, 0, 0, time.UTC),
+			want:    []string{"February", "Total Expenses:</b> 200.00€", "<b>vs Jan-Feb 2024:</b>", "Expenses (=)"},
+			notWant: []string{"June"},
+		},
+		{
+			name:   "no transactions",
+			totals: YearTotals{},
+			year:   2024,
+			now:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			want:   []string{"No transactions recorded for 2024"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatYearRecap(tt.totals, tt.year, tt.now)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("FormatYearRecap() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("FormatYearRecap() = %q, don't want it to contain %q", got, w)
+				}
+			}
+		})
+	}
+}
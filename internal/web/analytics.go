@@ -0,0 +1,276 @@
+package web
+
+import (
+	"cashout/internal/client"
+	"cashout/internal/model"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultTrendMonths = 12
+	maxTrendMonths     = 36
+
+	defaultMerchants = 10
+	maxMerchants     = 50
+)
+
+// trendMonth is a month of the trend: totals by type and by category
+type trendMonth struct {
+	Month      string             `json:"month"`
+	Income     float64            `json:"income"`
+	Expenses   float64            `json:"expenses"`
+	Balance    float64            `json:"balance"`
+	Categories map[string]float64 `json:"categories"`
+}
+
+// categoryStat is the total of a category in a period and its share of the type total
+type categoryStat struct {
+	Type     string  `json:"type"`
+	Category string  `json:"category"`
+	Total    float64 `json:"total"`
+	Count    int64   `json:"count"`
+	Percent  float64 `json:"percent"`
+}
+
+// merchantStat is the total spent with a merchant in a period
+type merchantStat struct {
+	Name  string  `json:"name"`
+	Total float64 `json:"total"`
+	Count int64   `json:"count"`
+}
+
+// handleAnalytics shows the yearly analytics page with the charts
+func (s *Server) handleAnalytics(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, basePath+"/login", http.StatusSeeOther)
+		return
+	}
+
+	// Parse year from query, default to current year
+	now := user.Now()
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil || year > now.Year() || year < client.MIN_YEAR_ALLOWED {
+		year = now.Year()
+	}
+
+	// The trend covers the 12 months up to the end of the year, or up to now for the current year
+	trendEnd := time.Date(year, 12, 1, 0, 0, 0, 0, time.UTC)
+	if year == now.Year() {
+		trendEnd = time.Date(year, now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	data := struct {
+		page
+		Year             int
+		PrevYear         int
+		NextYear         int
+		IsFirstYear      bool
+		IsCurrentYear    bool
+		TrendEnd         string
+		IncomeCategories []string
+	}{
+		page:             page{Name: "analytics", User: user},
+		Year:             year,
+		PrevYear:         year - 1,
+		NextYear:         year + 1,
+		IsFirstYear:      year <= client.MIN_YEAR_ALLOWED,
+		IsCurrentYear:    year == now.Year(),
+		TrendEnd:         trendEnd.Format(monthLayout),
+		IncomeCategories: categoriesByType()[model.TypeIncome],
+	}
+
+	s.render(w, "analytics", data)
+}
+
+// parseStatsRange reads the period of the stats endpoints: a year, or from and to dates, defaulting to the current year
+// of the user
+func parseStatsRange(r *http.Request, user *model.User) (time.Time, time.Time, error) {
+	params := r.URL.Query()
+
+	if params.Get("from") != "" || params.Get("to") != "" {
+		from, err := time.Parse(dateLayout, params.Get("from"))
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid from date, expected YYYY-MM-DD")
+		}
+		to, err := time.Parse(dateLayout, params.Get("to"))
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid to date, expected YYYY-MM-DD")
+		}
+		if to.Before(from) {
+			return time.Time{}, time.Time{}, errors.New("to must not be before from")
+		}
+		return from, to, nil
+	}
+
+	year := user.Now().Year()
+	if y := params.Get("year"); y != "" {
+		var err error
+		year, err = strconv.Atoi(y)
+		if err != nil || year < client.MIN_YEAR_ALLOWED || year > user.Now().Year() {
+			return time.Time{}, time.Time{}, errors.New("invalid year")
+		}
+	}
+
+	from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	return from, from.AddDate(1, 0, -1), nil
+}
+
+// handleAPIStatsTrend returns the monthly totals of the months up to end (YYYY-MM, default the current month)
+func (s *Server) handleAPIStatsTrend(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	months := defaultTrendMonths
+	if m := r.URL.Query().Get("months"); m != "" {
+		var err error
+		months, err = strconv.Atoi(m)
+		if err != nil || months < 1 || months > maxTrendMonths {
+			s.sendJSONError(w, "months must be between 1 and "+strconv.Itoa(maxTrendMonths), http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := user.Now()
+	end := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if e := r.URL.Query().Get("end"); e != "" {
+		var err error
+		end, err = time.Parse(monthLayout, e)
+		if err != nil {
+			s.sendJSONError(w, "invalid end, expected YYYY-MM", http.StatusBadRequest)
+			return
+		}
+	}
+	start := end.AddDate(0, -(months - 1), 0)
+
+	totals, err := s.repositories.Transactions.GetMonthlyCategoryTotals(user.TgID, start, end.AddDate(0, 1, -1))
+	if err != nil {
+		s.logger.Errorf("Failed to get monthly totals: %v", err)
+		s.sendJSONError(w, "Failed to get statistics", http.StatusInternalServerError)
+		return
+	}
+
+	// Every month is listed, even without transactions
+	trend := make([]trendMonth, months)
+	index := make(map[string]int, months)
+	for i := range trend {
+		month := start.AddDate(0, i, 0).Format(monthLayout)
+		trend[i] = trendMonth{Month: month, Categories: make(map[string]float64)}
+		index[month] = i
+	}
+
+	for _, t := range totals {
+		i, ok := index[t.Month.Format(monthLayout)]
+		if !ok {
+			continue
+		}
+		if t.Type == model.TypeIncome {
+			trend[i].Income += t.Total
+		} else {
+			trend[i].Expenses += t.Total
+		}
+		trend[i].Categories[string(t.Category)] = t.Total
+	}
+
+	for i := range trend {
+		trend[i].Balance = math.Round((trend[i].Income-trend[i].Expenses)*100) / 100
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"months": trend,
+	})
+}
+
+// handleAPIStatsCategories returns the category totals of a period with the share of each in its type
+func (s *Server) handleAPIStatsCategories(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	from, to, err := parseStatsRange(r, user)
+	if err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	totals, err := s.repositories.Transactions.GetCategoryTotals(user.TgID, from, to)
+	if err != nil {
+		s.logger.Errorf("Failed to get category totals: %v", err)
+		s.sendJSONError(w, "Failed to get statistics", http.StatusInternalServerError)
+		return
+	}
+
+	typeTotals := make(map[model.TransactionType]float64)
+	for _, t := range totals {
+		typeTotals[t.Type] += t.Total
+	}
+
+	categories := make([]categoryStat, len(totals))
+	for i, t := range totals {
+		categories[i] = categoryStat{
+			Type:     string(t.Type),
+			Category: string(t.Category),
+			Total:    t.Total,
+			Count:    t.Count,
+			Percent:  math.Round(t.Total/typeTotals[t.Type]*1000) / 10,
+		}
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"from":          from.Format(dateLayout),
+		"to":            to.Format(dateLayout),
+		"totalIncome":   typeTotals[model.TypeIncome],
+		"totalExpenses": typeTotals[model.TypeExpense],
+		"balance":       math.Round((typeTotals[model.TypeIncome]-typeTotals[model.TypeExpense])*100) / 100,
+		"categories":    categories,
+	})
+}
+
+// handleAPIStatsMerchants returns where the user spent the most in a period
+func (s *Server) handleAPIStatsMerchants(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	from, to, err := parseStatsRange(r, user)
+	if err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultMerchants
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxMerchants {
+			s.sendJSONError(w, "limit must be between 1 and "+strconv.Itoa(maxMerchants), http.StatusBadRequest)
+			return
+		}
+	}
+
+	totals, err := s.repositories.Transactions.GetTopMerchants(user.TgID, from, to, limit)
+	if err != nil {
+		s.logger.Errorf("Failed to get top merchants: %v", err)
+		s.sendJSONError(w, "Failed to get statistics", http.StatusInternalServerError)
+		return
+	}
+
+	merchants := make([]merchantStat, len(totals))
+	for i, t := range totals {
+		merchants[i] = merchantStat{Name: t.Name, Total: t.Total, Count: t.Count}
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"merchants": merchants,
+	})
+}
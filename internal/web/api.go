@@ -0,0 +1,152 @@
+package web
+
+import (
+	"bytes"
+	"cashout/internal/client"
+	"cashout/internal/utils"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const apiV1Path = "/api/v1"
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// requireAPIToken authenticates public API requests with a personal access token sent as a bearer token,
+// rejecting writes made with read-only tokens
+func (s *Server) requireAPIToken(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cashout"`)
+			s.sendJSONError(w, "Missing API token", http.StatusUnauthorized)
+			return
+		}
+
+		apiToken, err := s.repositories.APITokens.Authenticate(strings.TrimSpace(token))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cashout", error="invalid_token"`)
+			s.sendJSONError(w, "Invalid API token", http.StatusUnauthorized)
+			return
+		}
+
+		if !apiToken.Scope.Allows(r.Method) {
+			s.sendJSONError(w, "This token is read-only", http.StatusForbidden)
+			return
+		}
+
+		user, err := s.repositories.Users.GetByTgID(apiToken.TgID)
+		if err != nil || !s.canUse(user) {
+			s.sendJSONError(w, "Invalid API token", http.StatusUnauthorized)
+			return
+		}
+
+		ctx := client.SetUserInContext(r.Context(), &user)
+		handler(w, r.WithContext(ctx))
+	}
+}
+
+// handleOpenAPISpec serves the OpenAPI description of the public API
+func (s *Server) handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	if _, err := w.Write(openAPISpec); err != nil {
+		s.logger.Errorf("Failed to send OpenAPI spec: %v", err)
+	}
+}
+
+// handleAPIV1Transactions lists the user's transactions with filters, sorting and cursor pagination
+func (s *Server) handleAPIV1Transactions(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.sendTransactionsPage(w, r, user.TgID)
+}
+
+// handleAPIV1Transaction returns a single transaction of the user
+func (s *Server) handleAPIV1Transaction(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tx, ok := s.getOwnedTransaction(w, r, user.TgID)
+	if !ok {
+		return
+	}
+
+	s.sendJSONSuccess(w, newTransactionResponse(tx))
+}
+
+// handleAPIV1Categories lists the available categories by transaction type
+func (s *Server) handleAPIV1Categories(w http.ResponseWriter, r *http.Request) {
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"categories": categoriesByType(),
+	})
+}
+
+// handleAPIV1Export downloads the user's transactions matching the listing filters as CSV or JSON
+func (s *Server) handleAPIV1Export(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		s.sendJSONError(w, "format must be csv or json", http.StatusBadRequest)
+		return
+	}
+
+	query, err := parseTransactionQuery(r, user.TgID)
+	if err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transactions, err := s.repositories.Transactions.FindAllTransactions(query)
+	if err != nil {
+		s.logger.Errorf("Failed to export transactions: %v", err)
+		s.sendJSONError(w, "Failed to get transactions", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("cashout_export_%s.%s", user.Now().Format(dateLayout), format)
+
+	if format == "json" {
+		transactionResponses := make([]transactionResponse, len(transactions))
+		for i, tx := range transactions {
+			transactionResponses[i] = newTransactionResponse(tx)
+		}
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		s.sendJSONSuccess(w, map[string]interface{}{
+			"transactions": transactionResponses,
+			"count":        len(transactionResponses),
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := utils.WriteTransactionsCSV(&buf, transactions); err != nil {
+		s.logger.Errorf("Failed to export transactions: %v", err)
+		s.sendJSONError(w, "Failed to export transactions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.logger.Errorf("Failed to send export: %v", err)
+	}
+}
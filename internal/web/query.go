@@ -0,0 +1,200 @@
+package web
+
+import (
+	"cashout/internal/db"
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// parseTransactionQuery builds a transactions query from the request parameters:
+//   - from, to: inclusive dates (YYYY-MM-DD), or month (YYYY-MM) for a whole month
+//   - type: Income or Expense
+//   - category: one or more categories, comma separated or repeated
+//   - min, max: amount range
+//   - q: text searched in the description
+//   - sort: date, amount or category, and order: asc or desc (default)
+func parseTransactionQuery(r *http.Request, tgID int64) (db.TransactionQuery, error) {
+	query := db.TransactionQuery{TgID: tgID}
+	params := r.URL.Query()
+
+	if month := params.Get("month"); month != "" {
+		start, err := time.Parse(monthLayout, month)
+		if err != nil {
+			return query, errors.New("invalid month, expected YYYY-MM")
+		}
+		query.From = start
+		query.To = start.AddDate(0, 1, -1)
+	}
+
+	if from := params.Get("from"); from != "" {
+		date, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return query, errors.New("invalid from date, expected YYYY-MM-DD")
+		}
+		query.From = date
+	}
+
+	if to := params.Get("to"); to != "" {
+		date, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return query, errors.New("invalid to date, expected YYYY-MM-DD")
+		}
+		query.To = date
+	}
+
+	if t := params.Get("type"); t != "" {
+		query.Type = model.TransactionType(t)
+		if query.Type != model.TypeIncome && query.Type != model.TypeExpense {
+			return query, model.ErrInvalidTransactionType
+		}
+	}
+
+	categories := model.GetTransactionCategories()
+	for _, param := range params["category"] {
+		for _, category := range strings.Split(param, ",") {
+			category = strings.TrimSpace(category)
+			if category == "" {
+				continue
+			}
+			if !slices.Contains(categories, category) {
+				return query, fmt.Errorf("%w: %s", model.ErrInvalidCategory, category)
+			}
+			query.Categories = append(query.Categories, model.TransactionCategory(category))
+		}
+	}
+
+	var err error
+	if query.MinAmount, err = parseAmountParam(params.Get("min")); err != nil {
+		return query, errors.New("invalid min amount")
+	}
+	if query.MaxAmount, err = parseAmountParam(params.Get("max")); err != nil {
+		return query, errors.New("invalid max amount")
+	}
+
+	query.Search = strings.TrimSpace(params.Get("q"))
+
+	if query.Sort, err = db.ParseTransactionSort(params.Get("sort")); err != nil {
+		return query, err
+	}
+
+	switch strings.ToLower(params.Get("order")) {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, errors.New("invalid order, expected asc or desc")
+	}
+
+	return query, nil
+}
+
+// parseAmountParam parses an optional non-negative amount
+func parseAmountParam(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil || amount < 0 {
+		return nil, errors.New("invalid amount")
+	}
+	return &amount, nil
+}
+
+// parsePage reads the limit and cursor parameters. The cursor must come from a listing with the same sort.
+func parsePage(r *http.Request, query db.TransactionQuery) (*db.TransactionCursor, int, error) {
+	params := r.URL.Query()
+
+	limit := defaultPageSize
+	if l := params.Get("limit"); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return nil, 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+	}
+
+	c := params.Get("cursor")
+	if c == "" {
+		return nil, limit, nil
+	}
+
+	cursor, err := utils.DecodeCursor(c)
+	if err != nil {
+		return nil, 0, err
+	}
+	if cursor.Sort != string(query.Sort) {
+		return nil, 0, errors.New("the cursor belongs to a listing with a different sort")
+	}
+	if !validCursorValue(query.Sort, cursor.Value) {
+		return nil, 0, utils.ErrInvalidCursor
+	}
+
+	return &db.TransactionCursor{Value: cursor.Value, ID: cursor.ID}, limit, nil
+}
+
+// validCursorValue checks that a cursor holds a value of the field the listing is sorted by,
+// so a forged one is rejected before reaching the database
+func validCursorValue(sort db.TransactionSort, value string) bool {
+	switch sort {
+	case db.SortByAmount:
+		amount, err := strconv.ParseFloat(value, 64)
+		return err == nil && !math.IsNaN(amount) && !math.IsInf(amount, 0)
+	case db.SortByCategory:
+		return slices.Contains(model.GetTransactionCategories(), value)
+	default:
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	}
+}
+
+// sendTransactionsPage answers with a page of the user's transactions matching the request parameters
+func (s *Server) sendTransactionsPage(w http.ResponseWriter, r *http.Request, tgID int64) {
+	query, err := parseTransactionQuery(r, tgID)
+	if err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cursor, limit, err := parsePage(r, query)
+	if err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transactions, hasMore, err := s.repositories.Transactions.FindTransactions(query, cursor, limit)
+	if err != nil {
+		s.logger.Errorf("Failed to list transactions: %v", err)
+		s.sendJSONError(w, "Failed to get transactions", http.StatusInternalServerError)
+		return
+	}
+
+	transactionResponses := make([]transactionResponse, len(transactions))
+	for i, tx := range transactions {
+		transactionResponses[i] = newTransactionResponse(tx)
+	}
+
+	response := map[string]interface{}{
+		"transactions": transactionResponses,
+		"count":        len(transactionResponses),
+		"hasMore":      hasMore,
+	}
+	if hasMore {
+		next := query.CursorFor(transactions[len(transactions)-1])
+		response["nextCursor"] = utils.Cursor{Sort: string(query.Sort), Value: next.Value, ID: next.ID}.Encode()
+	}
+
+	s.sendJSONSuccess(w, response)
+}
@@ -5,6 +5,7 @@ import (
 	"cashout/internal/client"
 	"cashout/internal/model"
 	"cashout/internal/repository"
+	"html/template"
 	"net/http"
 	"sync"
 	"time"
@@ -18,6 +19,8 @@ type Repositories struct {
 	Users        repository.Users
 	Transactions repository.Transactions
 	Auth         repository.Auth
+	APITokens    repository.APITokens
+	AllowedUsers repository.AllowedUsers
 }
 
 type Server struct {
@@ -25,19 +28,26 @@ type Server struct {
 	repositories   Repositories
 	bot            *gotgbot.Bot
 	llm            ai.LLM
+	templates      map[string]*template.Template
 	loginLimiter   map[string]*rate.Limiter
 	loginLimiterMu sync.Mutex
 }
 
-func NewServer(logger *logrus.Logger, repos Repositories, bot *gotgbot.Bot, llm ai.LLM) *Server {
+func NewServer(logger *logrus.Logger, repos Repositories, bot *gotgbot.Bot, llm ai.LLM) (*Server, error) {
+	templates, err := parseTemplates()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Server{
 		logger:         logger,
 		repositories:   repos,
 		bot:            bot,
 		llm:            llm,
+		templates:      templates,
 		loginLimiter:   make(map[string]*rate.Limiter),
 		loginLimiterMu: sync.Mutex{},
-	}
+	}, nil
 }
 
 func (s *Server) Router() http.Handler {
@@ -81,18 +91,31 @@ func (s *Server) rateLimit(next http.HandlerFunc) http.HandlerFunc {
 func (s *Server) requireAuth(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		session, err := s.getSession(r)
-		if err != nil || session == nil || !session.IsValid() {
+		if err != nil || session == nil || !session.IsValid() || !s.canUse(*session.User) {
 			http.Redirect(w, r, basePath+"/login", http.StatusSeeOther)
 			return
 		}
 
-		// Add user to context
+		s.repositories.Auth.TouchWebSession(session, clientIP(r))
+
+		// Add user and session to context
 		ctx := r.Context()
 		ctx = client.SetUserInContext(ctx, session.User)
+		ctx = setSessionInContext(ctx, session)
 		handler(w, r.WithContext(ctx))
 	}
 }
 
+// canUse checks whether a user can still use a private deployment, they may have been removed since logging in
+func (s *Server) canUse(user model.User) bool {
+	allowed, err := s.repositories.AllowedUsers.CanUse(user.TgID, user.TgUsername)
+	if err != nil {
+		s.logger.Errorf("Failed to check allowed users: %v", err)
+		return false
+	}
+	return allowed
+}
+
 // Helper to get session from cookie
 func (s *Server) getSession(r *http.Request) (*model.WebSession, error) {
 	cookie, err := r.Cookie("session_id")
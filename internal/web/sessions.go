@@ -0,0 +1,229 @@
+package web
+
+import (
+	"cashout/internal/client"
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"context"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	gotgbot "github.com/PaulSonOfLars/gotgbot/v2"
+)
+
+type sessionContextKey struct{}
+
+// setSessionInContext adds the web session of the request to the context
+func setSessionInContext(ctx context.Context, session *model.WebSession) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// getSessionFromContext retrieves the web session of the request, set by requireAuth
+func getSessionFromContext(ctx context.Context) *model.WebSession {
+	session, _ := ctx.Value(sessionContextKey{}).(*model.WebSession)
+	return session
+}
+
+// sessionResponse is a web session as listed to its owner
+type sessionResponse struct {
+	ID         int64  `json:"id"`
+	Device     string `json:"device"`
+	IPAddress  string `json:"ipAddress"`
+	Remember   bool   `json:"remember"`
+	Current    bool   `json:"current"`
+	LastSeenAt string `json:"lastSeenAt"`
+	CreatedAt  string `json:"createdAt"`
+	ExpiresAt  string `json:"expiresAt"`
+}
+
+// clientIP returns the address the request comes from
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// startSession logs the user in on this device and tells them in Telegram
+func (s *Server) startSession(w http.ResponseWriter, r *http.Request, user model.User, remember bool) {
+	if !s.canUse(user) {
+		s.sendJSONError(w, "This account can't use Cashout anymore, ask the admin for access", http.StatusForbidden)
+		return
+	}
+
+	session, err := s.repositories.Auth.CreateWebSession(user.TgID, remember, r.UserAgent(), clientIP(r))
+	if err != nil {
+		s.logger.Errorf("Failed to create session: %v", err)
+		s.sendJSONError(w, "Failed to create session", http.StatusInternalServerError)
+		return
+	}
+
+	s.setSessionCookie(w, r, session)
+	s.notifyNewSession(user, session)
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"message":  "Login successful",
+		"redirect": basePath + "/dashboard",
+	})
+}
+
+// setSessionCookie sends the cookie of a web session. Unless remembered, it's deleted when the browser closes.
+func (s *Server) setSessionCookie(w http.ResponseWriter, r *http.Request, session *model.WebSession) {
+	cookie := &http.Cookie{
+		Name:     "session_id",
+		Value:    session.ID,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil, // Set to true in production with HTTPS
+		SameSite: http.SameSiteLaxMode,
+	}
+	if session.Remember {
+		cookie.MaxAge = int(time.Until(session.ExpiresAt).Seconds())
+	}
+	http.SetCookie(w, cookie)
+}
+
+// notifyNewSession warns the user in Telegram that their account was logged in from a device
+func (s *Server) notifyNewSession(user model.User, session *model.WebSession) {
+	message := fmt.Sprintf("🔐 <b>New login to Cashout web</b>\n\n%s\nIP: %s\n%s\n\nIf it wasn't you, log it out with /sessions.",
+		html.EscapeString(utils.DescribeUserAgent(session.UserAgent)),
+		html.EscapeString(session.IPAddress),
+		session.CreatedAt.In(user.Location()).Format("02-01-2006 15:04 MST"),
+	)
+
+	_, err := s.bot.SendMessage(user.TgID, message, &gotgbot.SendMessageOpts{
+		ParseMode: "HTML",
+	})
+	if err != nil {
+		s.logger.Warnf("Failed to send new session notification to %d: %v", user.TgID, err)
+	}
+}
+
+// rotateSession renews the session ID when it's old enough. It's only used on pages,
+// as the CSRF token of an already open page is bound to the previous ID.
+func (s *Server) rotateSession(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		session := getSessionFromContext(r.Context())
+		if session != nil && session.NeedsRotation(time.Now().UTC()) {
+			if err := s.repositories.Auth.RotateWebSession(session); err != nil {
+				s.logger.Errorf("Failed to rotate session: %v", err)
+			} else {
+				s.setSessionCookie(w, r, session)
+			}
+		}
+
+		handler(w, r)
+	}
+}
+
+// handleSessions shows the page listing the user's web sessions
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	session := getSessionFromContext(r.Context())
+	if user == nil || session == nil {
+		http.Redirect(w, r, basePath+"/login", http.StatusSeeOther)
+		return
+	}
+
+	data := struct {
+		page
+		CSRFToken string
+	}{
+		page:      page{Name: "sessions", User: user},
+		CSRFToken: csrfToken(session.ID),
+	}
+
+	s.render(w, "sessions", data)
+}
+
+// handleAPISessions lists the user's active web sessions
+func (s *Server) handleAPISessions(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	current := getSessionFromContext(r.Context())
+	if user == nil || current == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sessions, err := s.repositories.Auth.GetUserWebSessions(user.TgID)
+	if err != nil {
+		s.logger.Errorf("Failed to get sessions: %v", err)
+		s.sendJSONError(w, "Failed to get sessions", http.StatusInternalServerError)
+		return
+	}
+
+	sessionResponses := make([]sessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionResponses[i] = sessionResponse{
+			ID:         session.PublicID,
+			Device:     utils.DescribeUserAgent(session.UserAgent),
+			IPAddress:  session.IPAddress,
+			Remember:   session.Remember,
+			Current:    session.ID == current.ID,
+			LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			ExpiresAt:  session.ExpiresAt.Format(time.RFC3339),
+		}
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"sessions": sessionResponses,
+	})
+}
+
+// handleAPIRevokeSession logs out one of the user's other web sessions
+func (s *Server) handleAPIRevokeSession(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	current := getSessionFromContext(r.Context())
+	if user == nil || current == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.sendJSONError(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	if id == current.PublicID {
+		s.sendJSONError(w, "Use logout to end the current session", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repositories.Auth.RevokeWebSession(id, user.TgID); err != nil {
+		s.sendJSONError(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"message": "Session revoked",
+	})
+}
+
+// handleAPIRevokeOtherSessions logs out all the user's web sessions but the current one
+func (s *Server) handleAPIRevokeOtherSessions(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	current := getSessionFromContext(r.Context())
+	if user == nil || current == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	revoked, err := s.repositories.Auth.RevokeOtherWebSessions(user.TgID, current.ID)
+	if err != nil {
+		s.logger.Errorf("Failed to revoke sessions: %v", err)
+		s.sendJSONError(w, "Failed to revoke sessions", http.StatusInternalServerError)
+		return
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"message": "Sessions revoked",
+		"revoked": revoked,
+	})
+}
@@ -0,0 +1,65 @@
+package web
+
+import (
+	"cashout/internal/client"
+	"cashout/internal/pdf"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// handleStatement returns the PDF statement for a month (month=YYYY-MM) or a year (year=YYYY)
+func (s *Server) handleStatement(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Redirect(w, r, basePath+"/login", http.StatusSeeOther)
+		return
+	}
+
+	var year, month int
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		y, err := strconv.Atoi(yearStr)
+		if err != nil || y < client.MIN_YEAR_ALLOWED || y > user.Now().Year() {
+			http.Error(w, "Invalid year", http.StatusBadRequest)
+			return
+		}
+		year = y
+	} else {
+		currentMonth, err := time.Parse(monthLayout, r.URL.Query().Get("month"))
+		if err != nil {
+			currentMonth = user.Now()
+		}
+		year, month = currentMonth.Year(), int(currentMonth.Month())
+	}
+
+	from, to, label, suffix := client.StatementPeriod(year, month)
+
+	transactions, err := s.repositories.Transactions.GetUserTransactionsByDateRange(user.TgID, from, to)
+	if err != nil {
+		http.Error(w, "Failed to get transactions", http.StatusInternalServerError)
+		return
+	}
+
+	statement := pdf.Statement{
+		Name:         user.Name,
+		Period:       label,
+		From:         from,
+		To:           to,
+		Transactions: transactions,
+		GeneratedAt:  user.Now(),
+	}
+
+	data, err := statement.Render()
+	if err != nil {
+		s.logger.Errorf("Failed to render statement: %v", err)
+		http.Error(w, "Failed to render statement", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"cashout_statement_%s.pdf\"", suffix))
+	if _, err := w.Write(data); err != nil {
+		s.logger.Errorf("Failed to send statement: %v", err)
+	}
+}
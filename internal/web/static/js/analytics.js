@@ -0,0 +1,132 @@
+const page = document.getElementById('analytics').dataset;
+const year = Number(page.year);
+const trendEnd = page.trendEnd;
+const colors = ['#007bff', '#28a745', '#ffc107', '#dc3545', '#6f42c1', '#17a2b8', '#fd7e14', '#20c997', '#e83e8c', '#6c757d'];
+
+async function fetchJSON(url) {
+    const response = await fetch(url);
+    const data = await response.json();
+    if (!response.ok) throw new Error(data.error || 'Request failed');
+    return data;
+}
+
+function showError(id, error) {
+    const element = document.getElementById(id);
+    const message = '<div class="error">Failed to load: ' + escapeHtml(error.message) + '</div>';
+    if (element.tagName === 'CANVAS') {
+        element.parentElement.innerHTML = message;
+    } else {
+        element.innerHTML = message;
+    }
+}
+
+async function loadTrend() {
+    try {
+        const data = await fetchJSON('/web/api/stats/trend?months=12&end=' + trendEnd);
+        const labels = data.months.map(m => new Date(m.month + '-01').toLocaleDateString('en-US', { month: 'short', year: '2-digit' }));
+
+        new Chart(document.getElementById('balanceChart'), {
+            type: 'bar',
+            data: {
+                labels: labels,
+                datasets: [
+                    { label: 'Income', data: data.months.map(m => m.income), backgroundColor: '#28a745' },
+                    { label: 'Expenses', data: data.months.map(m => m.expenses), backgroundColor: '#dc3545' },
+                    { label: 'Balance', data: data.months.map(m => m.balance), type: 'line', borderColor: '#007bff', backgroundColor: '#007bff' },
+                ],
+            },
+            options: {
+                maintainAspectRatio: false,
+                plugins: { tooltip: { callbacks: { label: (c) => c.dataset.label + ': ' + formatCurrency(c.parsed.y) } } },
+            },
+        });
+
+        // The expense categories with the highest totals over the period, the others can be toggled from the legend
+        const incomeCategories = new Set(JSON.parse(document.getElementById('incomeCategories').textContent));
+        const totals = {};
+        data.months.forEach(m => {
+            for (const [category, amount] of Object.entries(m.categories)) {
+                if (incomeCategories.has(category)) continue;
+                totals[category] = (totals[category] || 0) + amount;
+            }
+        });
+        const categories = Object.keys(totals).sort((a, b) => totals[b] - totals[a]);
+
+        new Chart(document.getElementById('trendChart'), {
+            type: 'line',
+            data: {
+                labels: labels,
+                datasets: categories.map((category, i) => ({
+                    label: category,
+                    data: data.months.map(m => m.categories[category] || 0),
+                    borderColor: colors[i % colors.length],
+                    backgroundColor: colors[i % colors.length],
+                    hidden: i >= 5,
+                    tension: 0.3,
+                })),
+            },
+            options: {
+                maintainAspectRatio: false,
+                plugins: { tooltip: { callbacks: { label: (c) => c.dataset.label + ': ' + formatCurrency(c.parsed.y) } } },
+            },
+        });
+    } catch (error) {
+        showError('balanceChart', error);
+        showError('trendChart', error);
+    }
+}
+
+async function loadCategories() {
+    try {
+        const data = await fetchJSON('/web/api/stats/categories?year=' + year);
+
+        document.getElementById('yearStats').innerHTML =
+            '<div class="stat-card"><div class="stat-label">Income</div><div class="stat-value positive">' + formatCurrency(data.totalIncome) + '</div></div>' +
+            '<div class="stat-card"><div class="stat-label">Expenses</div><div class="stat-value negative">' + formatCurrency(data.totalExpenses) + '</div></div>' +
+            '<div class="stat-card"><div class="stat-label">Balance</div><div class="stat-value ' + (data.balance >= 0 ? 'positive' : 'negative') + '">' + formatCurrency(data.balance) + '</div></div>';
+
+        const expenses = data.categories.filter(c => c.type === 'Expense');
+        new Chart(document.getElementById('categoriesChart'), {
+            type: 'doughnut',
+            data: {
+                labels: expenses.map(c => c.category),
+                datasets: [{
+                    data: expenses.map(c => c.total),
+                    backgroundColor: expenses.map((c, i) => colors[i % colors.length]),
+                }],
+            },
+            options: {
+                maintainAspectRatio: false,
+                plugins: {
+                    legend: { position: 'right' },
+                    tooltip: { callbacks: { label: (c) => c.label + ': ' + formatCurrency(c.parsed) + ' (' + expenses[c.dataIndex].percent + '%)' } },
+                },
+            },
+        });
+    } catch (error) {
+        showError('yearStats', error);
+        showError('categoriesChart', error);
+    }
+}
+
+async function loadMerchants() {
+    try {
+        const data = await fetchJSON('/web/api/stats/merchants?year=' + year);
+        const container = document.getElementById('merchants');
+
+        if (data.merchants.length === 0) {
+            container.innerHTML = '<p>No expenses in ' + year + '.</p>';
+            return;
+        }
+
+        container.innerHTML = '<table class="merchants-table"><thead><tr><th>Description</th><th>Times</th><th class="amount">Total</th></tr></thead><tbody>' +
+            data.merchants.map(m => '<tr><td>' + escapeHtml(m.name) + '</td><td>' + m.count + '</td><td class="amount">' + formatCurrency(m.total) + '</td></tr>').join('') +
+            '</tbody></table>';
+    } catch (error) {
+        showError('merchants', error);
+    }
+}
+
+loadTrend();
+loadCategories();
+loadMerchants();
@@ -0,0 +1,412 @@
+// Format the change from the previous month, or from its first days for a month in progress;
+// for expenses a decrease is good
+function formatChange(delta, lowerIsBetter, comparedDays) {
+    if (delta.previous === 0 && delta.current === 0) return '';
+    const better = lowerIsBetter ? delta.change <= 0 : delta.change >= 0;
+    const sign = delta.change > 0 ? '+' : '';
+    const percent = delta.new ? 'new' : sign + delta.percent.toFixed(1) + '%';
+    return '<div class="stat-change ' + (better ? 'positive' : 'negative') + '">' +
+        sign + formatCurrency(delta.change) + ' (' + percent + ') vs ' +
+        (comparedDays ? 'the same days last month' : 'last month') + '</div>';
+}
+
+// Load statistics
+async function loadStats(month) {
+    try {
+        const response = await fetch('/web/api/stats?month=' + month);
+        const data = await response.json();
+
+        if (!response.ok) throw new Error(data.error || 'Failed to load stats');
+
+        const previous = data.comparison ? data.comparison.previousMonth : null;
+        const statsGrid = document.getElementById('statsGrid');
+        statsGrid.innerHTML = `
+            <div class="stat-card">
+                <div class="stat-label">Balance</div>
+                <div class="stat-value">${formatCurrency(data.balance)}</div>
+                ${previous ? formatChange(previous.balance, false, data.comparedDays) : ''}
+            </div>
+            <div class="stat-card">
+                <div class="stat-label">Total Income</div>
+                <div class="stat-value income">${formatCurrency(data.totalIncome)}</div>
+                ${previous ? formatChange(previous.income, false, data.comparedDays) : ''}
+            </div>
+            <div class="stat-card">
+                <div class="stat-label">Total Expenses</div>
+                <div class="stat-value expense">${formatCurrency(data.totalExpenses)}</div>
+                ${previous ? formatChange(previous.expenses, true, data.comparedDays) : ''}
+            </div>
+            <div class="stat-card">
+                <div class="stat-label">Transactions</div>
+                <div class="stat-value">${data.totalTransactions}</div>
+            </div>
+        `;
+
+        const forecastLine = document.getElementById('forecastLine');
+        const forecast = data.forecast;
+        forecastLine.innerHTML = forecast ?
+            '🔮 <strong>Forecast:</strong> ' + formatCurrency(forecast.expenses) + ' expenses and a balance of ' +
+            '<span class="' + (forecast.balance >= 0 ? 'positive' : 'negative') + '">' + formatCurrency(forecast.balance) + '</span> by the end of the month ' +
+            '<span class="forecast-range">(expenses between ' + formatCurrency(forecast.expensesLow) + ' and ' + formatCurrency(forecast.expensesHigh) +
+            ', ' + forecast.daysLeft + ' days left)</span>' : '';
+    } catch (error) {
+        document.getElementById('statsGrid').innerHTML =
+            '<div class="error">Failed to load statistics: ' + error.message + '</div>';
+    }
+}
+
+let transactionsData = [];
+let nextCursor = null;
+let currentView = 'list';
+
+// Load transactions
+// Build the transactions query from the month and the filters
+function transactionParams(month) {
+    const params = new URLSearchParams({ month: month, limit: 200 });
+    const filters = {
+        q: document.getElementById('filterQuery').value.trim(),
+        type: document.getElementById('filterType').value,
+        category: document.getElementById('filterCategory').value,
+        min: document.getElementById('filterMin').value,
+        max: document.getElementById('filterMax').value,
+    };
+    for (const [key, value] of Object.entries(filters)) {
+        if (value) params.set(key, value);
+    }
+
+    const [sort, order] = document.getElementById('filterSort').value.split(':');
+    params.set('sort', sort);
+    params.set('order', order);
+    return params;
+}
+
+// Load the first page of transactions, or the next one when appending
+async function loadTransactions(month, append) {
+    try {
+        const params = transactionParams(month);
+        if (append && nextCursor) params.set('cursor', nextCursor);
+
+        const response = await fetch('/web/api/transactions?' + params);
+        const data = await response.json();
+
+        if (!response.ok) throw new Error(data.error || 'Failed to load transactions');
+
+        transactionsData = append ? transactionsData.concat(data.transactions) : data.transactions;
+        nextCursor = data.nextCursor || null;
+        document.getElementById('loadMore').style.display = data.hasMore ? '' : 'none';
+        renderTransactions();
+
+    } catch (error) {
+        document.getElementById('transactionsContainer').innerHTML =
+            '<div class="error">Failed to load transactions: ' + error.message + '</div>';
+    }
+}
+
+// Render transactions based on the current view
+function renderTransactions() {
+    if (currentView === 'list') {
+        renderListView();
+    } else {
+        renderClusteredView();
+    }
+}
+
+// Render list view
+function renderListView() {
+    const container = document.getElementById('transactionsContainer');
+
+    if (transactionsData.length === 0) {
+        container.innerHTML = '<p>No transactions found for this month.</p>';
+        return;
+    }
+
+    const tableRows = transactionsData.map(tx =>`
+        <tr>
+            <td>${formatDate(tx.date)}</td>
+            <td>${tx.category}</td>
+            <td>${escapeHtml(tx.description) || '-'}</td>
+            <td class="amount ${tx.type.toLowerCase()}">${tx.type.toLowerCase() === 'income' ? '+' : '-'}${formatCurrency(Math.abs(tx.amount))}</td>
+            <td class="row-actions">
+                <button onclick="editTransaction(${tx.id})">Edit</button>
+                <button class="delete" onclick="deleteTransaction(${tx.id})">Delete</button>
+            </td>
+        </tr>
+    `).join('');
+
+    container.innerHTML =`
+        <table class="transactions-table">
+            <thead>
+                <tr>
+                    <th>Date</th>
+                    <th>Category</th>
+                    <th>Description</th>
+                    <th>Amount</th>
+                    <th></th>
+                </tr>
+            </thead>
+            <tbody>
+                ${tableRows}
+            </tbody>
+        </table>
+    `;
+}
+
+// Render clustered view
+function renderClusteredView() {
+    const container = document.getElementById('transactionsContainer');
+
+    if (transactionsData.length === 0) {
+        container.innerHTML = '<p>No transactions found for this month.</p>';
+        return;
+    }
+
+    const clusteredData = transactionsData.reduce((acc, tx) => {
+        const key = `${tx.type}-${tx.category}`;
+        if (!acc[key]) {
+            acc[key] = {
+                type: tx.type,
+                category: tx.category,
+                total: 0,
+                transactions: []
+            };
+        }
+        acc[key].total += tx.amount;
+        acc[key].transactions.push(tx);
+        return acc;
+    }, {});
+
+    const sortedClusters = Object.values(clusteredData).sort((a, b) => b.total - a.total);
+
+    container.innerHTML = sortedClusters.map(cluster =>`
+        <div class="cluster">
+            <div class="cluster-header">
+                <span class="cluster-title">${cluster.category} (${cluster.type})</span>
+                <span class="cluster-total ${cluster.type.toLowerCase()}">${cluster.type.toLowerCase() === 'income' ? '+' : '-'}${formatCurrency(Math.abs(cluster.total))}</span>
+            </div>
+        </div>
+    `).join('');
+}
+
+
+// Event Listeners for view toggle
+document.getElementById('listViewBtn').addEventListener('click', () => {
+    currentView = 'list';
+    document.getElementById('listViewBtn').classList.add('active');
+    document.getElementById('clusteredViewBtn').classList.remove('active');
+    renderTransactions();
+});
+
+document.getElementById('clusteredViewBtn').addEventListener('click', () => {
+    currentView = 'clustered';
+    document.getElementById('clusteredViewBtn').classList.add('active');
+    document.getElementById('listViewBtn').classList.remove('active');
+    renderTransactions();
+});
+
+// --- Create, edit and delete ---
+const categories = JSON.parse(document.getElementById('categories').textContent);
+
+function fillCategories(type, selected) {
+    document.getElementById('txCategory').innerHTML = categories[type]
+        .map(c => '<option value="' + c + '"' + (c === selected ? ' selected' : '') + '>' + c + '</option>')
+        .join('');
+}
+
+function resetForm() {
+    document.getElementById('transactionForm').reset();
+    document.getElementById('txId').value = '';
+    document.getElementById('txType').disabled = false;
+    document.getElementById('txDate').value = document.getElementById('txDate').max;
+    document.getElementById('formTitle').textContent = 'Add Transaction';
+    document.getElementById('txSubmit').textContent = 'Add';
+    document.getElementById('txCancel').style.display = 'none';
+    document.getElementById('formError').textContent = '';
+    fillCategories('Expense');
+}
+
+// Fill the form with the transaction understood from free text, to be checked and saved
+document.getElementById('quickAddForm').addEventListener('submit', async (e) => {
+    e.preventDefault();
+
+    const button = document.getElementById('quickAddSubmit');
+    button.disabled = true;
+    button.textContent = 'Thinking...';
+    try {
+        const tx = await sendJSON('POST', '/web/api/transactions/parse', {
+            text: document.getElementById('quickAddText').value,
+        });
+
+        resetForm();
+        document.getElementById('txType').value = tx.type;
+        fillCategories(tx.type, tx.category);
+        document.getElementById('txAmount').value = tx.amount;
+        document.getElementById('txDescription').value = tx.description;
+        document.getElementById('txDate').value = tx.date;
+        document.getElementById('formTitle').textContent = 'Confirm Transaction';
+        document.getElementById('txSubmit').textContent = 'Save';
+        document.getElementById('txCancel').style.display = '';
+        document.getElementById('quickAddText').value = '';
+    } catch (error) {
+        document.getElementById('formError').textContent = error.message;
+    } finally {
+        button.disabled = false;
+        button.textContent = 'Preview';
+    }
+});
+
+function editTransaction(id) {
+    const tx = transactionsData.find(t => t.id === id);
+    if (!tx) return;
+
+    document.getElementById('txId').value = tx.id;
+    document.getElementById('txType').value = tx.type;
+    // The type can't change, like in the bot
+    document.getElementById('txType').disabled = true;
+    fillCategories(tx.type, tx.category);
+    document.getElementById('txAmount').value = tx.amount;
+    document.getElementById('txDescription').value = tx.description;
+    document.getElementById('txDate').value = tx.date.substring(0, 10);
+    document.getElementById('formTitle').textContent = 'Edit Transaction';
+    document.getElementById('txSubmit').textContent = 'Save';
+    document.getElementById('txCancel').style.display = '';
+    document.getElementById('formError').textContent = '';
+    document.getElementById('transactionForm').scrollIntoView({ behavior: 'smooth' });
+}
+
+async function deleteTransaction(id) {
+    const tx = transactionsData.find(t => t.id === id);
+    if (!tx || !confirm('Delete ' + tx.category + ' ' + formatCurrency(tx.amount) + '?')) return;
+
+    try {
+        await sendJSON('DELETE', '/web/api/transactions/' + id);
+        reload();
+    } catch (error) {
+        alert('Failed to delete transaction: ' + error.message);
+    }
+}
+
+function reload() {
+    loadStats(currentMonth);
+    loadTransactions(currentMonth);
+}
+
+document.getElementById('txType').addEventListener('change', (e) => fillCategories(e.target.value));
+document.getElementById('txCancel').addEventListener('click', resetForm);
+
+document.getElementById('transactionForm').addEventListener('submit', async (e) => {
+    e.preventDefault();
+
+    const id = document.getElementById('txId').value;
+    const body = {
+        type: document.getElementById('txType').value,
+        category: document.getElementById('txCategory').value,
+        amount: parseFloat(document.getElementById('txAmount').value),
+        description: document.getElementById('txDescription').value,
+        date: document.getElementById('txDate').value,
+    };
+
+    try {
+        if (id) {
+            await sendJSON('PUT', '/web/api/transactions/' + id, body);
+        } else {
+            await sendJSON('POST', '/web/api/transactions', body);
+        }
+        resetForm();
+        reload();
+    } catch (error) {
+        document.getElementById('formError').textContent = error.message;
+    }
+});
+
+// --- API tokens ---
+async function loadTokens() {
+    const container = document.getElementById('tokensContainer');
+    try {
+        const response = await fetch('/web/api/tokens');
+        const data = await response.json();
+        if (!response.ok) throw new Error(data.error || 'Request failed');
+
+        if (data.tokens.length === 0) {
+            container.innerHTML = '<p>No API tokens yet.</p>';
+            return;
+        }
+
+        const rows = data.tokens.map(t => `
+            <tr>
+                <td>${escapeHtml(t.name)}</td>
+                <td><code>${escapeHtml(t.hint)}</code></td>
+                <td>${t.scope === 'read_write' ? 'Read-write' : 'Read-only'}</td>
+                <td>${formatDate(t.createdAt)}</td>
+                <td>${t.lastUsedAt ? formatDate(t.lastUsedAt) : 'Never'}</td>
+                <td class="row-actions"><button class="delete" onclick="revokeToken(${t.id})">Revoke</button></td>
+            </tr>
+        `).join('');
+
+        container.innerHTML = `
+            <table class="transactions-table">
+                <thead>
+                    <tr>
+                        <th>Name</th>
+                        <th>Token</th>
+                        <th>Access</th>
+                        <th>Created</th>
+                        <th>Last Used</th>
+                        <th></th>
+                    </tr>
+                </thead>
+                <tbody>${rows}</tbody>
+            </table>
+        `;
+    } catch (error) {
+        container.innerHTML = '<div class="error">Failed to load tokens</div>';
+    }
+}
+
+async function revokeToken(id) {
+    if (!confirm('Revoke this token? Scripts using it will stop working.')) return;
+
+    try {
+        await sendJSON('DELETE', '/web/api/tokens/' + id);
+        loadTokens();
+    } catch (error) {
+        alert('Failed to revoke token: ' + error.message);
+    }
+}
+
+document.getElementById('tokenForm').addEventListener('submit', async (e) => {
+    e.preventDefault();
+    document.getElementById('tokenError').textContent = '';
+
+    try {
+        const data = await sendJSON('POST', '/web/api/tokens', {
+            name: document.getElementById('tokenName').value,
+            scope: document.getElementById('tokenScope').value,
+        });
+        document.getElementById('tokenForm').reset();
+        document.getElementById('tokenSecretValue').textContent = data.token;
+        document.getElementById('tokenSecret').style.display = '';
+        loadTokens();
+    } catch (error) {
+        document.getElementById('tokenError').textContent = error.message;
+    }
+});
+
+// --- Filters ---
+document.getElementById('filterCategory').innerHTML += Object.values(categories).flat().sort()
+    .map(c => '<option value="' + c + '">' + c + '</option>')
+    .join('');
+
+let filterTimer;
+document.getElementById('filters').addEventListener('input', () => {
+    // Wait for the user to stop typing
+    clearTimeout(filterTimer);
+    filterTimer = setTimeout(() => loadTransactions(currentMonth), 300);
+});
+document.getElementById('loadMore').addEventListener('click', () => loadTransactions(currentMonth, true));
+
+// Load data on page load
+const currentMonth = document.getElementById('currentMonth').value;
+resetForm();
+loadStats(currentMonth);
+loadTransactions(currentMonth);
+loadTokens();
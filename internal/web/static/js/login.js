@@ -0,0 +1,118 @@
+const basePath = '/web';
+const loginForm = document.getElementById('loginForm');
+const verifyForm = document.getElementById('verifyForm');
+const loginSection = document.getElementById('loginSection');
+const verifySection = document.getElementById('verifySection');
+const messageDiv = document.getElementById('message');
+
+function showMessage(text, type) {
+    messageDiv.className = 'message ' + type;
+    messageDiv.textContent = text;
+}
+
+function remember() {
+    return document.getElementById('remember').checked;
+}
+
+// Create the session from data signed by Telegram, then go to the dashboard
+async function telegramLogin(url, options) {
+    try {
+        const response = await fetch(basePath + url, options);
+        const data = await response.json();
+
+        if (response.ok) {
+            showMessage('Login successful! Redirecting...', 'success');
+            window.location.href = data.redirect || basePath + '/dashboard';
+        } else {
+            showMessage(data.error || 'Telegram login failed', 'error');
+        }
+    } catch (error) {
+        showMessage('Network error. Please try again.', 'error');
+    }
+}
+
+// Called by the Telegram Login Widget
+function onTelegramAuth(user) {
+    // The widget data is signed as a whole, so the option goes in the URL
+    telegramLogin('/auth/telegram' + (remember() ? '?remember=true' : ''), {
+        method: 'POST',
+        body: new URLSearchParams(user),
+    });
+}
+
+// Opened as a Mini App inside Telegram: log in right away. The session is remembered so that
+// reopening the app doesn't log in again, with a new login message every time.
+const webApp = window.Telegram && window.Telegram.WebApp;
+if (webApp && webApp.initData) {
+    showMessage('Logging in with Telegram...', 'info');
+    telegramLogin('/auth/webapp', {
+        method: 'POST',
+        headers: {'Content-Type': 'application/json'},
+        body: JSON.stringify({initData: webApp.initData, remember: true}),
+    });
+}
+
+loginForm.addEventListener('submit', async (e) => {
+    e.preventDefault();
+    const username = document.getElementById('username').value.replace('@', '');
+    const submitBtn = document.getElementById('submitBtn');
+
+    submitBtn.disabled = true;
+    submitBtn.textContent = 'Sending...';
+
+    try {
+        const response = await fetch(basePath+'/auth/request', {
+            method: 'POST',
+            headers: {'Content-Type': 'application/json'},
+            body: JSON.stringify({username})
+        });
+
+        const data = await response.json();
+
+        if (response.ok) {
+            loginSection.style.display = 'none';
+            verifySection.style.display = 'block';
+            showMessage('Verification code sent to your Telegram!', 'success');
+        } else {
+            showMessage(data.error || 'Failed to send code', 'error');
+        }
+    } catch (error) {
+        showMessage('Network error. Please try again.', 'error');
+    } finally {
+        submitBtn.disabled = false;
+        submitBtn.textContent = 'Send Login Code';
+    }
+});
+
+verifyForm.addEventListener('submit', async (e) => {
+    e.preventDefault();
+    const code = document.getElementById('code').value;
+    const verifyBtn = document.getElementById('verifyBtn');
+
+    verifyBtn.disabled = true;
+    verifyBtn.textContent = 'Verifying...';
+
+    try {
+        const response = await fetch(basePath+'/auth/verify', {
+            method: 'POST',
+            headers: {'Content-Type': 'application/json'},
+            body: JSON.stringify({code, remember: remember()})
+        });
+
+        const data = await response.json();
+
+        if (response.ok) {
+            showMessage('Login successful! Redirecting...', 'success');
+            setTimeout(() => {
+                window.location.href = basePath+'/dashboard';
+            }, 1000);
+        } else {
+            showMessage(data.error || 'Invalid code', 'error');
+        }
+    } catch (error) {
+        showMessage('Network error. Please try again.', 'error');
+    } finally {
+        verifyBtn.disabled = false;
+        verifyBtn.textContent = 'Verify';
+    }
+});
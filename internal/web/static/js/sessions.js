@@ -0,0 +1,76 @@
+// Format a timestamp with the time of day
+function formatDateTime(dateString) {
+    return new Date(dateString).toLocaleString('en-US', {
+        month: 'short',
+        day: 'numeric',
+        year: 'numeric',
+        hour: '2-digit',
+        minute: '2-digit',
+    });
+}
+
+async function loadSessions() {
+    const container = document.getElementById('sessionsContainer');
+    try {
+        const response = await fetch('/web/api/sessions');
+        const data = await response.json();
+        if (!response.ok) throw new Error(data.error || 'Request failed');
+
+        const rows = data.sessions.map(s => `
+            <tr>
+                <td>${escapeHtml(s.device)}${s.current ? ' <span class="current-session">This device</span>' : ''}</td>
+                <td>${escapeHtml(s.ipAddress)}</td>
+                <td>${formatDateTime(s.lastSeenAt)}</td>
+                <td>${formatDate(s.createdAt)}</td>
+                <td>${formatDate(s.expiresAt)}${s.remember ? ' (remembered)' : ''}</td>
+                <td>${s.current
+                    ? '<a href="/web/logout">Log out</a>'
+                    : `<button class="revoke-btn" onclick="revokeSession(${s.id})">Log out</button>`}</td>
+            </tr>
+        `).join('');
+
+        container.innerHTML = `
+            <table class="sessions-table">
+                <thead>
+                    <tr>
+                        <th>Device</th>
+                        <th>IP Address</th>
+                        <th>Last Active</th>
+                        <th>Logged In</th>
+                        <th>Expires</th>
+                        <th></th>
+                    </tr>
+                </thead>
+                <tbody>${rows}</tbody>
+            </table>
+        `;
+
+        document.getElementById('revokeOthers').disabled = data.sessions.length <= 1;
+    } catch (error) {
+        container.innerHTML = '<div class="error">Failed to load sessions</div>';
+    }
+}
+
+async function revokeSession(id) {
+    if (!confirm('Log out this session?')) return;
+
+    try {
+        await sendJSON('DELETE', '/web/api/sessions/' + id);
+        loadSessions();
+    } catch (error) {
+        alert('Failed to log out session: ' + error.message);
+    }
+}
+
+document.getElementById('revokeOthers').addEventListener('click', async () => {
+    if (!confirm('Log out all the other sessions?')) return;
+
+    try {
+        await sendJSON('DELETE', '/web/api/sessions');
+        loadSessions();
+    } catch (error) {
+        alert('Failed to log out sessions: ' + error.message);
+    }
+});
+
+loadSessions();
@@ -0,0 +1,10 @@
+// Opened as a Mini App inside Telegram: use the chat theme and a compact layout.
+// telegram-web-app.js exposes the theme as --tg-theme-* CSS variables, see telegram.css.
+(function () {
+    const webApp = window.Telegram && window.Telegram.WebApp;
+    if (!webApp || !webApp.initData) return;
+
+    document.documentElement.classList.add('tg-app');
+    webApp.ready();
+    webApp.expand();
+})();
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"bytes"
+	"cashout/internal/model"
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+//go:embed static
+var staticFS embed.FS
+
+// pages are the templates rendered inside the shared layout, by name
+var pages = []string{"login", "dashboard", "analytics", "sessions"}
+
+// page holds the data every page gives to the layout. Page data structs embed it.
+type page struct {
+	// Name of the page, to highlight it in the navigation
+	Name string
+	// BodyClass styles the whole page, e.g. to center the login form
+	BodyClass string
+	// User is the logged in user, the navigation is only shown when set
+	User *model.User
+}
+
+// parseTemplates parses each page along with the layout
+func parseTemplates() (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template, len(pages))
+	for _, name := range pages {
+		t, err := template.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+		}
+		templates[name] = t
+	}
+	return templates, nil
+}
+
+// render executes a page template. The page is buffered so that a failing template doesn't send half a page.
+func (s *Server) render(w http.ResponseWriter, name string, data any) {
+	t, ok := s.templates[name]
+	if !ok {
+		s.logger.Errorf("Unknown template %s", name)
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, "layout", data); err != nil {
+		s.logger.Errorf("Failed to render %s template: %v", name, err)
+		http.Error(w, "Template error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		s.logger.Errorf("Failed to send %s page: %v", name, err)
+	}
+}
+
+// staticHandler serves the embedded stylesheets and scripts
+func staticHandler() http.Handler {
+	static, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		// The directory is embedded, so this can't happen
+		panic(err)
+	}
+	return http.FileServerFS(static)
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"cashout/internal/client"
+	"cashout/internal/model"
+	"cashout/internal/repository"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// apiTokenResponse is the JSON representation of an API token, without the secret
+type apiTokenResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Hint       string     `json:"hint"`
+	Scope      string     `json:"scope"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func newAPITokenResponse(t model.APIToken) apiTokenResponse {
+	return apiTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Hint:       t.Hint,
+		Scope:      string(t.Scope),
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// handleAPITokens lists the API tokens of the logged user
+func (s *Server) handleAPITokens(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := s.repositories.APITokens.GetUserTokens(user.TgID)
+	if err != nil {
+		s.logger.Errorf("Failed to get API tokens: %v", err)
+		s.sendJSONError(w, "Failed to get tokens", http.StatusInternalServerError)
+		return
+	}
+
+	tokenResponses := make([]apiTokenResponse, len(tokens))
+	for i, t := range tokens {
+		tokenResponses[i] = newAPITokenResponse(t)
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"tokens": tokenResponses,
+	})
+}
+
+// handleAPICreateToken creates an API token for the logged user, returning the secret once
+func (s *Server) handleAPICreateToken(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Name  string `json:"name"`
+		Scope string `json:"scope"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendJSONError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	scope, err := model.ParseAPITokenScope(req.Scope)
+	if err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	secret, token, err := s.repositories.APITokens.Create(user.TgID, req.Name, scope)
+	if err != nil {
+		if errors.Is(err, repository.ErrEmptyAPITokenName) || errors.Is(err, repository.ErrTooManyAPITokens) {
+			s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.logger.Errorf("Failed to create API token: %v", err)
+		s.sendJSONError(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"token":    secret,
+		"apiToken": newAPITokenResponse(*token),
+	})
+}
+
+// handleAPIRevokeToken revokes an API token of the logged user
+func (s *Server) handleAPIRevokeToken(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.sendJSONError(w, "Invalid token ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repositories.APITokens.Revoke(id, user.TgID); err != nil {
+		s.sendJSONError(w, "Token not found", http.StatusNotFound)
+		return
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"message": "Token revoked",
+	})
+}
@@ -0,0 +1,248 @@
+package web
+
+import (
+	"cashout/internal/client"
+	"cashout/internal/model"
+	"cashout/internal/utils"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// transactionResponse is the JSON representation of a transaction
+type transactionResponse struct {
+	ID          int64     `json:"id"`
+	Date        time.Time `json:"date"`
+	Category    string    `json:"category"`
+	Description string    `json:"description"`
+	Amount      float64   `json:"amount"`
+	Type        string    `json:"type"`
+}
+
+func newTransactionResponse(tx model.Transaction) transactionResponse {
+	return transactionResponse{
+		ID:          tx.ID,
+		Date:        tx.Date,
+		Category:    string(tx.Category),
+		Description: tx.Description,
+		Amount:      tx.Amount,
+		Type:        string(tx.Type),
+	}
+}
+
+// categoriesByType groups the transaction categories by the type they belong to
+func categoriesByType() map[model.TransactionType][]string {
+	categories := make(map[model.TransactionType][]string)
+	for _, c := range model.GetTransactionCategories() {
+		t := model.TypeExpense
+		if model.IsIncomeCategory(model.TransactionCategory(c)) {
+			t = model.TypeIncome
+		}
+		categories[t] = append(categories[t], c)
+	}
+	return categories
+}
+
+// transactionRequest is the body of the create and update endpoints.
+// Type can only be set on creation, like in the Telegram flows.
+type transactionRequest struct {
+	Type        string  `json:"type"`
+	Category    string  `json:"category"`
+	Amount      float64 `json:"amount"`
+	Description string  `json:"description"`
+	Date        string  `json:"date"`
+}
+
+// apply copies the request fields onto the transaction
+func (req transactionRequest) apply(tx *model.Transaction) error {
+	date, err := time.Parse(dateLayout, req.Date)
+	if err != nil {
+		return errors.New("invalid date, expected YYYY-MM-DD")
+	}
+
+	tx.Date = date
+	tx.Category = model.TransactionCategory(req.Category)
+	tx.Amount = req.Amount
+	tx.Description = strings.TrimSpace(req.Description)
+	return nil
+}
+
+// handleAPICreateTransaction creates a transaction for the logged user
+func (s *Server) handleAPICreateTransaction(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req transactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendJSONError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	tx := model.Transaction{
+		TgID:     user.TgID,
+		Type:     model.TransactionType(req.Type),
+		Currency: model.CurrencyEUR,
+	}
+	if err := req.apply(&tx); err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := tx.Validate(user.Today()); err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repositories.Transactions.Add(&tx); err != nil {
+		s.logger.Errorf("Failed to create transaction: %v", err)
+		s.sendJSONError(w, "Failed to create transaction", http.StatusInternalServerError)
+		return
+	}
+
+	s.sendJSONSuccess(w, newTransactionResponse(tx))
+}
+
+// maxQuickAddLength caps the free text sent to the LLM
+const maxQuickAddLength = 500
+
+// handleAPIParseTransaction extracts a transaction from free text with the LLM, like the bot does for
+// messages, and returns it as a preview to be confirmed through the create endpoint. Nothing is saved.
+func (s *Server) handleAPIParseTransaction(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendJSONError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	text := strings.TrimSpace(req.Text)
+	if len(text) > maxQuickAddLength {
+		s.sendJSONError(w, "Text is too long", http.StatusBadRequest)
+		return
+	}
+
+	transactionType, ok := utils.GuessTransactionType(text)
+	if !ok {
+		s.sendJSONError(w, "Include an amount, e.g. \"12.50 pizza yesterday\"", http.StatusBadRequest)
+		return
+	}
+
+	extracted, err := s.llm.ExtractTransaction(text, transactionType, user.Today())
+	if err != nil || extracted.Amount == 0 {
+		if err != nil {
+			s.logger.Errorf("Failed to extract transaction: %v", err)
+		}
+		s.sendJSONError(w, "I couldn't understand your transaction", http.StatusUnprocessableEntity)
+		return
+	}
+
+	date := extracted.Date
+	if date.IsZero() {
+		date = user.Today()
+	}
+
+	s.sendJSONSuccess(w, transactionRequest{
+		Type:        string(extracted.Type),
+		Category:    extracted.Category,
+		Amount:      extracted.Amount,
+		Description: extracted.Description,
+		Date:        date.Format(dateLayout),
+	})
+}
+
+// handleAPIUpdateTransaction edits a transaction of the logged user
+func (s *Server) handleAPIUpdateTransaction(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tx, ok := s.getOwnedTransaction(w, r, user.TgID)
+	if !ok {
+		return
+	}
+
+	var req transactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.sendJSONError(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if req.Type != "" && req.Type != string(tx.Type) {
+		s.sendJSONError(w, model.ErrCategoryTypeMismatch.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := req.apply(&tx); err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := tx.Validate(user.Today()); err != nil {
+		s.sendJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.repositories.Transactions.Update(&tx); err != nil {
+		s.logger.Errorf("Failed to update transaction %d: %v", tx.ID, err)
+		s.sendJSONError(w, "Failed to update transaction", http.StatusInternalServerError)
+		return
+	}
+
+	s.sendJSONSuccess(w, newTransactionResponse(tx))
+}
+
+// handleAPIDeleteTransaction deletes a transaction of the logged user
+func (s *Server) handleAPIDeleteTransaction(w http.ResponseWriter, r *http.Request) {
+	user := client.GetUserFromContext(r.Context())
+	if user == nil {
+		s.sendJSONError(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tx, ok := s.getOwnedTransaction(w, r, user.TgID)
+	if !ok {
+		return
+	}
+
+	if err := s.repositories.Transactions.Delete(tx.ID, user.TgID); err != nil {
+		s.logger.Errorf("Failed to delete transaction %d: %v", tx.ID, err)
+		s.sendJSONError(w, "Failed to delete transaction", http.StatusInternalServerError)
+		return
+	}
+
+	s.sendJSONSuccess(w, map[string]interface{}{
+		"message": "Transaction deleted",
+	})
+}
+
+// getOwnedTransaction loads the transaction in the {id} path parameter, answering with 404 if it
+// doesn't exist or belongs to someone else
+func (s *Server) getOwnedTransaction(w http.ResponseWriter, r *http.Request, tgID int64) (model.Transaction, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		s.sendJSONError(w, "Invalid transaction ID", http.StatusBadRequest)
+		return model.Transaction{}, false
+	}
+
+	tx, err := s.repositories.Transactions.GetByID(id)
+	if err != nil || tx.TgID != tgID {
+		s.sendJSONError(w, "Transaction not found", http.StatusNotFound)
+		return model.Transaction{}, false
+	}
+
+	return tx, true
+}
@@ -9,20 +9,49 @@ const (
 func Router(s *Server) http.Handler {
 	mux := http.NewServeMux()
 
-	// Serve static files
-	mux.Handle(basePath+"/static/", http.StripPrefix(basePath+"/static/", http.FileServer(http.Dir("./web/static"))))
+	// Serve static files, embedded in the binary
+	mux.Handle("GET "+basePath+"/static/", http.StripPrefix(basePath+"/static/", staticHandler()))
 
 	// Auth routes
 	mux.HandleFunc(basePath+"/", s.handleHome)
 	mux.HandleFunc(basePath+"/login", s.handleLogin)
 	mux.HandleFunc(basePath+"/auth/request", s.handleAuthRequest)
 	mux.HandleFunc(basePath+"/auth/verify", s.rateLimit(s.handleAuthVerify))
+	mux.HandleFunc("POST "+basePath+"/auth/telegram", s.rateLimit(s.handleAuthTelegram))
+	mux.HandleFunc("POST "+basePath+"/auth/webapp", s.rateLimit(s.handleAuthWebApp))
 	mux.HandleFunc(basePath+"/logout", s.handleLogout)
 
 	// Dashboard routes (protected)
-	mux.HandleFunc(basePath+"/dashboard", s.requireAuth(s.handleDashboard))
+	mux.HandleFunc(basePath+"/dashboard", s.requireAuth(s.rotateSession(s.handleDashboard)))
 	mux.HandleFunc(basePath+"/api/transactions", s.requireAuth(s.handleAPITransactions))
+	mux.HandleFunc("POST "+basePath+"/api/transactions", s.requireAuth(s.requireCSRF(s.handleAPICreateTransaction)))
+	mux.HandleFunc("POST "+basePath+"/api/transactions/parse", s.requireAuth(s.requireCSRF(s.handleAPIParseTransaction)))
+	mux.HandleFunc("PUT "+basePath+"/api/transactions/{id}", s.requireAuth(s.requireCSRF(s.handleAPIUpdateTransaction)))
+	mux.HandleFunc("DELETE "+basePath+"/api/transactions/{id}", s.requireAuth(s.requireCSRF(s.handleAPIDeleteTransaction)))
 	mux.HandleFunc(basePath+"/api/stats", s.requireAuth(s.handleAPIStats))
+	mux.HandleFunc(basePath+"/statement", s.requireAuth(s.handleStatement))
+	mux.HandleFunc("GET "+basePath+"/analytics", s.requireAuth(s.rotateSession(s.handleAnalytics)))
+	mux.HandleFunc("GET "+basePath+"/api/stats/trend", s.requireAuth(s.handleAPIStatsTrend))
+	mux.HandleFunc("GET "+basePath+"/api/stats/categories", s.requireAuth(s.handleAPIStatsCategories))
+	mux.HandleFunc("GET "+basePath+"/api/stats/merchants", s.requireAuth(s.handleAPIStatsMerchants))
+	mux.HandleFunc("GET "+basePath+"/api/tokens", s.requireAuth(s.handleAPITokens))
+	mux.HandleFunc("POST "+basePath+"/api/tokens", s.requireAuth(s.requireCSRF(s.handleAPICreateToken)))
+	mux.HandleFunc("DELETE "+basePath+"/api/tokens/{id}", s.requireAuth(s.requireCSRF(s.handleAPIRevokeToken)))
+	mux.HandleFunc("GET "+basePath+"/sessions", s.requireAuth(s.rotateSession(s.handleSessions)))
+	mux.HandleFunc("GET "+basePath+"/api/sessions", s.requireAuth(s.handleAPISessions))
+	mux.HandleFunc("DELETE "+basePath+"/api/sessions", s.requireAuth(s.requireCSRF(s.handleAPIRevokeOtherSessions)))
+	mux.HandleFunc("DELETE "+basePath+"/api/sessions/{id}", s.requireAuth(s.requireCSRF(s.handleAPIRevokeSession)))
+
+	// Public API (personal access tokens)
+	mux.HandleFunc("GET "+apiV1Path+"/openapi.yaml", s.handleOpenAPISpec)
+	mux.HandleFunc("GET "+apiV1Path+"/transactions", s.requireAPIToken(s.handleAPIV1Transactions))
+	mux.HandleFunc("POST "+apiV1Path+"/transactions", s.requireAPIToken(s.handleAPICreateTransaction))
+	mux.HandleFunc("GET "+apiV1Path+"/transactions/{id}", s.requireAPIToken(s.handleAPIV1Transaction))
+	mux.HandleFunc("PUT "+apiV1Path+"/transactions/{id}", s.requireAPIToken(s.handleAPIUpdateTransaction))
+	mux.HandleFunc("DELETE "+apiV1Path+"/transactions/{id}", s.requireAPIToken(s.handleAPIDeleteTransaction))
+	mux.HandleFunc("GET "+apiV1Path+"/stats", s.requireAPIToken(s.handleAPIStats))
+	mux.HandleFunc("GET "+apiV1Path+"/categories", s.requireAPIToken(s.handleAPIV1Categories))
+	mux.HandleFunc("GET "+apiV1Path+"/export", s.requireAPIToken(s.handleAPIV1Export))
 
 	return s.loggingMiddleware(mux)
 }